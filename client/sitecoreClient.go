@@ -8,10 +8,14 @@ import (
 	"strings"
 	"time"
 
+	"github.com/guitarrich/content-sdk-go/config"
 	"github.com/guitarrich/content-sdk-go/debug"
+	"github.com/guitarrich/content-sdk-go/disconnected"
 	"github.com/guitarrich/content-sdk-go/graphql"
+	"github.com/guitarrich/content-sdk-go/i18n"
 	layoutservice "github.com/guitarrich/content-sdk-go/layoutService"
 	"github.com/guitarrich/content-sdk-go/models"
+	"github.com/guitarrich/content-sdk-go/site"
 )
 
 const (
@@ -24,22 +28,47 @@ const (
 
 // SitecoreClient provides access to Sitecore content and services
 type SitecoreClient struct {
-	layoutService   *layoutservice.LayoutService
-	httpClient      *http.Client
-	defaultSite     string
-	defaultLang     string
-	graphQLEndpoint string
-	graphQLAPIKey   string
+	layoutService     layoutservice.LayoutFetcher
+	dictionaryService i18n.DictionaryService
+	httpClient        *http.Client
+	defaultSite       string
+	defaultLang       string
+	graphQLEndpoint   string
+	graphQLAPIKey     string
+	graphQLClient     graphql.Client
+	routeLister       RouteLister
+	siteInfoService   site.SiteInfoService
+	store             *disconnected.Store
+}
+
+// RouteLister lists the routes of sites and languages; disconnected.RouteService
+// implements it
+type RouteLister interface {
+	ListRoutes(ctx context.Context, sites []string, languages []string) ([]models.StaticPath, error)
 }
 
 // ClientConfig contains configuration for the Sitecore client
 type ClientConfig struct {
-	LayoutService   *layoutservice.LayoutService
+	// LayoutService fetches layout data in GetPage, from GraphQL or from
+	// local files in disconnected mode
+	LayoutService   layoutservice.LayoutFetcher
 	HTTPClient      *http.Client
 	DefaultSite     string
 	DefaultLanguage string
 	GraphQLEndpoint string
 	GraphQLAPIKey   string
+
+	// DictionaryService fetches the page dictionary in GetPage (optional)
+	DictionaryService i18n.DictionaryService
+
+	// GraphQLClient lists routes in GetStaticPaths (default: a client for GraphQLEndpoint)
+	GraphQLClient graphql.Client
+
+	// RouteLister lists routes in GetStaticPaths instead of GraphQL (optional)
+	RouteLister RouteLister
+
+	// SiteInfoService fetches site information, e.g. for a dynamic site resolver (optional)
+	SiteInfoService site.SiteInfoService
 }
 
 // NewSitecoreClient creates a new Sitecore client
@@ -60,17 +89,84 @@ func NewSitecoreClient(config ClientConfig) *SitecoreClient {
 	}
 
 	return &SitecoreClient{
-		layoutService:   config.LayoutService,
-		httpClient:      httpClient,
-		defaultSite:     defaultSite,
-		defaultLang:     defaultLang,
-		graphQLEndpoint: config.GraphQLEndpoint,
-		graphQLAPIKey:   config.GraphQLAPIKey,
+		layoutService:     config.LayoutService,
+		dictionaryService: config.DictionaryService,
+		httpClient:        httpClient,
+		defaultSite:       defaultSite,
+		defaultLang:       defaultLang,
+		graphQLEndpoint:   config.GraphQLEndpoint,
+		graphQLAPIKey:     config.GraphQLAPIKey,
+		graphQLClient:     config.GraphQLClient,
+		routeLister:       config.RouteLister,
+		siteInfoService:   config.SiteInfoService,
 	}
 }
 
+// NewSitecoreClientFromConfig creates a Sitecore client for the API selected
+// by the configuration. With API.UseDisconnected, layout and dictionary data
+// routes, sites, layout and dictionary data are read from the files in
+// API.Disconnected.DataDir instead of Sitecore; DisconnectedStore returns the store.
+func NewSitecoreClientFromConfig(cfg *config.Config) *SitecoreClient {
+	clientConfig := ClientConfig{
+		DefaultSite:     cfg.DefaultSite,
+		DefaultLanguage: cfg.DefaultLanguage,
+		GraphQLEndpoint: cfg.GetGraphQLEndpoint(),
+		GraphQLAPIKey:   cfg.GetAPIKey(),
+	}
+
+	if cfg.API.UseDisconnected {
+		debug.Common("using disconnected content from %s", cfg.API.Disconnected.DataDir)
+		store := disconnected.NewStore(disconnected.Config{DataDir: cfg.API.Disconnected.DataDir})
+		clientConfig.LayoutService = disconnected.NewLayoutService(store)
+		clientConfig.DictionaryService = disconnected.NewDictionaryService(store)
+		clientConfig.RouteLister = disconnected.NewRouteService(store)
+		clientConfig.SiteInfoService = disconnected.NewSiteInfoService(store)
+		client := NewSitecoreClient(clientConfig)
+		client.store = store
+		return client
+	}
+
+	graphQLConfig := graphql.DefaultClientConfig()
+	if cfg.EdgeTimeout > 0 {
+		graphQLConfig.Timeout = cfg.EdgeTimeout
+	}
+	graphQLClient := graphql.NewClient(clientConfig.GraphQLEndpoint, clientConfig.GraphQLAPIKey, nil, graphQLConfig)
+
+	clientConfig.GraphQLClient = graphQLClient
+	clientConfig.LayoutService = layoutservice.NewLayoutServiceWithClient(layoutservice.LayoutServiceConfig{
+		GraphQLServiceConfig: layoutservice.GraphQLServiceConfig{
+			Endpoint: clientConfig.GraphQLEndpoint,
+			APIKey:   clientConfig.GraphQLAPIKey,
+		},
+	}, graphQLClient)
+	clientConfig.DictionaryService = i18n.NewDictionaryService(i18n.DictionaryServiceConfig{
+		GraphQLClient: graphQLClient,
+		SiteName:      cfg.DefaultSite,
+	})
+	clientConfig.SiteInfoService = site.NewSiteInfoService(site.SiteInfoServiceConfig{GraphQLClient: graphQLClient})
+	return NewSitecoreClient(clientConfig)
+}
+
+// SiteInfoService returns the service fetching site information, or nil when
+// none is configured
+func (c *SitecoreClient) SiteInfoService() site.SiteInfoService {
+	return c.siteInfoService
+}
+
+// DisconnectedStore returns the store of disconnected content, or nil unless the
+// client reads disconnected content. Start it to be notified of file changes.
+func (c *SitecoreClient) DisconnectedStore() *disconnected.Store {
+	return c.store
+}
+
 // GetPage fetches a page from Sitecore
 func (c *SitecoreClient) GetPage(path string, options models.PageOptions) (*models.Page, error) {
+	return c.GetPageContext(context.Background(), path, options)
+}
+
+// GetPageContext fetches a page from Sitecore. The context is used for
+// cancellation and tracing of the layout and dictionary fetches.
+func (c *SitecoreClient) GetPageContext(ctx context.Context, path string, options models.PageOptions) (*models.Page, error) {
 	// Parse and normalize the path
 	normalizedPath := c.ParsePath(path)
 
@@ -89,7 +185,7 @@ func (c *SitecoreClient) GetPage(path string, options models.PageOptions) (*mode
 	}
 
 	// Fetch layout data
-	layoutData, err := c.layoutService.FetchLayoutData(normalizedPath, layoutservice.RouteOptions{
+	layoutData, err := c.layoutService.FetchLayoutDataContext(ctx, normalizedPath, layoutservice.RouteOptions{
 		Site:   site,
 		Locale: locale,
 	}, nil)
@@ -106,12 +202,38 @@ func (c *SitecoreClient) GetPage(path string, options models.PageOptions) (*mode
 		}
 	}
 
+	// The served language differs from the requested one when language fallback was used
+	servedLanguage := *locale
+	if language := layoutData.Sitecore.Context.Language; language != nil && *language != "" {
+		servedLanguage = *language
+	}
+	if servedLanguage != *locale {
+		debug.Layout("served %s in fallback language %s instead of %s", normalizedPath, servedLanguage, *locale)
+	}
+
 	// Build page response
 	page := &models.Page{
-		LayoutData: layoutData,
-		Dictionary: make(models.DictionaryPhrases), // TODO: Fetch dictionary
-		ErrorPages: nil,                            // TODO: Fetch error pages
-		HeadLinks:  []models.HTMLLink{},            // TODO: Build head links
+		LayoutData:        layoutData,
+		Dictionary:        make(models.DictionaryPhrases),
+		ErrorPages:        nil,                 // TODO: Fetch error pages
+		HeadLinks:         []models.HTMLLink{}, // TODO: Build head links
+		Path:              normalizedPath,
+		Language:          servedLanguage,
+		RequestedLanguage: *locale,
+		Site:              site,
+	}
+	if itemID := layoutData.Sitecore.Route.ItemID; itemID != nil {
+		page.ItemID = *itemID
+	}
+
+	// Fetch dictionary in the requested language; the dictionary service applies its own fallback
+	if c.dictionaryService != nil {
+		dictionary, err := c.dictionaryService.FetchDictionaryData(ctx, *locale, site)
+		if err != nil {
+			debug.Dictionary("failed to fetch dictionary for %s: %v", site, err)
+		} else {
+			page.Dictionary = dictionary
+		}
 	}
 
 	return page, nil
@@ -199,23 +321,12 @@ func (c *SitecoreClient) GetPreview(previewData models.PreviewData) (*models.Pag
 
 	// Create page response
 	page := &models.Page{
-		LayoutData: layoutData,
-		Language:   previewData.Language,
-		Site:       previewData.Site,
-		ItemID:     previewData.ItemID,
-		Path:       previewData.Route,
-		EditingContext: &models.EditingContext{
-			IsEditing: previewData.Mode == models.PreviewModeEdit || previewData.Mode == models.PreviewModeMetadata,
-			IsPreview: previewData.Mode == models.PreviewModePreview,
-			Mode:      models.PageModeEdit,
-			QueryParams: map[string]string{
-				"sc_itemid":     previewData.ItemID,
-				"sc_lang":       previewData.Language,
-				"sc_site":       previewData.Site,
-				"sc_layoutKind": string(previewData.LayoutKind),
-				"mode":          string(previewData.Mode),
-			},
-		},
+		LayoutData:     layoutData,
+		Language:       previewData.Language,
+		Site:           previewData.Site,
+		ItemID:         previewData.ItemID,
+		Path:           previewData.Route,
+		EditingContext: models.NewEditingContext(previewData),
 	}
 
 	debug.Editing("preview page fetched successfully")
@@ -231,9 +342,110 @@ func (c *SitecoreClient) GetDesignLibraryData(data models.DesignLibraryRenderPre
 
 // GetStaticPaths generates static paths for all pages in given sites and languages
 func (c *SitecoreClient) GetStaticPaths(sites []string, languages []string) ([]models.StaticPath, error) {
-	// TODO: Implement static path generation
-	// This requires querying all routes from Sitecore via GraphQL
-	return nil, fmt.Errorf("static path generation not yet implemented")
+	return c.GetStaticPathsContext(context.Background(), sites, languages)
+}
+
+// staticPathsQuery pages through the routes of a site in a language
+const staticPathsQuery = `
+	query StaticPathsQuery($site: String!, $language: String!, $pageSize: Int, $after: String) {
+		site {
+			siteInfo(site: $site) {
+				routes(language: $language, first: $pageSize, after: $after) {
+					pageInfo {
+						endCursor
+						hasNext
+					}
+					results {
+						path: routePath
+						route {
+							updated: field(name: "__Updated") {
+								value
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+`
+
+// GetStaticPathsContext lists the routes of the given sites and languages from
+// GraphQL, with their last modification time when Sitecore reports it
+func (c *SitecoreClient) GetStaticPathsContext(ctx context.Context, sites []string, languages []string) ([]models.StaticPath, error) {
+	if c.routeLister != nil {
+		return c.routeLister.ListRoutes(ctx, sites, languages)
+	}
+
+	graphQLClient := c.graphQLClient
+	if graphQLClient == nil {
+		graphQLClient = graphql.NewClient(c.graphQLEndpoint, c.graphQLAPIKey, c.httpClient, nil)
+	}
+
+	paths := []models.StaticPath{}
+	for _, site := range sites {
+		for _, language := range languages {
+			var after any
+			for {
+				result, err := graphQLClient.Request(ctx, staticPathsQuery, map[string]any{
+					"site":     site,
+					"language": language,
+					"pageSize": 100,
+					"after":    after,
+				})
+				if err != nil {
+					return nil, fmt.Errorf("failed to fetch routes for site %s, language %s: %w", site, language, err)
+				}
+
+				routes := nestedMap(result, "site", "siteInfo", "routes")
+				results, _ := routes["results"].([]any)
+				for _, item := range results {
+					route, ok := item.(map[string]any)
+					if !ok {
+						continue
+					}
+					path, _ := route["path"].(string)
+					staticPath := models.StaticPath{
+						Site:   site,
+						Locale: language,
+						Path:   strings.FieldsFunc(path, func(r rune) bool { return r == '/' }),
+					}
+					if updated, _ := nestedMap(route, "route", "updated")["value"].(string); updated != "" {
+						field := models.DateField{Value: updated}
+						if t, err := field.Time(); err == nil {
+							staticPath.LastModified = t
+						}
+					}
+					paths = append(paths, staticPath)
+				}
+
+				pageInfo := nestedMap(routes, "pageInfo")
+				if hasNext, _ := pageInfo["hasNext"].(bool); !hasNext {
+					break
+				}
+				// A missing or repeated cursor would request the same page forever
+				cursor, _ := pageInfo["endCursor"].(string)
+				if cursor == "" || cursor == after {
+					return nil, fmt.Errorf("failed to fetch routes for site %s, language %s: invalid end cursor %q", site, language, cursor)
+				}
+				after = cursor
+			}
+		}
+	}
+
+	debug.Layout("found %d static paths for sites=%v, languages=%v", len(paths), sites, languages)
+	return paths, nil
+}
+
+// nestedMap follows keys through nested maps, returning nil when a key is missing
+func nestedMap(data map[string]any, keys ...string) map[string]any {
+	for _, key := range keys {
+		next, ok := data[key].(map[string]any)
+		if !ok {
+			return nil
+		}
+		data = next
+	}
+	return data
 }
 
 // GetSiteNameFromPath extracts the site name from a path
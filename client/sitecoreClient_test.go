@@ -1,8 +1,18 @@
 package client
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/guitarrich/content-sdk-go/config"
+	"github.com/guitarrich/content-sdk-go/i18n"
+	layoutservice "github.com/guitarrich/content-sdk-go/layoutService"
+	"github.com/guitarrich/content-sdk-go/metrics"
 	"github.com/guitarrich/content-sdk-go/models"
 )
 
@@ -357,3 +367,246 @@ func TestSitecoreClient_ParsePath(t *testing.T) {
 		})
 	}
 }
+
+// fallbackGraphQLClient returns a route only for the given language
+type fallbackGraphQLClient struct {
+	language string
+	requests int
+}
+
+func (m *fallbackGraphQLClient) Request(ctx context.Context, query string, variables map[string]any) (map[string]any, error) {
+	m.requests++
+	if !strings.Contains(query, `language:"`+m.language+`"`) {
+		return map[string]any{"layout": map[string]any{"item": nil}}, nil
+	}
+	return map[string]any{
+		"layout": map[string]any{
+			"item": map[string]any{
+				"rendered": map[string]any{
+					"sitecore": map[string]any{
+						"context": map[string]any{"language": m.language},
+						"route":   map[string]any{"name": "home", "itemId": "{A1B2}"},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func TestSitecoreClient_GetPage_LanguageFallback(t *testing.T) {
+	layoutService := layoutservice.NewLayoutServiceWithClient(layoutservice.LayoutServiceConfig{
+		LanguageFallback: &i18n.LanguageFallback{DefaultLanguage: "en"},
+	}, &fallbackGraphQLClient{language: "fr"})
+
+	client := NewSitecoreClient(ClientConfig{
+		LayoutService: layoutService,
+		DefaultSite:   "mysite",
+	})
+
+	locale := "fr-CA"
+	page, err := client.GetPage("/", models.PageOptions{Site: "mysite", Locale: &locale})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if page.Language != "fr" {
+		t.Errorf("expected served language 'fr', got '%s'", page.Language)
+	}
+	if page.RequestedLanguage != "fr-CA" {
+		t.Errorf("expected requested language 'fr-CA', got '%s'", page.RequestedLanguage)
+	}
+	if page.ItemID != "{A1B2}" {
+		t.Errorf("expected item ID '{A1B2}', got '%s'", page.ItemID)
+	}
+
+	// Without a route in any language in the chain the page is not found
+	locale = "de"
+	if _, err := client.GetPage("/", models.PageOptions{Site: "mysite", Locale: &locale}); err == nil {
+		t.Error("expected not found error")
+	}
+}
+
+func TestSitecoreClient_GetPage_LayoutCache(t *testing.T) {
+	graphQLClient := &fallbackGraphQLClient{language: "en"}
+	layoutService := layoutservice.NewLayoutServiceWithClient(layoutservice.LayoutServiceConfig{
+		CacheTTL: time.Minute,
+	}, graphQLClient)
+	client := NewSitecoreClient(ClientConfig{
+		LayoutService: layoutService,
+		DefaultSite:   "mysite",
+	})
+
+	hits := metrics.CacheLookups.Value("layout", "hit")
+	misses := metrics.CacheLookups.Value("layout", "miss")
+
+	locale := "en"
+	for range 2 {
+		page, err := client.GetPage("/", models.PageOptions{Site: "mysite", Locale: &locale})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if page.ItemID != "{A1B2}" {
+			t.Errorf("expected item ID '{A1B2}', got '%s'", page.ItemID)
+		}
+		// Each caller gets its own copy of the cached layout
+		page.LayoutData.(*layoutservice.LayoutServiceData).Sitecore.Route.ItemID = nil
+	}
+
+	if graphQLClient.requests != 1 {
+		t.Errorf("expected 1 request, got %d", graphQLClient.requests)
+	}
+	if got := metrics.CacheLookups.Value("layout", "miss") - misses; got != 1 {
+		t.Errorf("expected 1 layout cache miss, got %v", got)
+	}
+	if got := metrics.CacheLookups.Value("layout", "hit") - hits; got != 1 {
+		t.Errorf("expected 1 layout cache hit, got %v", got)
+	}
+
+	// A config change drops the cache
+	layoutService.OnConfigChange(nil, nil)
+	if _, err := client.GetPage("/", models.PageOptions{Site: "mysite", Locale: &locale}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if graphQLClient.requests != 2 {
+		t.Errorf("expected a new request after a config change, got %d requests", graphQLClient.requests)
+	}
+}
+
+// routesGraphQLClient serves two pages of routes for each site and language.
+// With a cursor set, every page claims a next page at that cursor.
+type routesGraphQLClient struct {
+	requests int
+	cursor   any
+}
+
+func (m *routesGraphQLClient) Request(ctx context.Context, query string, variables map[string]any) (map[string]any, error) {
+	m.requests++
+	results := []any{
+		map[string]any{
+			"path":  "/",
+			"route": map[string]any{"updated": map[string]any{"value": "20240115T103000Z"}},
+		},
+	}
+	pageInfo := map[string]any{"hasNext": true, "endCursor": "page2"}
+	if m.cursor != nil {
+		pageInfo = map[string]any{"hasNext": true, "endCursor": m.cursor}
+	} else if variables["after"] == "page2" {
+		results = []any{map[string]any{"path": "/news/article", "route": nil}}
+		pageInfo = map[string]any{"hasNext": false}
+	}
+	return map[string]any{
+		"site": map[string]any{
+			"siteInfo": map[string]any{
+				"routes": map[string]any{"pageInfo": pageInfo, "results": results},
+			},
+		},
+	}, nil
+}
+
+func TestGetStaticPathsContext(t *testing.T) {
+	graphQLClient := &routesGraphQLClient{}
+	client := NewSitecoreClient(ClientConfig{GraphQLClient: graphQLClient})
+
+	paths, err := client.GetStaticPathsContext(context.Background(), []string{"mysite"}, []string{"en", "fr"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if graphQLClient.requests != 4 {
+		t.Errorf("expected 4 requests, got %d", graphQLClient.requests)
+	}
+	if len(paths) != 4 {
+		t.Fatalf("expected 4 paths, got %d", len(paths))
+	}
+	if paths[0].Site != "mysite" || paths[0].Locale != "en" || len(paths[0].Path) != 0 {
+		t.Errorf("unexpected home path: %+v", paths[0])
+	}
+	if paths[0].LastModified.IsZero() || paths[0].LastModified.Year() != 2024 {
+		t.Errorf("expected last modified in 2024, got %v", paths[0].LastModified)
+	}
+	if strings.Join(paths[1].Path, "/") != "news/article" || !paths[1].LastModified.IsZero() {
+		t.Errorf("unexpected article path: %+v", paths[1])
+	}
+	if paths[2].Locale != "fr" {
+		t.Errorf("expected fr paths after en, got %s", paths[2].Locale)
+	}
+}
+
+func TestGetStaticPathsContext_InvalidCursor(t *testing.T) {
+	for _, cursor := range []any{"", "page2"} {
+		graphQLClient := &routesGraphQLClient{cursor: cursor}
+		client := NewSitecoreClient(ClientConfig{GraphQLClient: graphQLClient})
+
+		// A repeated cursor stops after the second page, a missing one after the first
+		_, err := client.GetStaticPathsContext(context.Background(), []string{"mysite"}, []string{"en"})
+		if err == nil || !strings.Contains(err.Error(), "invalid end cursor") {
+			t.Errorf("cursor %q: expected invalid end cursor error, got %v", cursor, err)
+		}
+		if graphQLClient.requests > 2 {
+			t.Errorf("cursor %q: expected at most 2 requests, got %d", cursor, graphQLClient.requests)
+		}
+	}
+}
+
+func TestNewSitecoreClientFromConfig_Disconnected(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"routes/en/about/index.json": `{"sitecore": {"route": {"name": "about"}}}`,
+		"dictionary/en.json":         `{"greeting": "Hello"}`,
+		"sites.json":                 `[{"name": "mysite", "hostName": "localhost"}]`,
+	}
+	for name, content := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{
+		API: config.APIConfig{
+			UseDisconnected: true,
+			Disconnected:    config.DisconnectedAPIConfig{DataDir: dir},
+		},
+		DefaultSite:     "mysite",
+		DefaultLanguage: "en",
+	}
+	client := NewSitecoreClientFromConfig(cfg)
+
+	page, err := client.GetPage("/about", models.PageOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Dictionary["greeting"] != "Hello" {
+		t.Errorf("expected dictionary phrase 'Hello', got '%s'", page.Dictionary["greeting"])
+	}
+
+	_, err = client.GetPage("/missing", models.PageOptions{})
+	var notFound *models.NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+
+	// Routes and sites come from the data directory, not GraphQL
+	paths, err := client.GetStaticPaths([]string{"mysite"}, []string{"en"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(paths) != 1 || strings.Join(paths[0].Path, "/") != "about" || paths[0].Locale != "en" {
+		t.Errorf("expected the about route, got %+v", paths)
+	}
+
+	siteInfo, err := client.SiteInfoService().FetchSiteInfo(context.Background(), "mysite")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if siteInfo.HostName != "localhost" {
+		t.Errorf("expected host name 'localhost', got '%s'", siteInfo.HostName)
+	}
+
+	if client.DisconnectedStore() == nil {
+		t.Error("expected the disconnected store")
+	}
+}
@@ -0,0 +1,267 @@
+// Command content-sdk-go generates Go code from Sitecore templates.
+//
+// Usage:
+//
+//	content-sdk-go schema [-root path] [-o schema.json]
+//	content-sdk-go generate [-schema schema.json] [-root path] [-models dir] [-components dir]
+//	content-sdk-go scaffold component [-schema schema.json] [-models dir] [-components dir] <Name>
+//
+// Without -schema, templates are read from the GraphQL endpoint configured by
+// the SDK environment variables (SITECORE_API_HOST and SITECORE_API_KEY, or
+// the Edge variables with USE_EDGE_API=true).
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/guitarrich/content-sdk-go/codegen"
+	"github.com/guitarrich/content-sdk-go/config"
+	"github.com/guitarrich/content-sdk-go/graphql"
+)
+
+const usage = `Usage:
+  content-sdk-go schema [flags]                      dump templates to a JSON schema file
+  content-sdk-go generate [flags]                    generate field structs and component skeletons
+  content-sdk-go scaffold component [flags] <Name>   scaffold a templ component
+
+Run a command with -h for its flags.
+`
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "content-sdk-go:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// run dispatches a command
+func run(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return flag.ErrHelp
+	}
+
+	switch args[0] {
+	case "schema":
+		return runSchema(args[1:])
+	case "generate":
+		return runGenerate(args[1:])
+	case "scaffold":
+		if len(args) < 2 || args[1] != "component" {
+			return fmt.Errorf("usage: content-sdk-go scaffold component <Name>")
+		}
+		return runScaffold(args[2:])
+	case "help", "-h", "--help":
+		fmt.Fprint(os.Stdout, usage)
+		return nil
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+// sourceFlags are the flags selecting where templates are read from
+type sourceFlags struct {
+	schema   string
+	root     string
+	language string
+}
+
+// register adds the source flags to a flag set
+func (s *sourceFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&s.schema, "schema", "", "read templates from a JSON schema file instead of GraphQL")
+	fs.StringVar(&s.root, "root", "/sitecore/templates/Project", "template folder to read from GraphQL")
+	fs.StringVar(&s.language, "language", "en", "language of the template items")
+}
+
+// load reads the schema from the file or GraphQL
+func (s *sourceFlags) load() (*codegen.Schema, error) {
+	if s.schema != "" {
+		return codegen.LoadSchema(s.schema)
+	}
+
+	cfg := config.LoadConfig()
+	client := graphql.NewClient(cfg.GetGraphQLEndpoint(), cfg.GetAPIKey(), nil, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	return codegen.Introspect(ctx, codegen.IntrospectConfig{
+		Client:   client,
+		RootPath: s.root,
+		Language: s.language,
+	})
+}
+
+// runSchema dumps the templates to a JSON schema file
+func runSchema(args []string) error {
+	fs := flag.NewFlagSet("schema", flag.ContinueOnError)
+	var source sourceFlags
+	source.register(fs)
+	out := fs.String("o", "sitecore-schema.json", "output file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	schema, err := source.load()
+	if err != nil {
+		return err
+	}
+	if err := schema.Save(*out); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %d templates to %s\n", len(schema.Templates), *out)
+	return nil
+}
+
+// runGenerate generates the field structs, component skeletons and registry
+func runGenerate(args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	var source sourceFlags
+	source.register(fs)
+	modelsDir := fs.String("models", "models", "directory of the generated structs")
+	modelsFile := fs.String("models-file", "sitecore_gen.go", "file name of the generated structs")
+	componentsDir := fs.String("components", "components", "directory of the component skeletons")
+	skipComponents := fs.Bool("skip-components", false, "only generate the structs")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	schema, err := source.load()
+	if err != nil {
+		return err
+	}
+
+	modelsPackage := filepath.Base(*modelsDir)
+	structs, err := codegen.GenerateStructs(schema, codegen.StructOptions{Package: modelsPackage})
+	if err != nil {
+		return err
+	}
+	if err := writeFile(filepath.Join(*modelsDir, *modelsFile), structs, true); err != nil {
+		return err
+	}
+	if *skipComponents {
+		return nil
+	}
+
+	modelsImport, err := codegen.ImportPath(*modelsDir)
+	if err != nil {
+		return err
+	}
+	for i := range schema.Templates {
+		template := &schema.Templates[i]
+		if strings.HasPrefix(template.Name, "_") {
+			// Base templates only contribute fields
+			continue
+		}
+		if err := scaffold(template.Name, *componentsDir, modelsImport, schema.AllFields(template)); err != nil {
+			return err
+		}
+	}
+	return writeRegistry(*componentsDir)
+}
+
+// runScaffold scaffolds a single component
+func runScaffold(args []string) error {
+	fs := flag.NewFlagSet("scaffold component", flag.ContinueOnError)
+	schemaFile := fs.String("schema", "", "JSON schema file with the component's template")
+	modelsDir := fs.String("models", "models", "directory of the generated structs")
+	componentsDir := fs.String("components", "components", "directory of the component")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: content-sdk-go scaffold component [flags] <Name>")
+	}
+	name := fs.Arg(0)
+
+	var modelsImport string
+	var fields []codegen.TemplateField
+	if *schemaFile != "" {
+		schema, err := codegen.LoadSchema(*schemaFile)
+		if err != nil {
+			return err
+		}
+		template := schema.Template(name)
+		if template == nil {
+			return fmt.Errorf("template %s not found in %s", name, *schemaFile)
+		}
+		if modelsImport, err = codegen.ImportPath(*modelsDir); err != nil {
+			return err
+		}
+		fields = schema.AllFields(template)
+	}
+
+	if err := scaffold(name, *componentsDir, modelsImport, fields); err != nil {
+		return err
+	}
+	return writeRegistry(*componentsDir)
+}
+
+// scaffold writes a component skeleton unless the component file exists
+func scaffold(name, dir, modelsImport string, fields []codegen.TemplateField) error {
+	source, err := codegen.ScaffoldComponent(name, codegen.ComponentOptions{
+		Package:      filepath.Base(dir),
+		ModelsImport: modelsImport,
+		Fields:       fields,
+	})
+	if err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(dir, fileName(codegen.GoName(name))+".templ"), source, false)
+}
+
+// writeRegistry regenerates the component registry of a directory
+func writeRegistry(dir string) error {
+	source, err := codegen.GenerateRegistry(dir, filepath.Base(dir))
+	if err != nil {
+		return err
+	}
+	if err := writeFile(filepath.Join(dir, codegen.RegistryFile), source, true); err != nil {
+		return err
+	}
+	fmt.Println("run templ generate to compile new components")
+	return nil
+}
+
+// writeFile writes a generated file. Existing files are only replaced when
+// overwrite is set, so scaffolded components are never clobbered.
+func writeFile(path string, data []byte, overwrite bool) error {
+	if !overwrite {
+		if _, err := os.Stat(path); err == nil {
+			fmt.Printf("skipped %s (exists)\n", path)
+			return nil
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %s\n", path)
+	return nil
+}
+
+// fileName converts a component name to a file name ("HeroBanner" becomes "hero_banner")
+func fileName(name string) string {
+	var b []rune
+	for i, r := range name {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b = append(b, '_')
+			}
+			r += 'a' - 'A'
+		}
+		b = append(b, r)
+	}
+	return string(b)
+}
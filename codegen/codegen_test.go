@@ -0,0 +1,209 @@
+package codegen
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSchema has a base template and a template inheriting from it
+func testSchema() *Schema {
+	return &Schema{Templates: []Template{
+		{
+			ID:   "{AAAA-0001}",
+			Name: "_Titled",
+			Fields: []TemplateField{
+				{Name: "Title", Type: "Single-Line Text"},
+			},
+		},
+		{
+			ID:            "BBBB0002",
+			Name:          "Hero Banner",
+			Path:          "/sitecore/templates/Project/Site/Hero Banner",
+			BaseTemplates: []string{"{aaaa-0001}", "{unknown}"},
+			Fields: []TemplateField{
+				{Name: "Image", Type: "Image"},
+				{Name: "CTA Link", Type: "General Link"},
+				{Name: "Related", Type: "Multilist"},
+				{Name: "Settings", Type: "Name Value List"},
+			},
+		},
+	}}
+}
+
+func TestGoName(t *testing.T) {
+	assert.Equal(t, "HeroBanner", GoName("Hero Banner"))
+	assert.Equal(t, "CtaLink", GoName("cta-link"))
+	assert.Equal(t, "Titled", GoName("_Titled"))
+	assert.Equal(t, "X2Column", GoName("2 column"))
+	assert.Equal(t, "Field", GoName("--"))
+}
+
+func TestSchema_AllFieldsIncludesInheritedFields(t *testing.T) {
+	schema := testSchema()
+	fields := schema.AllFields(schema.Template("hero banner"))
+
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Name
+	}
+	assert.Equal(t, []string{"Image", "CTA Link", "Related", "Settings", "Title"}, names)
+}
+
+func TestGenerateStructs(t *testing.T) {
+	source, err := GenerateStructs(testSchema(), StructOptions{Package: "models"})
+	require.NoError(t, err)
+
+	code := string(source)
+	assert.True(t, strings.HasPrefix(code, Header))
+	assert.Contains(t, code, "// HeroBanner is generated from the template /sitecore/templates/Project/Site/Hero Banner")
+	assert.Contains(t, code, "CTALink *sdkmodels.LinkField `sc:\"CTA Link,optional\"`")
+	assert.Contains(t, code, "Related *sdkmodels.ItemReferenceList `sc:\"Related,optional\"`")
+	assert.Contains(t, code, "Settings any `sc:\"Settings,optional\"`")
+	assert.Contains(t, code, "Title *sdkmodels.TextField `sc:\"Title,optional\"`")
+	assert.Contains(t, code, "func BindHeroBanner(fields any) (*HeroBanner, error)")
+}
+
+func TestGenerateStructs_DuplicateNames(t *testing.T) {
+	schema := &Schema{Templates: []Template{{Name: "Hero Banner"}, {Name: "HeroBanner"}}}
+	_, err := GenerateStructs(schema, StructOptions{})
+	assert.ErrorContains(t, err, "both generate HeroBanner")
+}
+
+func TestScaffoldComponent(t *testing.T) {
+	schema := testSchema()
+	source, err := ScaffoldComponent("Hero Banner", ComponentOptions{
+		ModelsImport: "example.com/app/models",
+		Fields:       schema.AllFields(schema.Template("Hero Banner")),
+	})
+	require.NoError(t, err)
+
+	code := string(source)
+	assert.Contains(t, code, `models "example.com/app/models"`)
+	assert.Contains(t, code, "templ HeroBanner(fields any, params map[string]any) {")
+	assert.Contains(t, code, "if props, err := models.BindHeroBanner(fields); err == nil {")
+	assert.Contains(t, code, `@sdkcomponents.GeneralLink(props.CTALink, "CTA Link", editing, "")`)
+	assert.Contains(t, code, `@sdkcomponents.PlainText(props.Title, "Title", editing)`)
+	assert.Contains(t, code, "// props.Related (Multilist): render the field value here")
+}
+
+func TestScaffoldComponent_Untyped(t *testing.T) {
+	source, err := ScaffoldComponent("Footer", ComponentOptions{})
+	require.NoError(t, err)
+
+	code := string(source)
+	assert.Contains(t, code, "templ Footer(fields any, params map[string]any) {")
+	assert.NotContains(t, code, "sdkcomponents")
+	assert.NotContains(t, code, "middleware")
+}
+
+func TestGenerateRegistry(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "hero.templ"), []byte(`package components
+
+templ Hero(fields any, params map[string]any) {
+}
+
+templ heroVariant(fields any, params map[string]any) {
+}
+`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "footer.templ"), []byte(`package components
+
+templ Footer(fields interface{}, params map[string]interface{}) {
+}
+
+templ Copyright(year int) {
+}
+`), 0o644))
+
+	source, err := GenerateRegistry(dir, "components")
+	require.NoError(t, err)
+
+	code := string(source)
+	assert.Contains(t, code, `"Footer": Footer,`)
+	assert.Contains(t, code, `"Hero":   Hero,`)
+	assert.NotContains(t, code, "heroVariant")
+	assert.NotContains(t, code, "Copyright")
+}
+
+func TestImportPath(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/app\n\ngo 1.25\n"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "internal", "models"), 0o755))
+
+	path, err := ImportPath(filepath.Join(root, "internal", "models"))
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/app/internal/models", path)
+}
+
+// fakeClient serves template folders by path
+type fakeClient struct {
+	folders map[string][]any
+}
+
+func (c *fakeClient) Request(ctx context.Context, query string, variables map[string]any) (map[string]any, error) {
+	children, ok := c.folders[variables["path"].(string)]
+	if !ok {
+		return map[string]any{"item": nil}, nil
+	}
+	return map[string]any{"item": map[string]any{
+		"children": map[string]any{
+			"pageInfo": map[string]any{"hasNext": false},
+			"results":  children,
+		},
+	}}, nil
+}
+
+func TestIntrospect(t *testing.T) {
+	client := &fakeClient{folders: map[string][]any{
+		"/sitecore/templates/Project": {
+			map[string]any{
+				"name": "Site", "path": "/sitecore/templates/Project/Site", "hasChildren": true,
+				"template": map[string]any{"name": "Template Folder"},
+			},
+		},
+		"/sitecore/templates/Project/Site": {
+			map[string]any{
+				"id": "BBBB0002", "name": "Hero", "path": "/sitecore/templates/Project/Site/Hero", "hasChildren": true,
+				"template":      map[string]any{"name": "Template"},
+				"baseTemplates": map[string]any{"value": "{AAAA-0001}|{CCCC-0003}"},
+				"children": map[string]any{"results": []any{
+					map[string]any{
+						"name":     "Content",
+						"template": map[string]any{"name": "Template section"},
+						"children": map[string]any{"results": []any{
+							map[string]any{
+								"name":     "Title",
+								"template": map[string]any{"name": "Template field"},
+								"type":     map[string]any{"value": "Single-Line Text"},
+							},
+						}},
+					},
+					map[string]any{
+						"name":     "__Standard Values",
+						"template": map[string]any{"name": "Hero"},
+					},
+				}},
+			},
+		},
+	}}
+
+	schema, err := Introspect(context.Background(), IntrospectConfig{Client: client})
+	require.NoError(t, err)
+	require.Len(t, schema.Templates, 1)
+
+	hero := schema.Templates[0]
+	assert.Equal(t, "Hero", hero.Name)
+	assert.Equal(t, []string{"{AAAA-0001}", "{CCCC-0003}"}, hero.BaseTemplates)
+	assert.Equal(t, []TemplateField{{Name: "Title", Type: "Single-Line Text", Section: "Content"}}, hero.Fields)
+}
+
+func TestIntrospect_MissingRoot(t *testing.T) {
+	_, err := Introspect(context.Background(), IntrospectConfig{Client: &fakeClient{}, RootPath: "/missing"})
+	assert.ErrorContains(t, err, "template folder /missing not found")
+}
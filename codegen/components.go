@@ -0,0 +1,190 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// RegistryFile is the file GenerateRegistry output is written to
+const RegistryFile = "components_gen.go"
+
+// ComponentOptions contains options for ScaffoldComponent
+type ComponentOptions struct {
+	// Package is the package name of the component (default: components)
+	Package string
+
+	// ModelsImport is the import path of the generated structs.
+	// Without it, or without fields, the skeleton doesn't bind fields.
+	ModelsImport string
+
+	// Fields are the template fields to render, including inherited fields
+	Fields []TemplateField
+}
+
+// fieldRenderers maps typed fields to the SDK field component rendering them.
+// %[1]s is the struct field and %[2]q the Sitecore field name.
+var fieldRenderers = map[string]string{
+	"TextField":     "@sdkcomponents.PlainText(props.%[1]s, %[2]q, editing)",
+	"RichTextField": "@sdkcomponents.RichText(sdkmodels.GetFieldByName(fields, %[2]q), %[2]q, editing, \"\")",
+	"ImageField":    "@sdkcomponents.Image(props.%[1]s, %[2]q, editing, \"\", \"\", \"\")",
+	"LinkField":     "@sdkcomponents.GeneralLink(props.%[1]s, %[2]q, editing, \"\")",
+	"DateField":     "@sdkcomponents.DateTime(props.%[1]s, %[2]q, editing, \"January 2, 2006\", nil, \"\")",
+	"NumberField":   "@sdkcomponents.Number(props.%[1]s, %[2]q, editing, -1, \"\")",
+	"IntegerField":  "@sdkcomponents.Integer(props.%[1]s, %[2]q, editing, \"\")",
+	"CheckboxField": "@sdkcomponents.Checkbox(props.%[1]s, %[2]q, editing, \"\")",
+	"FileField":     "@sdkcomponents.File(props.%[1]s, %[2]q, editing, \"\", \"\")",
+}
+
+// ScaffoldComponent generates a templ component skeleton with the signature
+// component registries expect:
+//
+//	templ Hero(fields any, params map[string]any)
+//
+// With fields and a models import, the skeleton binds the generated struct
+// and renders each field with the matching SDK field component.
+func ScaffoldComponent(name string, options ComponentOptions) ([]byte, error) {
+	goName := GoName(name)
+	if options.Package == "" {
+		options.Package = "components"
+	}
+	typed := options.ModelsImport != "" && len(options.Fields) > 0
+	modelsAlias := path.Base(options.ModelsImport)
+
+	var body bytes.Buffer
+	usesComponents := false
+	fmt.Fprintf(&body, "// %s renders the %s component\n", goName, name)
+	fmt.Fprintf(&body, "templ %s(fields any, params map[string]any) {\n", goName)
+	if typed {
+		fmt.Fprintf(&body, "\tif props, err := %s.Bind%s(fields); err == nil {\n", modelsAlias, goName)
+		body.WriteString("\t\t{{ editing := middleware.IsEditMode(ctx) }}\n")
+		fmt.Fprintf(&body, "\t\t<section class={ %q, sdkmodels.GetStringParam(params, \"styles\") }>\n", kebab(goName))
+		usesProps := false
+		for _, field := range options.Fields {
+			fieldName := GoName(field.Name)
+			renderer, ok := fieldRenderers[FieldType(field.Type)]
+			if !ok {
+				fmt.Fprintf(&body, "\t\t\t// props.%s (%s): render the field value here\n", fieldName, fieldTypeLabel(field.Type))
+				continue
+			}
+			usesComponents = true
+			usesProps = usesProps || strings.Contains(renderer, "props.")
+			fmt.Fprintf(&body, "\t\t\t"+renderer+"\n", fieldName, field.Name)
+		}
+		body.WriteString("\t\t</section>\n")
+		if !usesProps {
+			body.WriteString("\t\t{{ _ = props }}\n")
+		}
+		if !usesComponents {
+			body.WriteString("\t\t{{ _ = editing }}\n")
+		}
+		body.WriteString("\t}\n}\n")
+	} else {
+		fmt.Fprintf(&body, "\t<section class={ %q, sdkmodels.GetStringParam(params, \"styles\") }>\n", kebab(goName))
+		body.WriteString("\t\t// Bind fields with sdkmodels.Bind and render them with the SDK field components\n")
+		body.WriteString("\t</section>\n}\n")
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "package %s\n\n", options.Package)
+	b.WriteString("import (\n")
+	if usesComponents {
+		b.WriteString("\tsdkcomponents \"github.com/guitarrich/content-sdk-go/components\"\n")
+	}
+	if typed {
+		b.WriteString("\t\"github.com/guitarrich/content-sdk-go/middleware\"\n")
+	}
+	b.WriteString("\tsdkmodels \"github.com/guitarrich/content-sdk-go/models\"\n")
+	if typed {
+		fmt.Fprintf(&b, "\t%s %q\n", modelsAlias, options.ModelsImport)
+	}
+	b.WriteString(")\n\n")
+	b.Write(body.Bytes())
+
+	return b.Bytes(), nil
+}
+
+// componentPattern matches templ components with the registry signature
+var componentPattern = regexp.MustCompile(`(?m)^templ\s+([A-Z]\w*)\(\s*fields\s+(?:any|interface\{\})\s*,\s*params\s+map\[string\](?:any|interface\{\})\s*\)`)
+
+// GenerateRegistry generates a map of the components in a directory's templ
+// files that have the registry signature, keyed by component name
+func GenerateRegistry(dir, pkg string) ([]byte, error) {
+	if pkg == "" {
+		pkg = "components"
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.templ"))
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, file := range files {
+		source, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range componentPattern.FindAllSubmatch(source, -1) {
+			names = append(names, string(match[1]))
+		}
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s\n\npackage %s\n\n", Header, pkg)
+	b.WriteString("import \"github.com/a-h/templ\"\n\n")
+	b.WriteString("// Components maps Sitecore component names to the components of this package.\n")
+	b.WriteString("// Add them to the application's component registry:\n")
+	b.WriteString("//\n//\tfor name, component := range Components {\n//\t\tregistry.Register(name, component)\n//\t}\n")
+	b.WriteString("var Components = map[string]func(fields any, params map[string]any) templ.Component{\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "\t%q: %s,\n", name, name)
+	}
+	b.WriteString("}\n")
+
+	source, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated registry: %w", err)
+	}
+	return source, nil
+}
+
+// ImportPath returns the import path of a directory from the nearest go.mod
+func ImportPath(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for root := abs; ; root = filepath.Dir(root) {
+		data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+		if err == nil {
+			module := modulePattern.FindSubmatch(data)
+			if module == nil {
+				return "", fmt.Errorf("no module directive in %s", filepath.Join(root, "go.mod"))
+			}
+			rel, err := filepath.Rel(root, abs)
+			if err != nil {
+				return "", err
+			}
+			if rel == "." {
+				return string(module[1]), nil
+			}
+			return string(module[1]) + "/" + filepath.ToSlash(rel), nil
+		}
+		if filepath.Dir(root) == root {
+			return "", fmt.Errorf("no go.mod found above %s", abs)
+		}
+	}
+}
+
+// modulePattern matches the module directive of a go.mod file
+var modulePattern = regexp.MustCompile(`(?m)^module\s+"?([^\s"]+)"?`)
@@ -0,0 +1,210 @@
+package codegen
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/guitarrich/content-sdk-go/debug"
+	"github.com/guitarrich/content-sdk-go/graphql"
+)
+
+// IntrospectConfig contains configuration for reading templates from GraphQL
+type IntrospectConfig struct {
+	// Client queries the Sitecore GraphQL endpoint
+	Client graphql.Client
+
+	// RootPath is the template folder to read (default: /sitecore/templates/Project)
+	RootPath string
+
+	// Language is the language of the template items (default: en)
+	Language string
+}
+
+// templateTreeQuery reads the children of a template folder with two levels
+// below each child: the sections and fields of templates
+const templateTreeQuery = `
+query TemplateTree($path: String!, $language: String!, $after: String) {
+  item(path: $path, language: $language) {
+    children(first: 50, after: $after) {
+      pageInfo {
+        hasNext
+        endCursor
+      }
+      results {
+        id
+        name
+        path
+        hasChildren
+        template {
+          name
+        }
+        baseTemplates: field(name: "__Base template") {
+          value
+        }
+        children(first: 50) {
+          results {
+            name
+            template {
+              name
+            }
+            children(first: 100) {
+              results {
+                name
+                template {
+                  name
+                }
+                type: field(name: "Type") {
+                  value
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}
+`
+
+// Template item template names
+const (
+	templateItem        = "Template"
+	templateSectionItem = "Template section"
+	templateFieldItem   = "Template field"
+)
+
+// Introspect reads the templates below the root path from Sitecore GraphQL.
+// Templates are read from items, so the endpoint must serve the template tree
+// (the preview endpoint does; published Edge content usually doesn't).
+func Introspect(ctx context.Context, config IntrospectConfig) (*Schema, error) {
+	if config.Client == nil {
+		return nil, fmt.Errorf("graphql client is required")
+	}
+	if config.RootPath == "" {
+		config.RootPath = "/sitecore/templates/Project"
+	}
+	if config.Language == "" {
+		config.Language = "en"
+	}
+
+	schema := &Schema{}
+	folders := []string{config.RootPath}
+	for len(folders) > 0 {
+		folder := folders[0]
+		folders = folders[1:]
+
+		children, err := fetchChildren(ctx, config, folder)
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range children {
+			if templateName(child) == templateItem {
+				schema.Templates = append(schema.Templates, parseTemplate(child))
+				continue
+			}
+			if hasChildren, _ := child["hasChildren"].(bool); hasChildren {
+				if path, _ := child["path"].(string); path != "" {
+					folders = append(folders, path)
+				}
+			}
+		}
+	}
+
+	debug.Common("introspected %d templates below %s", len(schema.Templates), config.RootPath)
+	return schema, nil
+}
+
+// fetchChildren reads all pages of children of a folder
+func fetchChildren(ctx context.Context, config IntrospectConfig, path string) ([]map[string]any, error) {
+	var children []map[string]any
+	var after any
+	for {
+		data, err := config.Client.Request(ctx, templateTreeQuery, map[string]any{
+			"path":     path,
+			"language": config.Language,
+			"after":    after,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read templates below %s: %w", path, err)
+		}
+
+		item, _ := data["item"].(map[string]any)
+		if item == nil {
+			return nil, fmt.Errorf("template folder %s not found", path)
+		}
+		connection, _ := item["children"].(map[string]any)
+		children = append(children, results(connection)...)
+
+		pageInfo, _ := connection["pageInfo"].(map[string]any)
+		if hasNext, _ := pageInfo["hasNext"].(bool); !hasNext {
+			return children, nil
+		}
+		after = pageInfo["endCursor"]
+	}
+}
+
+// parseTemplate converts a template item to a Template
+func parseTemplate(item map[string]any) Template {
+	template := Template{
+		ID:   stringValue(item, "id"),
+		Name: stringValue(item, "name"),
+		Path: stringValue(item, "path"),
+	}
+
+	if base, ok := item["baseTemplates"].(map[string]any); ok {
+		for _, id := range strings.Split(stringValue(base, "value"), "|") {
+			if id = strings.TrimSpace(id); id != "" {
+				template.BaseTemplates = append(template.BaseTemplates, id)
+			}
+		}
+	}
+
+	sections, _ := item["children"].(map[string]any)
+	for _, section := range results(sections) {
+		if templateName(section) != templateSectionItem {
+			continue
+		}
+		fields, _ := section["children"].(map[string]any)
+		for _, field := range results(fields) {
+			if templateName(field) != templateFieldItem {
+				continue
+			}
+			fieldType := ""
+			if typeField, ok := field["type"].(map[string]any); ok {
+				fieldType = stringValue(typeField, "value")
+			}
+			template.Fields = append(template.Fields, TemplateField{
+				Name:    stringValue(field, "name"),
+				Type:    fieldType,
+				Section: stringValue(section, "name"),
+			})
+		}
+	}
+
+	return template
+}
+
+// results returns the results of a GraphQL connection
+func results(connection map[string]any) []map[string]any {
+	list, _ := connection["results"].([]any)
+	items := make([]map[string]any, 0, len(list))
+	for _, entry := range list {
+		if item, ok := entry.(map[string]any); ok {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// templateName returns the name of an item's template
+func templateName(item map[string]any) string {
+	template, _ := item["template"].(map[string]any)
+	return stringValue(template, "name")
+}
+
+// stringValue returns a string property of a map
+func stringValue(m map[string]any, key string) string {
+	value, _ := m[key].(string)
+	return value
+}
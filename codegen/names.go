@@ -0,0 +1,58 @@
+package codegen
+
+import (
+	"strings"
+	"unicode"
+)
+
+// GoName converts a Sitecore name to an exported Go identifier
+// ("Hero Banner" becomes "HeroBanner", "cta-link" becomes "CtaLink")
+func GoName(name string) string {
+	var b strings.Builder
+	upperNext := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upperNext = true
+			continue
+		}
+		if upperNext {
+			r = unicode.ToUpper(r)
+			upperNext = false
+		}
+		b.WriteRune(r)
+	}
+
+	result := b.String()
+	if result == "" {
+		return "Field"
+	}
+	if unicode.IsDigit(rune(result[0])) {
+		result = "X" + result
+	}
+	return result
+}
+
+// lowerFirst lowercases the first letter of an identifier
+func lowerFirst(name string) string {
+	if name == "" {
+		return name
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
+
+// kebab converts an identifier to a CSS class name ("HeroBanner" becomes "hero-banner")
+func kebab(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
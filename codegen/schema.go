@@ -0,0 +1,139 @@
+// Package codegen generates Go code from Sitecore templates.
+//
+// Templates are read from Edge GraphQL (Introspect) or from a JSON schema dump
+// (LoadSchema). GenerateStructs turns them into structs of typed fields that
+// bind with models.Bind, ScaffoldComponent writes templ component skeletons and
+// GenerateRegistry maps component names to the components of a package.
+// The content-sdk-go command wraps these in a CLI.
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Schema is a set of Sitecore templates
+type Schema struct {
+	Templates []Template `json:"templates"`
+}
+
+// Template is a Sitecore data template
+type Template struct {
+	// ID is the template item ID
+	ID string `json:"id,omitempty"`
+
+	// Name is the template name
+	Name string `json:"name"`
+
+	// Path is the template item path
+	Path string `json:"path,omitempty"`
+
+	// BaseTemplates are the IDs of the templates this template inherits from
+	BaseTemplates []string `json:"baseTemplates,omitempty"`
+
+	// Fields are the fields defined on the template itself
+	Fields []TemplateField `json:"fields"`
+}
+
+// TemplateField is a field of a Sitecore template
+type TemplateField struct {
+	// Name is the field name
+	Name string `json:"name"`
+
+	// Type is the Sitecore field type (e.g. "Single-Line Text")
+	Type string `json:"type"`
+
+	// Section is the template section the field belongs to
+	Section string `json:"section,omitempty"`
+}
+
+// LoadSchema reads a JSON schema dump
+func LoadSchema(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+
+	var schema Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse schema %s: %w", path, err)
+	}
+	return &schema, nil
+}
+
+// Save writes the schema as JSON
+func (s *Schema) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Template returns the template with the given name, ignoring case
+func (s *Schema) Template(name string) *Template {
+	for i := range s.Templates {
+		if strings.EqualFold(s.Templates[i].Name, name) {
+			return &s.Templates[i]
+		}
+	}
+	return nil
+}
+
+// AllFields returns the fields of a template including inherited fields.
+// Own fields come first; fields of base templates missing from the schema are skipped.
+func (s *Schema) AllFields(t *Template) []TemplateField {
+	byID := make(map[string]*Template, len(s.Templates))
+	for i := range s.Templates {
+		if s.Templates[i].ID != "" {
+			byID[normalizeID(s.Templates[i].ID)] = &s.Templates[i]
+		}
+	}
+
+	var fields []TemplateField
+	seen := map[string]bool{}
+	visited := map[string]bool{}
+
+	var collect func(t *Template)
+	collect = func(t *Template) {
+		if t.ID != "" {
+			if visited[normalizeID(t.ID)] {
+				return
+			}
+			visited[normalizeID(t.ID)] = true
+		}
+		for _, field := range t.Fields {
+			key := strings.ToLower(field.Name)
+			if !seen[key] {
+				seen[key] = true
+				fields = append(fields, field)
+			}
+		}
+		for _, id := range t.BaseTemplates {
+			if base, ok := byID[normalizeID(id)]; ok {
+				collect(base)
+			}
+		}
+	}
+	collect(t)
+
+	return fields
+}
+
+// sorted returns the templates sorted by name
+func (s *Schema) sorted() []Template {
+	templates := slices.Clone(s.Templates)
+	slices.SortStableFunc(templates, func(a, b Template) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return templates
+}
+
+// normalizeID converts Sitecore IDs to a comparable form
+// ("{6F8A...-...}" and "6F8A......" are equal)
+func normalizeID(id string) string {
+	return strings.ToUpper(strings.NewReplacer("{", "", "}", "", "-", "").Replace(strings.TrimSpace(id)))
+}
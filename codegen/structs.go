@@ -0,0 +1,130 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+)
+
+// Header marks generated files
+const Header = "// Code generated by content-sdk-go; DO NOT EDIT."
+
+// fieldTypes maps Sitecore field types (lowercase) to the typed fields of the models package
+var fieldTypes = map[string]string{
+	"single-line text":         "TextField",
+	"multi-line text":          "TextField",
+	"password":                 "TextField",
+	"droplist":                 "TextField",
+	"grouped droplist":         "TextField",
+	"rich text":                "RichTextField",
+	"image":                    "ImageField",
+	"general link":             "LinkField",
+	"general link with search": "LinkField",
+	"date":                     "DateField",
+	"datetime":                 "DateField",
+	"number":                   "NumberField",
+	"integer":                  "IntegerField",
+	"checkbox":                 "CheckboxField",
+	"file":                     "FileField",
+	"droplink":                 "ItemReference",
+	"droptree":                 "ItemReference",
+	"grouped droplink":         "ItemReference",
+	"checklist":                "ItemReferenceList",
+	"multilist":                "ItemReferenceList",
+	"multilist with search":    "ItemReferenceList",
+	"treelist":                 "ItemReferenceList",
+	"treelistex":               "ItemReferenceList",
+	"treelist with search":     "ItemReferenceList",
+}
+
+// FieldType returns the models field type for a Sitecore field type,
+// or "" for types without a typed field
+func FieldType(sitecoreType string) string {
+	return fieldTypes[strings.ToLower(strings.TrimSpace(sitecoreType))]
+}
+
+// StructOptions contains options for GenerateStructs
+type StructOptions struct {
+	// Package is the package name of the generated file (default: models)
+	Package string
+}
+
+// GenerateStructs generates a struct per template, with a pointer to a typed
+// field per template field (including inherited fields) and a Bind function:
+//
+//	type Hero struct {
+//		Title *sdkmodels.TextField `sc:"Title,optional"`
+//	}
+//
+//	func BindHero(fields any) (*Hero, error)
+//
+// Fields without a typed field are bound as any.
+func GenerateStructs(schema *Schema, options StructOptions) ([]byte, error) {
+	if len(schema.Templates) == 0 {
+		return nil, fmt.Errorf("schema has no templates")
+	}
+	if options.Package == "" {
+		options.Package = "models"
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s\n\npackage %s\n\n", Header, options.Package)
+	fmt.Fprintf(&b, "import sdkmodels \"github.com/guitarrich/content-sdk-go/models\"\n")
+
+	names := map[string]string{}
+	for _, template := range schema.sorted() {
+		name := GoName(template.Name)
+		if other, ok := names[name]; ok {
+			return nil, fmt.Errorf("templates %s and %s both generate %s", other, template.Path, name)
+		}
+		names[name] = template.Path
+
+		fields := schema.AllFields(&template)
+
+		b.WriteString("\n")
+		if template.Path != "" {
+			fmt.Fprintf(&b, "// %s is generated from the template %s\n", name, template.Path)
+		} else {
+			fmt.Fprintf(&b, "// %s is generated from the template %s\n", name, template.Name)
+		}
+		fmt.Fprintf(&b, "type %s struct {\n", name)
+
+		fieldNames := map[string]bool{}
+		for _, field := range fields {
+			fieldName := GoName(field.Name)
+			if fieldNames[fieldName] {
+				return nil, fmt.Errorf("template %s: fields generate duplicate name %s", template.Name, fieldName)
+			}
+			fieldNames[fieldName] = true
+
+			goType := "any"
+			if fieldType := FieldType(field.Type); fieldType != "" {
+				goType = "*sdkmodels." + fieldType
+			}
+			fmt.Fprintf(&b, "\t// %s is the %q field (%s)\n", fieldName, field.Name, fieldTypeLabel(field.Type))
+			fmt.Fprintf(&b, "\t%s %s `sc:\"%s,optional\"`\n", fieldName, goType, field.Name)
+		}
+		b.WriteString("}\n")
+
+		fmt.Fprintf(&b, "\n// Bind%s binds component fields to %s\n", name, name)
+		fmt.Fprintf(&b, "func Bind%s(fields any) (*%s, error) {\n", name, name)
+		fmt.Fprintf(&b, "\tvar props %s\n", name)
+		b.WriteString("\tif err := sdkmodels.Bind(fields, &props); err != nil {\n\t\treturn nil, err\n\t}\n")
+		b.WriteString("\treturn &props, nil\n}\n")
+	}
+
+	source, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated structs: %w", err)
+	}
+	return source, nil
+}
+
+// fieldTypeLabel returns the Sitecore field type for doc comments
+func fieldTypeLabel(sitecoreType string) string {
+	if sitecoreType == "" {
+		return "untyped"
+	}
+	return sitecoreType
+}
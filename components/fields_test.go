@@ -0,0 +1,74 @@
+package components_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/guitarrich/content-sdk-go/components"
+	"github.com/guitarrich/content-sdk-go/models"
+	"github.com/guitarrich/content-sdk-go/richtext"
+	"github.com/guitarrich/content-sdk-go/site"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRichText_SanitizesWithoutProcessor(t *testing.T) {
+	field := map[string]any{
+		"value": `<p onclick="steal()">Hello <img src="/a.png" onerror="steal()"></p><script>steal()</script><a href="javascript:steal()">link</a>`,
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, components.RichText(field, "Text", false, "").Render(context.Background(), &buf))
+	html := buf.String()
+
+	assert.Contains(t, html, `<p>Hello <img src="/a.png"/></p>`)
+	for _, unsafe := range []string{"<script", "steal()", "onerror", "onclick", "javascript:"} {
+		assert.NotContains(t, html, unsafe)
+	}
+}
+
+func TestRichText_Processor(t *testing.T) {
+	field := map[string]any{"value": `<a href="~/link.aspx?_id=1E6E0A6AB2E04D7C8F5B8E9A2C3D4E5F">About</a><script>steal()</script>`}
+	processor := richtext.NewProcessor(richtext.Config{
+		LinkResolver: richtext.LinkResolverFunc(func(ctx context.Context, itemID, site, language string) (string, error) {
+			return "/about", nil
+		}),
+	})
+	ctx := richtext.NewContext(context.Background(), processor, richtext.Options{Language: "en"})
+
+	var buf bytes.Buffer
+	require.NoError(t, components.RichText(field, "Text", false, "").Render(ctx, &buf))
+
+	assert.Contains(t, buf.String(), `<a href="/about">About</a>`)
+	assert.NotContains(t, buf.String(), "<script")
+}
+
+func TestGeneralLink_URLService(t *testing.T) {
+	info := models.SiteInfo{Name: "brand-a", HostName: "example.com/brand-a", Language: "en"}
+	ctx := site.NewURLContext(context.Background(), site.NewURLService(site.URLServiceConfig{}), info, "fr")
+
+	tests := []struct {
+		name     string
+		field    *models.LinkField
+		expected string
+	}{
+		{"internal", &models.LinkField{Href: "/about", Text: "About", QueryString: "a=1", Anchor: "team"}, `href="/brand-a/fr/about?a=1#team"`},
+		{"home", &models.LinkField{Href: "/", Text: "Home"}, `href="/brand-a/fr"`},
+		{"external", &models.LinkField{Href: "https://other.com/about", Text: "Other"}, `href="https://other.com/about"`},
+		{"media", &models.LinkField{Href: "/-/media/doc.pdf", Text: "Doc"}, `href="/-/media/doc.pdf"`},
+		{"anchor", &models.LinkField{Anchor: "top", Text: "Top"}, `href="#top"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, components.GeneralLink(tt.field, "Link", false, "").Render(ctx, &buf))
+			assert.Contains(t, buf.String(), tt.expected)
+		})
+	}
+
+	// Without a URL service the Sitecore href is rendered as is
+	var buf bytes.Buffer
+	require.NoError(t, components.GeneralLink(&models.LinkField{Href: "/about"}, "Link", false, "").Render(context.Background(), &buf))
+	assert.Contains(t, buf.String(), `href="/about"`)
+}
@@ -0,0 +1,67 @@
+package components_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/guitarrich/content-sdk-go/components"
+	"github.com/guitarrich/content-sdk-go/seo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSEOHead(t *testing.T) {
+	head := &seo.Head{
+		Title:       "Big <Launch> | Example",
+		Description: "We launched",
+		Robots:      "noindex",
+		Canonical:   "https://www.example.com/news/launch",
+		Alternates: []seo.AlternateLink{
+			{Hreflang: "fr", Href: "https://www.example.com/fr/news/launch"},
+			{Hreflang: "x-default", Href: "https://www.example.com/news/launch"},
+		},
+		OpenGraph: seo.OpenGraph{
+			Type:   "article",
+			Title:  "Big <Launch>",
+			URL:    "https://www.example.com/news/launch",
+			Image:  "https://www.example.com/-/media/launch.jpg",
+			Locale: "en_US",
+		},
+		Twitter: seo.TwitterCard{Card: "summary_large_image", Site: "@example"},
+		JSONLD:  []any{seo.BreadcrumbList{{Name: "Home", URL: "https://www.example.com/"}}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, components.SEOHead(head).Render(context.Background(), &buf))
+	html := buf.String()
+
+	for _, tag := range []string{
+		`<title>Big &lt;Launch&gt; | Example</title>`,
+		`<meta name="description" content="We launched">`,
+		`<meta name="robots" content="noindex">`,
+		`<link rel="canonical" href="https://www.example.com/news/launch">`,
+		`<link rel="alternate" hreflang="fr" href="https://www.example.com/fr/news/launch">`,
+		`<link rel="alternate" hreflang="x-default" href="https://www.example.com/news/launch">`,
+		`<meta property="og:type" content="article">`,
+		`<meta property="og:title" content="Big &lt;Launch&gt;">`,
+		`<meta property="og:image" content="https://www.example.com/-/media/launch.jpg">`,
+		`<meta property="og:locale" content="en_US">`,
+		`<meta name="twitter:card" content="summary_large_image">`,
+		`<meta name="twitter:site" content="@example">`,
+		`<script type="application/ld+json">{"@context":"https://schema.org","@type":"BreadcrumbList"`,
+	} {
+		assert.Contains(t, html, tag)
+	}
+
+	// Empty properties are left out
+	assert.NotContains(t, html, `name="keywords"`)
+	assert.NotContains(t, html, `og:description`)
+	assert.NotContains(t, html, `twitter:title`)
+}
+
+func TestSEOHead_Nil(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, components.SEOHead(nil).Render(context.Background(), &buf))
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,130 @@
+package components_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/guitarrich/content-sdk-go/components"
+	"github.com/guitarrich/content-sdk-go/media"
+	"github.com/guitarrich/content-sdk-go/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func heroImage() *models.ImageField {
+	return &models.ImageField{Src: "/-/media/hero.jpg", Alt: "Hero", Width: "1200", Height: "600"}
+}
+
+func TestResponsiveImage(t *testing.T) {
+	opts := components.ImageOptions{Widths: []int{640, 1920}, Sizes: "(min-width: 1024px) 50vw, 100vw", CSSClass: "hero"}
+
+	var buf bytes.Buffer
+	require.NoError(t, components.ResponsiveImage(heroImage(), "Image", false, opts).Render(context.Background(), &buf))
+	html := buf.String()
+
+	// Widths above the intrinsic width are replaced by it
+	assert.Contains(t, html, `src="/-/media/hero.jpg?w=1200"`)
+	assert.Contains(t, html, `srcset="/-/media/hero.jpg?w=640 640w, /-/media/hero.jpg?w=1200 1200w"`)
+	assert.Contains(t, html, `sizes="(min-width: 1024px) 50vw, 100vw"`)
+	assert.Contains(t, html, `alt="Hero" width="1200" height="600"`)
+	assert.Contains(t, html, `loading="lazy" decoding="async"`)
+	assert.Contains(t, html, `class="hero"`)
+	assert.NotContains(t, html, "fetchpriority")
+	assert.NotContains(t, html, "data-field-name")
+}
+
+func TestResponsiveImage_Loading(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     components.ImageOptions
+		expected []string
+	}{
+		{
+			name:     "high priority loads eagerly",
+			opts:     components.ImageOptions{FetchPriority: "high"},
+			expected: []string{`loading="eager"`, `fetchpriority="high"`},
+		},
+		{
+			name:     "explicit loading wins",
+			opts:     components.ImageOptions{FetchPriority: "high", Loading: "lazy", Decoding: "sync"},
+			expected: []string{`loading="lazy"`, `decoding="sync"`, `fetchpriority="high"`},
+		},
+		{
+			name:     "low priority stays lazy",
+			opts:     components.ImageOptions{FetchPriority: "low"},
+			expected: []string{`loading="lazy"`, `fetchpriority="low"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, components.ResponsiveImage(heroImage(), "Image", false, tt.opts).Render(context.Background(), &buf))
+			for _, attribute := range tt.expected {
+				assert.Contains(t, buf.String(), attribute)
+			}
+		})
+	}
+}
+
+func TestResponsiveImage_Editing(t *testing.T) {
+	field := heroImage()
+	field.Metadata = &models.FieldMetadata{FieldID: "{F1}", FieldType: "Image"}
+
+	var buf bytes.Buffer
+	require.NoError(t, components.ResponsiveImage(field, "Image", true, components.ImageOptions{}).Render(context.Background(), &buf))
+	html := buf.String()
+
+	assert.Contains(t, html, `kind="open">{"datasource":`)
+	assert.Contains(t, html, `"fieldId":"{F1}"`)
+	assert.Contains(t, html, `data-field-name="Image"`)
+	assert.Contains(t, html, `kind="close"`)
+
+	// Editing requires metadata
+	buf.Reset()
+	require.NoError(t, components.ResponsiveImage(heroImage(), "Image", true, components.ImageOptions{}).Render(context.Background(), &buf))
+	assert.Contains(t, buf.String(), "field &#39;Image&#39; is missing required metadata in editing mode")
+	assert.NotContains(t, buf.String(), "<img")
+}
+
+func TestPicture(t *testing.T) {
+	square := &models.ImageField{Src: "/-/media/hero-square.jpg", Alt: "Hero", Width: "800", Height: "800"}
+	one := 1
+	sources := []components.ImageSource{
+		// Art-directed image for small screens
+		{Media: "(max-width: 640px)", Field: square, Widths: []int{320}, Type: "image/webp"},
+		// 1:1 crop of the main image for medium screens
+		{Media: "(max-width: 1024px)", Sizes: "50vw", Params: &media.ImageParams{Width: &one, Height: &one}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, components.Picture(heroImage(), "Image", false, sources, components.ImageOptions{Widths: []int{640}}).Render(context.Background(), &buf))
+	html := buf.String()
+
+	assert.Contains(t, html, `<picture><source srcset="/-/media/hero-square.jpg?w=320 320w" sizes="100vw" media="(max-width: 640px)" type="image/webp" width="800" height="800">`)
+	assert.Contains(t, html, `<source srcset="/-/media/hero.jpg?h=640&amp;w=640 640w" sizes="50vw" media="(max-width: 1024px)" width="1200" height="1200">`)
+	assert.Contains(t, html, `<img src="/-/media/hero.jpg?w=640" srcset="/-/media/hero.jpg?w=640 640w" sizes="100vw" alt="Hero" width="1200" height="600" loading="lazy" decoding="async"></picture>`)
+}
+
+func TestPicture_Editing(t *testing.T) {
+	field := heroImage()
+	field.Metadata = &models.FieldMetadata{FieldID: "{F1}", FieldType: "Image"}
+	sources := []components.ImageSource{{Media: "(max-width: 640px)", Widths: []int{320}}}
+
+	var buf bytes.Buffer
+	require.NoError(t, components.Picture(field, "Image", true, sources, components.ImageOptions{}).Render(context.Background(), &buf))
+	html := buf.String()
+
+	// Only the <img> is rendered so the image editor can select it
+	assert.NotContains(t, html, "<picture")
+	assert.NotContains(t, html, "<source")
+	assert.Contains(t, html, `kind="open"`)
+	assert.Contains(t, html, `data-field-name="Image"`)
+}
+
+func TestPicture_EmptyField(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, components.Picture(&models.ImageField{}, "Image", false, nil, components.ImageOptions{}).Render(context.Background(), &buf))
+	assert.Empty(t, buf.String())
+}
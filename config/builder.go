@@ -35,6 +35,7 @@ func (b *ConfigBuilder) WithEdgeAPI(contextID, clientContextID, edgeURL string)
 		b.config.API.Edge.EdgeURL = edgeURL
 	}
 	b.config.API.UseEdge = true
+	b.config.API.UseDisconnected = false
 	return b
 }
 
@@ -43,6 +44,14 @@ func (b *ConfigBuilder) WithLocalAPI(apiKey, apiHost string) *ConfigBuilder {
 	b.config.API.Local.APIKey = apiKey
 	b.config.API.Local.APIHost = apiHost
 	b.config.API.UseEdge = false
+	b.config.API.UseDisconnected = false
+	return b
+}
+
+// WithDisconnectedAPI serves content from the files in dataDir instead of Sitecore
+func (b *ConfigBuilder) WithDisconnectedAPI(dataDir string) *ConfigBuilder {
+	b.config.API.Disconnected.DataDir = dataDir
+	b.config.API.UseDisconnected = true
 	return b
 }
 
@@ -69,6 +78,13 @@ func (b *ConfigBuilder) WithMultisite(enabled bool, sites []models.SiteInfo, use
 	return b
 }
 
+// WithEdgeSites loads the multisite site list from Edge, refreshing it on the given interval
+func (b *ConfigBuilder) WithEdgeSites(enabled bool, refreshInterval time.Duration) *ConfigBuilder {
+	b.config.Multisite.UseEdgeSites = enabled
+	b.config.Multisite.RefreshInterval = refreshInterval
+	return b
+}
+
 // WithPersonalization configures personalization
 func (b *ConfigBuilder) WithPersonalization(enabled bool, scope, cdpEndpoint string) *ConfigBuilder {
 	b.config.Personalize.Enabled = enabled
@@ -92,6 +108,13 @@ func (b *ConfigBuilder) WithEditing(enabled bool, secret, internalHostURL string
 	return b
 }
 
+// WithMedia configures the media host and request protection shared secret
+func (b *ConfigBuilder) WithMedia(host, sharedSecret string) *ConfigBuilder {
+	b.config.Media.Host = host
+	b.config.Media.SharedSecret = sharedSecret
+	return b
+}
+
 // WithTimeouts sets the API timeouts
 func (b *ConfigBuilder) WithTimeouts(edgeTimeout, cdpTimeout time.Duration) *ConfigBuilder {
 	b.config.EdgeTimeout = edgeTimeout
@@ -28,6 +28,9 @@ type Config struct {
 	// Editing configuration
 	Editing EditingConfig `json:"editing"`
 
+	// Media configuration
+	Media MediaConfig `json:"media"`
+
 	// Timeouts
 	EdgeTimeout time.Duration `json:"edgeTimeout"`
 	CDPTimeout  time.Duration `json:"cdpTimeout"`
@@ -46,6 +49,12 @@ type APIConfig struct {
 
 	// UseEdge determines whether to use Edge or Local API
 	UseEdge bool `json:"useEdge"`
+
+	// Disconnected API configuration (for development without Sitecore)
+	Disconnected DisconnectedAPIConfig `json:"disconnected"`
+
+	// UseDisconnected serves content from local files instead of Edge or the Local API
+	UseDisconnected bool `json:"useDisconnected"`
 }
 
 // EdgeAPIConfig contains Sitecore Edge API configuration
@@ -69,6 +78,12 @@ type LocalAPIConfig struct {
 	APIHost string `json:"apiHost"`
 }
 
+// DisconnectedAPIConfig contains configuration for serving content from local files
+type DisconnectedAPIConfig struct {
+	// DataDir is the directory with the routes, dictionary and sites files
+	DataDir string `json:"dataDir"`
+}
+
 // MultisiteConfig contains multisite configuration
 type MultisiteConfig struct {
 	// Enabled determines if multisite is enabled
@@ -82,6 +97,13 @@ type MultisiteConfig struct {
 
 	// UseCookieResolution enables cookie-based site resolution
 	UseCookieResolution bool `json:"useCookieResolution"`
+
+	// UseEdgeSites loads the site list from Edge siteInfo at runtime.
+	// Sites configured statically are merged on top as overrides.
+	UseEdgeSites bool `json:"useEdgeSites"`
+
+	// RefreshInterval is how often the Edge site list is refreshed
+	RefreshInterval time.Duration `json:"refreshInterval"`
 }
 
 // PersonalizeConfig contains personalization configuration
@@ -104,6 +126,9 @@ type EditingConfig struct {
 	// Secret is the editing secret for security
 	Secret string `json:"secret"`
 
+	// Secrets are additional accepted editing secrets, for rotating secrets without downtime
+	Secrets []string `json:"secrets,omitempty"`
+
 	// InternalHostURL is the internal host URL for server-side requests
 	InternalHostURL string `json:"internalHostUrl"`
 
@@ -111,6 +136,15 @@ type EditingConfig struct {
 	AllowedOrigins []string `json:"allowedOrigins"`
 }
 
+// MediaConfig contains media URL configuration
+type MediaConfig struct {
+	// Host is the CDN or Edge host that /-/media/ and /-/jssmedia/ URLs are rewritten to
+	Host string `json:"host"`
+
+	// SharedSecret is the Sitecore media request protection shared secret
+	SharedSecret string `json:"sharedSecret"`
+}
+
 // LoadConfig loads configuration from environment variables
 func LoadConfig() *Config {
 	config := &Config{
@@ -125,12 +159,18 @@ func LoadConfig() *Config {
 				APIHost: utils.GetEnvVar("SITECORE_API_HOST"),
 			},
 			UseEdge: utils.GetEnvVarOrDefault("USE_EDGE_API", "false") == "true",
+			Disconnected: DisconnectedAPIConfig{
+				DataDir: utils.GetEnvVarOrDefault("SITECORE_DISCONNECTED_DATA_DIR", "data"),
+			},
+			UseDisconnected: utils.GetEnvVarOrDefault("USE_DISCONNECTED_API", "false") == "true",
 		},
 		DefaultSite:     utils.GetEnvVarOrDefault("DEFAULT_SITE_NAME", "default"),
 		DefaultLanguage: utils.GetEnvVarOrDefault("DEFAULT_LANGUAGE", "en"),
 		Multisite: MultisiteConfig{
 			Enabled:             utils.GetEnvVarOrDefault("MULTISITE_ENABLED", "true") == "true",
 			UseCookieResolution: utils.GetEnvVarOrDefault("MULTISITE_USE_COOKIE", "true") == "true",
+			UseEdgeSites:        utils.GetEnvVarOrDefault("MULTISITE_USE_EDGE_SITES", "false") == "true",
+			RefreshInterval:     parseDuration(utils.GetEnvVarOrDefault("MULTISITE_REFRESH_INTERVAL", "5m")),
 		},
 		Personalize: PersonalizeConfig{
 			Enabled:     utils.GetEnvVarOrDefault("PERSONALIZE_ENABLED", "false") == "true",
@@ -140,9 +180,14 @@ func LoadConfig() *Config {
 		Editing: EditingConfig{
 			Enabled:         utils.GetEnvVarOrDefault("EDITING_ENABLED", "false") == "true",
 			Secret:          utils.GetEnvVar("EDITING_SECRET"),
+			Secrets:         utils.GetEnvVarAsArray("EDITING_SECRETS", ","),
 			InternalHostURL: utils.GetEnvVar("SITECORE_INTERNAL_EDITING_HOST_URL"),
 			AllowedOrigins:  utils.GetEnvVarAsArray("ALLOWED_ORIGINS", ","),
 		},
+		Media: MediaConfig{
+			Host:         utils.GetEnvVar("SITECORE_MEDIA_HOST"),
+			SharedSecret: utils.GetEnvVar("SITECORE_MEDIA_SHARED_SECRET"),
+		},
 		EdgeTimeout: parseDuration(utils.GetEnvVarOrDefault("EDGE_TIMEOUT", "10s")),
 		CDPTimeout:  parseDuration(utils.GetEnvVarOrDefault("CDP_TIMEOUT", "400ms")),
 		EnableDebug: utils.GetEnvVar("DEBUG") != "",
@@ -154,7 +199,11 @@ func LoadConfig() *Config {
 // Validate validates the configuration
 func (c *Config) Validate() error {
 	// Validate API configuration
-	if c.API.UseEdge {
+	if c.API.UseDisconnected {
+		if c.API.Disconnected.DataDir == "" {
+			return fmt.Errorf("SITECORE_DISCONNECTED_DATA_DIR is required when using the disconnected API")
+		}
+	} else if c.API.UseEdge {
 		if c.API.Edge.ContextID == "" {
 			return fmt.Errorf("SITECORE_EDGE_CONTEXT_ID is required when using Edge API")
 		}
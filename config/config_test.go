@@ -90,6 +90,42 @@ func TestConfigValidate_LocalAPI(t *testing.T) {
 	}
 }
 
+func TestConfigValidate_DisconnectedAPI(t *testing.T) {
+	config := &Config{
+		API: APIConfig{
+			UseDisconnected: true,
+			Disconnected: DisconnectedAPIConfig{
+				DataDir: "data",
+			},
+		},
+		DefaultSite: "testsite",
+	}
+
+	if err := config.Validate(); err != nil {
+		t.Errorf("unexpected validation error: %v", err)
+	}
+
+	config.API.Disconnected.DataDir = ""
+	if err := config.Validate(); err == nil {
+		t.Error("expected validation error for missing data directory")
+	}
+}
+
+func TestConfigBuilder_DisconnectedAPI(t *testing.T) {
+	config, err := NewConfigBuilder().
+		WithDisconnectedAPI("testdata/content").
+		WithDefaultSite("mysite").
+		Build()
+
+	if err != nil {
+		t.Errorf("unexpected build error: %v", err)
+	}
+
+	if !config.API.UseDisconnected || config.API.Disconnected.DataDir != "testdata/content" {
+		t.Errorf("expected disconnected API with data directory 'testdata/content', got %+v", config.API)
+	}
+}
+
 func TestConfigBuilder_EdgeAPI(t *testing.T) {
 	config, err := NewConfigBuilder().
 		WithEdgeAPI("test-context", "client-context", "").
@@ -205,6 +241,26 @@ func TestConfigBuilder_Timeouts(t *testing.T) {
 	}
 }
 
+func TestConfigBuilder_Media(t *testing.T) {
+	config, err := NewConfigBuilder().
+		WithEdgeAPI("test-context", "", "").
+		WithDefaultSite("mysite").
+		WithMedia("https://cdn.example.com", "s3cret").
+		Build()
+
+	if err != nil {
+		t.Errorf("unexpected build error: %v", err)
+	}
+
+	if config.Media.Host != "https://cdn.example.com" {
+		t.Errorf("expected media host 'https://cdn.example.com', got '%s'", config.Media.Host)
+	}
+
+	if config.Media.SharedSecret != "s3cret" {
+		t.Errorf("expected shared secret 's3cret', got '%s'", config.Media.SharedSecret)
+	}
+}
+
 func TestConfigGetGraphQLEndpoint_Edge(t *testing.T) {
 	config := &Config{
 		API: APIConfig{
@@ -0,0 +1,281 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/guitarrich/content-sdk-go/debug"
+)
+
+// Subscriber is notified after a new configuration has been swapped in
+type Subscriber interface {
+	OnConfigChange(old, new *Config)
+}
+
+// SubscriberFunc is a function type that implements Subscriber
+type SubscriberFunc func(old, new *Config)
+
+// OnConfigChange implements the Subscriber interface
+func (f SubscriberFunc) OnConfigChange(old, new *Config) {
+	f(old, new)
+}
+
+// Loader loads a configuration from its source
+type Loader func() (*Config, error)
+
+// EnvLoader returns a Loader that reads configuration from environment variables
+func EnvLoader() Loader {
+	return func() (*Config, error) {
+		return LoadConfig(), nil
+	}
+}
+
+// FileLoader returns a Loader that reads configuration from a JSON file.
+// Values from the environment are used as defaults and overridden by the file.
+func FileLoader(path string) Loader {
+	return func() (*Config, error) {
+		return LoadConfigFile(path)
+	}
+}
+
+// LoadConfigFile loads configuration from a JSON file on top of environment defaults
+// Durations are expressed in nanoseconds, as encoded by time.Duration
+func LoadConfigFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	config := LoadConfig()
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// WatcherConfig contains configuration for the config watcher
+type WatcherConfig struct {
+	// Path is the JSON config file to watch. When empty, configuration is
+	// loaded from environment variables and only reloaded on SIGHUP or Reload.
+	Path string
+
+	// Loader overrides how configuration is loaded (optional)
+	Loader Loader
+
+	// Interval is how often the config file is checked for changes (default: 5s)
+	Interval time.Duration
+
+	// DisableSignal disables reloading on SIGHUP
+	DisableSignal bool
+}
+
+// Watcher holds the current configuration and reloads it when its source changes.
+// New configurations are validated before being swapped in; invalid ones are rejected
+// and the previous configuration stays active.
+type Watcher struct {
+	config      WatcherConfig
+	loader      Loader
+	current     atomic.Pointer[Config]
+	mu          sync.Mutex
+	subscribers map[int]Subscriber
+	nextID      int
+	modTime     time.Time
+	size        int64
+	stop        context.CancelFunc
+	done        chan struct{}
+}
+
+// NewWatcher creates a new config watcher and performs the initial load
+func NewWatcher(config WatcherConfig) (*Watcher, error) {
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Second
+	}
+
+	loader := config.Loader
+	if loader == nil {
+		if config.Path != "" {
+			loader = FileLoader(config.Path)
+		} else {
+			loader = EnvLoader()
+		}
+	}
+
+	w := &Watcher{
+		config:      config,
+		loader:      loader,
+		subscribers: make(map[int]Subscriber),
+	}
+
+	cfg, err := w.load()
+	if err != nil {
+		return nil, err
+	}
+	w.current.Store(cfg)
+	w.modTime, w.size = w.stat()
+
+	return w, nil
+}
+
+// Current returns the active configuration.
+// The returned value must be treated as read-only.
+func (w *Watcher) Current() *Config {
+	return w.current.Load()
+}
+
+// Subscribe registers a subscriber and returns a function that removes it
+func (w *Watcher) Subscribe(subscriber Subscriber) (unsubscribe func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	id := w.nextID
+	w.nextID++
+	w.subscribers[id] = subscriber
+
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(w.subscribers, id)
+	}
+}
+
+// Reload loads, validates and swaps in the configuration.
+// Subscribers are only notified when the configuration actually changed.
+func (w *Watcher) Reload() error {
+	cfg, err := w.load()
+	if err != nil {
+		debug.Config("config reload rejected: %v", err)
+		return err
+	}
+
+	w.mu.Lock()
+	old := w.current.Load()
+	if reflect.DeepEqual(old, cfg) {
+		w.mu.Unlock()
+		debug.Config("config unchanged, skipping notification")
+		return nil
+	}
+	w.current.Store(cfg)
+	subscribers := make([]Subscriber, 0, len(w.subscribers))
+	for _, s := range w.subscribers {
+		subscribers = append(subscribers, s)
+	}
+	w.mu.Unlock()
+
+	debug.Config("config reloaded, notifying %d subscribers", len(subscribers))
+	for _, s := range subscribers {
+		s.OnConfigChange(old, cfg)
+	}
+
+	return nil
+}
+
+// Start watches the config file and SIGHUP until ctx is cancelled or Stop is called
+func (w *Watcher) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	w.mu.Lock()
+	if w.stop != nil {
+		w.mu.Unlock()
+		cancel()
+		return
+	}
+	w.stop = cancel
+	w.done = make(chan struct{})
+	w.mu.Unlock()
+
+	var signals chan os.Signal
+	if !w.config.DisableSignal {
+		signals = make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGHUP)
+	}
+
+	go func() {
+		defer close(w.done)
+		if signals != nil {
+			defer signal.Stop(signals)
+		}
+
+		ticker := time.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+				debug.Config("SIGHUP received, reloading config")
+				_ = w.Reload()
+			case <-ticker.C:
+				if modTime, size, changed := w.fileChanged(); changed {
+					debug.Config("config file %s changed, reloading", w.config.Path)
+					// Only a loaded file is marked as seen, so a failed load
+					// (e.g. of a partly written file) is retried on the next tick
+					if w.Reload() == nil {
+						w.modTime, w.size = modTime, size
+					}
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops watching and waits for the watch loop to exit
+func (w *Watcher) Stop() {
+	w.mu.Lock()
+	stop, done := w.stop, w.done
+	w.stop, w.done = nil, nil
+	w.mu.Unlock()
+
+	if stop != nil {
+		stop()
+		<-done
+	}
+}
+
+// load runs the loader and validates the result
+func (w *Watcher) load() (*Config, error) {
+	cfg, err := w.loader()
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// fileChanged reports whether the watched file's modification time or size
+// differs from the last loaded file, and returns the new values
+func (w *Watcher) fileChanged() (time.Time, int64, bool) {
+	if w.config.Path == "" {
+		return time.Time{}, 0, false
+	}
+
+	modTime, size := w.stat()
+	if modTime.IsZero() {
+		return modTime, size, false
+	}
+	return modTime, size, !modTime.Equal(w.modTime) || size != w.size
+}
+
+// stat returns the watched file's modification time and size
+func (w *Watcher) stat() (time.Time, int64) {
+	if w.config.Path == "" {
+		return time.Time{}, 0
+	}
+	info, err := os.Stat(w.config.Path)
+	if err != nil {
+		debug.Config("failed to stat config file %s: %v", w.config.Path, err)
+		return time.Time{}, 0
+	}
+	return info.ModTime(), info.Size()
+}
@@ -0,0 +1,218 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+}
+
+const watcherTestConfig = `{
+	"api": {"useEdge": true, "edge": {"contextId": "ctx", "edgeUrl": "https://edge.example.com"}},
+	"defaultSite": "%s",
+	"editing": {"allowedOrigins": ["https://pages.example.com"]}
+}`
+
+func TestWatcher_LoadsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	writeConfigFile(t, path, sprintfConfig("site1"))
+
+	w, err := NewWatcher(WatcherConfig{Path: path, DisableSignal: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if w.Current().DefaultSite != "site1" {
+		t.Errorf("expected default site 'site1', got '%s'", w.Current().DefaultSite)
+	}
+	if len(w.Current().Editing.AllowedOrigins) != 1 {
+		t.Errorf("expected 1 allowed origin, got %d", len(w.Current().Editing.AllowedOrigins))
+	}
+}
+
+func TestWatcher_ReloadNotifiesSubscribers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	writeConfigFile(t, path, sprintfConfig("site1"))
+
+	w, err := NewWatcher(WatcherConfig{Path: path, DisableSignal: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var oldSite, newSite string
+	calls := 0
+	w.Subscribe(SubscriberFunc(func(old, new *Config) {
+		calls++
+		oldSite = old.DefaultSite
+		newSite = new.DefaultSite
+	}))
+
+	// Reload without changes should not notify
+	if err := w.Reload(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected no notification for unchanged config, got %d", calls)
+	}
+
+	writeConfigFile(t, path, sprintfConfig("site2"))
+	if err := w.Reload(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected 1 notification, got %d", calls)
+	}
+	if oldSite != "site1" || newSite != "site2" {
+		t.Errorf("expected site1 -> site2, got %s -> %s", oldSite, newSite)
+	}
+	if w.Current().DefaultSite != "site2" {
+		t.Errorf("expected current default site 'site2', got '%s'", w.Current().DefaultSite)
+	}
+}
+
+func TestWatcher_RejectsInvalidConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	writeConfigFile(t, path, sprintfConfig("site1"))
+
+	w, err := NewWatcher(WatcherConfig{Path: path, DisableSignal: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	notified := false
+	w.Subscribe(SubscriberFunc(func(old, new *Config) {
+		notified = true
+	}))
+
+	// Empty default site fails validation
+	writeConfigFile(t, path, sprintfConfig(""))
+	if err := w.Reload(); err == nil {
+		t.Error("expected validation error")
+	}
+
+	if notified {
+		t.Error("subscribers should not be notified of invalid config")
+	}
+	if w.Current().DefaultSite != "site1" {
+		t.Errorf("expected previous config to stay active, got '%s'", w.Current().DefaultSite)
+	}
+}
+
+func TestWatcher_Unsubscribe(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	writeConfigFile(t, path, sprintfConfig("site1"))
+
+	w, err := NewWatcher(WatcherConfig{Path: path, DisableSignal: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	calls := 0
+	unsubscribe := w.Subscribe(SubscriberFunc(func(old, new *Config) {
+		calls++
+	}))
+	unsubscribe()
+
+	writeConfigFile(t, path, sprintfConfig("site2"))
+	if err := w.Reload(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls != 0 {
+		t.Errorf("expected no notification after unsubscribe, got %d", calls)
+	}
+}
+
+func TestWatcher_StartDetectsFileChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	writeConfigFile(t, path, sprintfConfig("site1"))
+
+	w, err := NewWatcher(WatcherConfig{Path: path, Interval: 10 * time.Millisecond, DisableSignal: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	changed := make(chan string, 1)
+	w.Subscribe(SubscriberFunc(func(old, new *Config) {
+		changed <- new.DefaultSite
+	}))
+
+	w.Start(context.Background())
+	defer w.Stop()
+
+	writeConfigFile(t, path, sprintfConfig("site-updated"))
+
+	select {
+	case site := <-changed:
+		if site != "site-updated" {
+			t.Errorf("expected 'site-updated', got '%s'", site)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for config reload")
+	}
+}
+
+func sprintfConfig(defaultSite string) string {
+	return fmt.Sprintf(watcherTestConfig, defaultSite)
+}
+
+func TestWatcher_RetriesFailedFileLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	writeConfigFile(t, path, sprintfConfig("site1"))
+
+	// The first load after the change fails, as for a file read while being written
+	failures := make(chan struct{}, 1)
+	loader := FileLoader(path)
+	loads := 0
+	w, err := NewWatcher(WatcherConfig{
+		Path:          path,
+		Interval:      10 * time.Millisecond,
+		DisableSignal: true,
+		Loader: func() (*Config, error) {
+			loads++
+			if loads == 2 {
+				failures <- struct{}{}
+				return nil, fmt.Errorf("unexpected end of JSON input")
+			}
+			return loader()
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	changed := make(chan string, 1)
+	w.Subscribe(SubscriberFunc(func(old, new *Config) {
+		changed <- new.DefaultSite
+	}))
+
+	w.Start(context.Background())
+	defer w.Stop()
+
+	writeConfigFile(t, path, sprintfConfig("site-updated"))
+
+	select {
+	case <-failures:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the failed load")
+	}
+	// The unchanged file is loaded again on a later tick
+	select {
+	case site := <-changed:
+		if site != "site-updated" {
+			t.Errorf("expected 'site-updated', got '%s'", site)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the retried reload")
+	}
+}
@@ -0,0 +1,145 @@
+package debug
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+// SetEchoLogger allows applications to inject an Echo logger used by this package
+func SetEchoLogger(l echo.Logger) {
+	SetHandler(NewEchoHandler(l))
+}
+
+// NewEchoHandler creates a handler that writes records as JSON to an Echo logger
+func NewEchoHandler(l echo.Logger) slog.Handler {
+	return &fieldsHandler{emit: func(level slog.Level, msg string, fields []any) {
+		j := log.JSON{"message": msg}
+		for i := 0; i+1 < len(fields); i += 2 {
+			j[fields[i].(string)] = fields[i+1]
+		}
+		switch {
+		case level >= slog.LevelError:
+			l.Errorj(j)
+		case level >= slog.LevelWarn:
+			l.Warnj(j)
+		case level >= slog.LevelInfo:
+			l.Infoj(j)
+		default:
+			l.Debugj(j)
+		}
+	}}
+}
+
+// ZapSugaredLogger is the part of *zap.SugaredLogger used by NewZapHandler
+type ZapSugaredLogger interface {
+	Debugw(msg string, keysAndValues ...any)
+	Infow(msg string, keysAndValues ...any)
+	Warnw(msg string, keysAndValues ...any)
+	Errorw(msg string, keysAndValues ...any)
+}
+
+// NewZapHandler creates a handler that writes records to a zap logger:
+//
+//	debug.SetHandler(debug.NewZapHandler(zapLogger.Sugar()))
+func NewZapHandler(l ZapSugaredLogger) slog.Handler {
+	return &fieldsHandler{emit: func(level slog.Level, msg string, fields []any) {
+		switch {
+		case level >= slog.LevelError:
+			l.Errorw(msg, fields...)
+		case level >= slog.LevelWarn:
+			l.Warnw(msg, fields...)
+		case level >= slog.LevelInfo:
+			l.Infow(msg, fields...)
+		default:
+			l.Debugw(msg, fields...)
+		}
+	}}
+}
+
+// NewZerologHandler creates a handler that writes records in zerolog's JSON
+// format ("level", "time" and "message" fields), so they can share a zerolog
+// output or pipeline:
+//
+//	debug.SetHandler(debug.NewZerologHandler(os.Stderr))
+func NewZerologHandler(w io.Writer) slog.Handler {
+	return slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if len(groups) > 0 {
+				return attr
+			}
+			switch attr.Key {
+			case slog.MessageKey:
+				attr.Key = "message"
+			case slog.LevelKey:
+				attr.Value = slog.StringValue(strings.ToLower(attr.Value.String()))
+			}
+			return attr
+		},
+	})
+}
+
+// fieldsHandler is a handler that passes records to a logger as flat key-value
+// fields. Groups become dotted key prefixes.
+type fieldsHandler struct {
+	emit   func(level slog.Level, msg string, fields []any)
+	fields []any
+	prefix string
+}
+
+// Enabled always returns true; levels are applied by the SDK loggers and the target logger
+func (h *fieldsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return true
+}
+
+// Handle emits the record
+func (h *fieldsHandler) Handle(ctx context.Context, r slog.Record) error {
+	fields := make([]any, len(h.fields), len(h.fields)+2*r.NumAttrs())
+	copy(fields, h.fields)
+	r.Attrs(func(attr slog.Attr) bool {
+		fields = appendFields(fields, h.prefix, attr)
+		return true
+	})
+	h.emit(r.Level, r.Message, fields)
+	return nil
+}
+
+// WithAttrs returns a handler with additional fields
+func (h *fieldsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := append([]any{}, h.fields...)
+	for _, attr := range attrs {
+		fields = appendFields(fields, h.prefix, attr)
+	}
+	return &fieldsHandler{emit: h.emit, fields: fields, prefix: h.prefix}
+}
+
+// WithGroup returns a handler that prefixes the keys of new fields
+func (h *fieldsHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &fieldsHandler{emit: h.emit, fields: h.fields, prefix: h.prefix + name + "."}
+}
+
+// appendFields appends an attribute as key-value fields
+func appendFields(fields []any, prefix string, attr slog.Attr) []any {
+	value := attr.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, member := range value.Group() {
+			fields = appendFields(fields, prefix, member)
+		}
+		return fields
+	}
+	if attr.Key == "" {
+		return fields
+	}
+	return append(fields, prefix+attr.Key, value.Any())
+}
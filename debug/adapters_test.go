@@ -0,0 +1,96 @@
+package debug
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/labstack/gommon/log"
+)
+
+// zapCall is a recorded call to the fake zap logger
+type zapCall struct {
+	level  string
+	msg    string
+	fields []any
+}
+
+// fakeZapLogger records the calls of NewZapHandler
+type fakeZapLogger struct {
+	calls []zapCall
+}
+
+func (l *fakeZapLogger) Debugw(msg string, keysAndValues ...any) {
+	l.calls = append(l.calls, zapCall{"debug", msg, keysAndValues})
+}
+
+func (l *fakeZapLogger) Infow(msg string, keysAndValues ...any) {
+	l.calls = append(l.calls, zapCall{"info", msg, keysAndValues})
+}
+
+func (l *fakeZapLogger) Warnw(msg string, keysAndValues ...any) {
+	l.calls = append(l.calls, zapCall{"warn", msg, keysAndValues})
+}
+
+func (l *fakeZapLogger) Errorw(msg string, keysAndValues ...any) {
+	l.calls = append(l.calls, zapCall{"error", msg, keysAndValues})
+}
+
+func TestZapHandler(t *testing.T) {
+	resetLogging(t)
+
+	zap := &fakeZapLogger{}
+	SetHandler(NewZapHandler(zap))
+
+	logger := Logger("proxy").With("site", "main").WithGroup("request")
+	logger.Info("proxied", "path", "/about", slog.Group("upstream", "status", 200, "token", "abc"))
+	logger.Error("failed", slog.Group("", "inline", true))
+	Logger("proxy").Debug("filtered by level")
+	Logger("proxy").Warn("slow")
+
+	expected := []zapCall{
+		{"info", "proxied", []any{
+			NamespaceKey, "content-sdk-go/proxy",
+			"site", "main",
+			"request.path", "/about",
+			"request.upstream.status", int64(200),
+			"request.upstream.token", Redacted,
+		}},
+		{"error", "failed", []any{NamespaceKey, "content-sdk-go/proxy", "site", "main", "request.inline", true}},
+		{"warn", "slow", []any{NamespaceKey, "content-sdk-go/proxy"}},
+	}
+	if !reflect.DeepEqual(zap.calls, expected) {
+		t.Errorf("expected %v, got %v", expected, zap.calls)
+	}
+}
+
+func TestEchoHandler(t *testing.T) {
+	resetLogging(t)
+
+	var buf bytes.Buffer
+	logger := log.New("test")
+	logger.SetOutput(&buf)
+	logger.SetHeader(`{"level":"${level}"}`)
+	logger.SetLevel(log.DEBUG)
+	SetEchoLogger(logger)
+	SetLevel("editing", slog.LevelDebug)
+
+	Logger("editing").WithGroup("request").Warn("rejected", "path", "/editing/render", "secret", "abc")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("expected a JSON record, got %q: %v", buf.String(), err)
+	}
+	expected := map[string]any{
+		"level":          "WARN",
+		"message":        "rejected",
+		NamespaceKey:     "content-sdk-go/editing",
+		"request.path":   "/editing/render",
+		"request.secret": Redacted,
+	}
+	if !reflect.DeepEqual(record, expected) {
+		t.Errorf("expected %v, got %v", expected, record)
+	}
+}
@@ -1,31 +1,15 @@
 package debug
 
 import (
-	"fmt"
-	"os"
-	"strings"
-
-	"github.com/labstack/echo/v4"
+	"log/slog"
 )
 
 const rootNamespace = "content-sdk-go"
 
-var echoLogger echo.Logger
-
-// SetEchoLogger allows applications to inject an Echo logger used by this package
-func SetEchoLogger(l echo.Logger) {
-	echoLogger = l
-}
-
+// debug logs a printf-style message at debug level in a namespace.
+// Enable a namespace with DEBUG or LOG_LEVELS (see Configure).
 func debug(debugModule string, format string, a ...any) {
-	debug := os.Getenv("DEBUG")
-	if debug == "true" || strings.Contains(debug, debugModule) {
-		if echoLogger != nil {
-			echoLogger.Debugf("[%s] %s", debugModule, fmt.Sprintf(format, a...))
-			return
-		}
-		fmt.Printf("[%s] %s\n", debugModule, fmt.Sprintf(format, a...))
-	}
+	logf(debugModule, slog.LevelDebug, format, a...)
 }
 
 func Common(format string, a ...any) {
@@ -79,3 +63,15 @@ func ErrorPages(format string, a ...any) {
 func Proxy(format string, a ...any) {
 	debug(rootNamespace+"/proxy", format, a...)
 }
+
+func Config(format string, a ...any) {
+	debug(rootNamespace+"/config", format, a...)
+}
+
+func RichText(format string, a ...any) {
+	debug(rootNamespace+"/richtext", format, a...)
+}
+
+func Health(format string, a ...any) {
+	debug(rootNamespace+"/health", format, a...)
+}
@@ -0,0 +1,285 @@
+package debug
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Attribute keys for the structured attributes logged by the SDK
+const (
+	SiteKey      = "site"
+	LocaleKey    = "locale"
+	PathKey      = "path"
+	RequestIDKey = "request_id"
+	DurationKey  = "duration"
+	NamespaceKey = "namespace"
+)
+
+// handler is the destination handler of all SDK loggers
+var handler atomic.Pointer[slog.Handler]
+
+// levels holds the default and per-namespace log levels
+var levels = struct {
+	sync.RWMutex
+	defaultLevel slog.Level
+	namespaces   map[string]slog.Level
+}{defaultLevel: slog.LevelInfo}
+
+func init() {
+	Configure()
+}
+
+// Configure applies the logging environment variables:
+//   - LOG_LEVEL: the default level (debug, info, warn, error; default: info)
+//   - LOG_LEVELS: per-namespace levels, e.g. "editing=debug,graphql=warn"
+//   - LOG_FORMAT: "json" or "text" (default: text)
+//   - DEBUG: "true" for all namespaces, or a comma-separated list of namespaces logged at debug level
+//
+// Namespaces can be given with or without the "content-sdk-go/" prefix.
+func Configure() {
+	levels.Lock()
+	levels.defaultLevel = slog.LevelInfo
+	levels.namespaces = map[string]slog.Level{}
+	levels.Unlock()
+
+	if level, ok := parseLevel(os.Getenv("LOG_LEVEL")); ok {
+		SetDefaultLevel(level)
+	}
+	for _, entry := range splitList(os.Getenv("LOG_LEVELS")) {
+		if namespace, value, found := strings.Cut(entry, "="); found {
+			if level, ok := parseLevel(value); ok {
+				SetLevel(namespace, level)
+			}
+		}
+	}
+	for _, namespace := range splitList(os.Getenv("DEBUG")) {
+		if namespace == "true" || namespace == "1" || namespace == "*" {
+			namespace = rootNamespace
+		}
+		SetLevel(namespace, slog.LevelDebug)
+	}
+
+	var h slog.Handler
+	options := &slog.HandlerOptions{Level: slog.LevelDebug}
+	if strings.EqualFold(os.Getenv("LOG_FORMAT"), "json") {
+		h = slog.NewJSONHandler(os.Stdout, options)
+	} else {
+		h = slog.NewTextHandler(os.Stdout, options)
+	}
+	SetHandler(h)
+}
+
+// SetHandler sets the handler SDK logs are written to. Levels, context
+// attributes and redaction are applied before records reach it, so it should
+// accept debug records.
+func SetHandler(h slog.Handler) {
+	handler.Store(&h)
+}
+
+// SetLogger writes SDK logs to an application logger
+func SetLogger(l *slog.Logger) {
+	SetHandler(l.Handler())
+}
+
+// SetDefaultLevel sets the level of namespaces without their own level
+func SetDefaultLevel(level slog.Level) {
+	levels.Lock()
+	defer levels.Unlock()
+	levels.defaultLevel = level
+}
+
+// SetLevel sets the level of a namespace and its sub-namespaces.
+// SetLevel("content-sdk-go", ...) applies to every namespace without a more specific level.
+func SetLevel(namespace string, level slog.Level) {
+	levels.Lock()
+	defer levels.Unlock()
+	levels.namespaces[qualify(namespace)] = level
+}
+
+// LevelFor returns the level of a namespace, using the most specific configured level
+func LevelFor(namespace string) slog.Level {
+	levels.RLock()
+	defer levels.RUnlock()
+
+	namespace = qualify(namespace)
+	for {
+		if level, ok := levels.namespaces[namespace]; ok {
+			return level
+		}
+		i := strings.LastIndex(namespace, "/")
+		if i < 0 {
+			return levels.defaultLevel
+		}
+		namespace = namespace[:i]
+	}
+}
+
+// Logger returns the structured logger of a namespace, e.g. Logger("editing").
+// Records are filtered by the namespace level, get the attributes stored with
+// WithAttrs and are redacted before they reach the handler.
+func Logger(namespace string) *slog.Logger {
+	namespace = qualify(namespace)
+	return slog.New(&sdkHandler{namespace: namespace}).With(NamespaceKey, namespace)
+}
+
+// contextAttrsKey is the context key for attributes added with WithAttrs
+type contextAttrsKey struct{}
+
+// WithAttrs returns a copy of ctx carrying attributes that are added to every
+// record logged with it, such as the request ID, site and locale
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing, _ := ctx.Value(contextAttrsKey{}).([]slog.Attr)
+	merged := make([]slog.Attr, 0, len(existing)+len(attrs))
+	merged = append(merged, existing...)
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, contextAttrsKey{}, merged)
+}
+
+// handlerOp is a WithAttrs or WithGroup call replayed on the current handler
+type handlerOp struct {
+	group string
+	attrs []slog.Attr
+}
+
+// sdkHandler applies namespace levels, context attributes and redaction,
+// then forwards records to the current handler
+type sdkHandler struct {
+	namespace string
+	ops       []handlerOp
+}
+
+// Enabled reports whether the namespace logs at the level
+func (h *sdkHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= LevelFor(h.namespace)
+}
+
+// Handle redacts the record and forwards it
+func (h *sdkHandler) Handle(ctx context.Context, r slog.Record) error {
+	record := slog.NewRecord(r.Time, r.Level, Redact(r.Message), r.PC)
+	if attrs, ok := ctx.Value(contextAttrsKey{}).([]slog.Attr); ok {
+		for _, attr := range attrs {
+			record.AddAttrs(redactAttr(attr))
+		}
+	}
+	r.Attrs(func(attr slog.Attr) bool {
+		record.AddAttrs(redactAttr(attr))
+		return true
+	})
+
+	inner := *handler.Load()
+	for _, op := range h.ops {
+		if op.group != "" {
+			inner = inner.WithGroup(op.group)
+		} else {
+			inner = inner.WithAttrs(op.attrs)
+		}
+	}
+	if !inner.Enabled(ctx, record.Level) {
+		return nil
+	}
+	return inner.Handle(ctx, record)
+}
+
+// WithAttrs returns a handler with redacted attributes
+func (h *sdkHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = redactAttr(attr)
+	}
+	return h.with(handlerOp{attrs: redacted})
+}
+
+// WithGroup returns a handler with a group
+func (h *sdkHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.with(handlerOp{group: name})
+}
+
+// with returns a copy of the handler with an additional operation
+func (h *sdkHandler) with(op handlerOp) *sdkHandler {
+	ops := make([]handlerOp, len(h.ops), len(h.ops)+1)
+	copy(ops, h.ops)
+	return &sdkHandler{namespace: h.namespace, ops: append(ops, op)}
+}
+
+// logf logs a printf-style message in a namespace
+func logf(namespace string, level slog.Level, format string, a ...any) {
+	ctx := context.Background()
+	logger := namespaceLoggers.get(namespace)
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	// Skip logf, debug and the namespace helper so the record points at the caller
+	var pcs [1]uintptr
+	runtime.Callers(4, pcs[:])
+	record := slog.NewRecord(time.Now(), level, fmt.Sprintf(format, a...), pcs[0])
+	logger.Handler().Handle(ctx, record)
+}
+
+// namespaceLoggers caches the loggers of the printf-style helpers
+var namespaceLoggers = &loggerCache{loggers: map[string]*slog.Logger{}}
+
+// loggerCache is a concurrency-safe map of namespace loggers
+type loggerCache struct {
+	mu      sync.RWMutex
+	loggers map[string]*slog.Logger
+}
+
+// get returns the logger of a namespace
+func (c *loggerCache) get(namespace string) *slog.Logger {
+	c.mu.RLock()
+	logger, ok := c.loggers[namespace]
+	c.mu.RUnlock()
+	if ok {
+		return logger
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	logger = Logger(namespace)
+	c.loggers[namespace] = logger
+	return logger
+}
+
+// qualify prefixes a namespace with the root namespace
+func qualify(namespace string) string {
+	namespace = strings.Trim(strings.TrimSpace(namespace), "/*")
+	if namespace == "" {
+		return rootNamespace
+	}
+	if namespace == rootNamespace || strings.HasPrefix(namespace, rootNamespace+"/") {
+		return namespace
+	}
+	return rootNamespace + "/" + namespace
+}
+
+// parseLevel parses a level name
+func parseLevel(value string) (slog.Level, bool) {
+	var level slog.Level
+	if strings.TrimSpace(value) == "" {
+		return level, false
+	}
+	err := level.UnmarshalText([]byte(strings.TrimSpace(value)))
+	return level, err == nil
+}
+
+// splitList splits a comma-separated list
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
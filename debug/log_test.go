@@ -0,0 +1,130 @@
+package debug
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+// resetLogging restores the environment configuration after a test
+func resetLogging(t *testing.T) {
+	t.Helper()
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("LOG_LEVELS", "")
+	t.Setenv("DEBUG", "")
+	t.Setenv("LOG_FORMAT", "")
+	// Registered before the variables are restored, so it runs after them
+	t.Cleanup(Configure)
+	Configure()
+}
+
+func TestLevelFor(t *testing.T) {
+	resetLogging(t)
+
+	SetLevel("editing", slog.LevelDebug)
+	SetLevel("content-sdk-go/graphql", slog.LevelError)
+
+	tests := []struct {
+		namespace string
+		expected  slog.Level
+	}{
+		{"editing", slog.LevelDebug},
+		{"content-sdk-go/editing", slog.LevelDebug},
+		{"editing/secret", slog.LevelDebug},
+		{"/editing/", slog.LevelDebug},
+		{"graphql", slog.LevelError},
+		{"layout", slog.LevelInfo},
+		{"", slog.LevelInfo},
+	}
+	for _, tt := range tests {
+		if level := LevelFor(tt.namespace); level != tt.expected {
+			t.Errorf("LevelFor(%q): expected %v, got %v", tt.namespace, tt.expected, level)
+		}
+	}
+
+	// The root namespace applies to namespaces without a more specific level
+	SetLevel("content-sdk-go", slog.LevelWarn)
+	SetDefaultLevel(slog.LevelError)
+	if level := LevelFor("layout"); level != slog.LevelWarn {
+		t.Errorf("expected layout to inherit the root level, got %v", level)
+	}
+	if level := LevelFor("editing/secret"); level != slog.LevelDebug {
+		t.Errorf("expected editing/secret to keep the editing level, got %v", level)
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	resetLogging(t)
+
+	t.Setenv("LOG_LEVEL", "warn")
+	t.Setenv("LOG_LEVELS", "editing=debug, graphql = error ,layout=verbose,proxy")
+	t.Setenv("DEBUG", "content-sdk-go/sitemap, locale")
+	Configure()
+
+	tests := []struct {
+		namespace string
+		expected  slog.Level
+	}{
+		{"editing", slog.LevelDebug},
+		{"graphql", slog.LevelError},
+		{"sitemap", slog.LevelDebug},
+		{"locale", slog.LevelDebug},
+		// Invalid entries are ignored
+		{"layout", slog.LevelWarn},
+		{"proxy", slog.LevelWarn},
+		{"multisite", slog.LevelWarn},
+	}
+	for _, tt := range tests {
+		if level := LevelFor(tt.namespace); level != tt.expected {
+			t.Errorf("LevelFor(%q): expected %v, got %v", tt.namespace, tt.expected, level)
+		}
+	}
+
+	for _, value := range []string{"true", "1", "*"} {
+		t.Setenv("DEBUG", value)
+		Configure()
+		if level := LevelFor("multisite"); level != slog.LevelDebug {
+			t.Errorf("DEBUG=%s: expected all namespaces at debug level, got %v", value, level)
+		}
+	}
+
+	t.Setenv("DEBUG", "")
+	t.Setenv("LOG_LEVELS", "")
+	t.Setenv("LOG_LEVEL", "")
+	Configure()
+	if level := LevelFor("multisite"); level != slog.LevelInfo {
+		t.Errorf("expected default info level, got %v", level)
+	}
+}
+
+func TestLogger(t *testing.T) {
+	resetLogging(t)
+
+	var buf bytes.Buffer
+	SetHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	SetLevel("editing", slog.LevelDebug)
+
+	ctx := WithAttrs(context.Background(), slog.String(SiteKey, "main"))
+	ctx = WithAttrs(ctx, slog.String(PathKey, "/about?secret=abc"))
+	Logger("editing").DebugContext(ctx, "render started", "apiKey", "0123-abcd")
+	Logger("layout").DebugContext(ctx, "filtered by level")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("expected a single JSON record, got %q: %v", buf.String(), err)
+	}
+	expected := map[string]any{
+		NamespaceKey: "content-sdk-go/editing",
+		SiteKey:      "main",
+		PathKey:      "/about?secret=[REDACTED]",
+		"apiKey":     Redacted,
+		"msg":        "render started",
+	}
+	for key, value := range expected {
+		if record[key] != value {
+			t.Errorf("%s: expected %v, got %v", key, value, record[key])
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package debug
+
+import (
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces redacted values
+const Redacted = "[REDACTED]"
+
+// minSecretLength is the shortest registered secret that is redacted, so
+// short placeholder values don't redact unrelated text
+const minSecretLength = 6
+
+// sensitiveKeys are attribute key fragments whose values are always redacted
+var sensitiveKeys = []string{"secret", "apikey", "password", "token", "authorization", "cookie", "credential"}
+
+// sensitivePattern matches key=value and key: value pairs with sensitive keys in
+// messages, such as query strings, headers and formatted maps
+var sensitivePattern = regexp.MustCompile(`(?i)\b((?:sc_)?api[_-]?key|x-api-key|[a-z_-]*secret|[a-z_]*token|password|authorization)("?\s*[:=]\s*"?)([^\s&"',;\]}]+)`)
+
+// secrets are the registered secret values
+var secrets = struct {
+	sync.RWMutex
+	values []string
+}{}
+
+// RegisterSecret redacts a secret value, such as an API key, wherever it
+// appears in SDK log messages and attributes
+func RegisterSecret(secret string) {
+	if len(secret) < minSecretLength {
+		return
+	}
+	secrets.Lock()
+	defer secrets.Unlock()
+	if !slices.Contains(secrets.values, secret) {
+		secrets.values = append(secrets.values, secret)
+	}
+}
+
+// Redact replaces registered secrets and the values of sensitive key=value pairs
+func Redact(s string) string {
+	secrets.RLock()
+	for _, secret := range secrets.values {
+		s = strings.ReplaceAll(s, secret, Redacted)
+	}
+	secrets.RUnlock()
+
+	return sensitivePattern.ReplaceAllString(s, "${1}${2}"+Redacted)
+}
+
+// isSensitiveKey checks if an attribute key names a secret
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+	for _, fragment := range sensitiveKeys {
+		if strings.Contains(key, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactAttr redacts an attribute, recursing into groups
+func redactAttr(attr slog.Attr) slog.Attr {
+	if isSensitiveKey(attr.Key) {
+		return slog.String(attr.Key, Redacted)
+	}
+
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, Redact(value.String()))
+	case slog.KindGroup:
+		group := value.Group()
+		redacted := make([]any, len(group))
+		for i, member := range group {
+			redacted[i] = redactAttr(member)
+		}
+		return slog.Group(attr.Key, redacted...)
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			return slog.String(attr.Key, Redact(err.Error()))
+		}
+		return slog.Attr{Key: attr.Key, Value: value}
+	}
+	return slog.Attr{Key: attr.Key, Value: value}
+}
@@ -0,0 +1,76 @@
+package debug
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"query string", "GET /api/layout?sc_apikey=0123-abcd&site=main", "GET /api/layout?sc_apikey=[REDACTED]&site=main"},
+		{"query string token", "/editing/render?secret=abc&token=xyz", "/editing/render?secret=[REDACTED]&token=[REDACTED]"},
+		{"header", "X-Api-Key: 0123-abcd", "X-Api-Key: [REDACTED]"},
+		{"authorization header", "authorization=Bearer", "authorization=[REDACTED]"},
+		{"formatted map", "config map[apiKey:0123-abcd site:main]", "config map[apiKey:[REDACTED] site:main]"},
+		{"JSON", `{"editingSecret":"s3cr3t","site":"main"}`, `{"editingSecret":"[REDACTED]","site":"main"}`},
+		{"password", "password = hunter2", "password = [REDACTED]"},
+		{"unrelated", "fetched /about for site main", "fetched /about for site main"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if redacted := Redact(tt.input); redacted != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, redacted)
+			}
+		})
+	}
+}
+
+func TestRegisterSecret(t *testing.T) {
+	RegisterSecret("edge-context-id-42")
+	RegisterSecret("abcde")
+
+	if redacted := Redact("endpoint https://edge.example.com/v1?context=edge-context-id-42"); redacted != "endpoint https://edge.example.com/v1?context=[REDACTED]" {
+		t.Errorf("expected registered secret to be redacted, got %q", redacted)
+	}
+	// Values shorter than minSecretLength are not registered
+	if redacted := Redact("abcdef label abcde"); redacted != "abcdef label abcde" {
+		t.Errorf("expected short secret to be ignored, got %q", redacted)
+	}
+	if len("abcde") >= minSecretLength {
+		t.Fatalf("test secret must be shorter than %d", minSecretLength)
+	}
+}
+
+func TestRedactAttr(t *testing.T) {
+	tests := []struct {
+		name     string
+		attr     slog.Attr
+		expected string
+	}{
+		{"sensitive key", slog.String("X-Api-Key", "anything"), Redacted},
+		{"sensitive key with other kind", slog.Int("sessionToken", 42), Redacted},
+		{"string value", slog.String("url", "/about?sc_apikey=1234"), "/about?sc_apikey=[REDACTED]"},
+		{"error value", slog.Any("error", errors.New("request failed: token=abc")), "request failed: token=[REDACTED]"},
+		{"other value", slog.Int("status", 200), "200"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if value := redactAttr(tt.attr).Value.String(); value != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, value)
+			}
+		})
+	}
+
+	group := redactAttr(slog.Group("request", slog.String("path", "/about"), slog.String("cookie", "session=1")))
+	members := group.Value.Group()
+	if len(members) != 2 || members[0].Value.String() != "/about" || members[1].Value.String() != Redacted {
+		t.Errorf("expected group members to be redacted, got %v", members)
+	}
+}
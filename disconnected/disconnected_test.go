@@ -0,0 +1,194 @@
+package disconnected_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guitarrich/content-sdk-go/disconnected"
+	"github.com/guitarrich/content-sdk-go/i18n"
+	layoutservice "github.com/guitarrich/content-sdk-go/layoutService"
+	"github.com/guitarrich/content-sdk-go/site"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ layoutservice.LayoutFetcher = (*disconnected.LayoutService)(nil)
+	_ i18n.DictionaryService      = (*disconnected.DictionaryService)(nil)
+	_ site.SiteInfoService        = (*disconnected.SiteInfoService)(nil)
+)
+
+// writeFiles writes files relative to a data directory
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o644))
+	}
+}
+
+func newStore(t *testing.T) (*disconnected.Store, string) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"routes/en/index.json":        `{"sitecore": {"route": {"name": "home"}}}`,
+		"routes/en/about/index.json":  `{"sitecore": {"context": {"itemPath": "/about-us"}, "route": {"name": "about", "placeholders": {"headless-main": [{"componentName": "RichText"}]}}}}`,
+		"brand/routes/en/index.json":  `{"sitecore": {"route": {"name": "brand-home"}}}`,
+		"routes/en/broken/index.json": `{"sitecore": `,
+		"dictionary/en.json":          `{"greeting": "Hello"}`,
+		"brand/dictionary/en.json":    `{"greeting": "Howdy"}`,
+		"sites.json":                  `[{"name": "main", "hostName": "localhost"}, {"name": "brand", "hostName": "brand.localhost"}]`,
+	})
+	return disconnected.NewStore(disconnected.Config{DataDir: dir, Interval: 10 * time.Millisecond}), dir
+}
+
+func TestLayoutService(t *testing.T) {
+	store, _ := newStore(t)
+	layout := disconnected.NewLayoutService(store)
+	locale := "en"
+
+	data, err := layout.FetchLayoutData("/about/", layoutservice.RouteOptions{Site: "main", Locale: &locale}, nil)
+	require.NoError(t, err)
+	require.NotNil(t, data.Sitecore.Route)
+	assert.Equal(t, "about", data.Sitecore.Route.Name)
+	assert.Equal(t, "RichText", data.Sitecore.Route.Placeholders["headless-main"][0].ComponentName)
+	assert.Equal(t, "/about-us", *data.Sitecore.Context.ItemPath)
+	assert.Equal(t, "en", *data.Sitecore.Context.Language)
+	assert.Equal(t, "main", *data.Sitecore.Context.Site.Name)
+	assert.False(t, *data.Sitecore.Context.PageEditing)
+
+	// Site routes take precedence over shared routes
+	data, err = layout.FetchLayoutData("/", layoutservice.RouteOptions{Site: "brand", Locale: &locale}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "brand-home", data.Sitecore.Route.Name)
+
+	data, err = layout.FetchLayoutData("/about", layoutservice.RouteOptions{Site: "brand", Locale: &locale}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "about", data.Sitecore.Route.Name)
+}
+
+func TestLayoutService_NotFound(t *testing.T) {
+	store, _ := newStore(t)
+	layout := disconnected.NewLayoutService(store)
+
+	fr := "fr"
+	data, err := layout.FetchLayoutData("/about", layoutservice.RouteOptions{Site: "main", Locale: &fr}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, data.Sitecore.Route)
+	assert.Equal(t, "fr", *data.Sitecore.Context.Language)
+
+	// Paths can't leave the data directory
+	escape := "../en"
+	data, err = layout.FetchLayoutData("/", layoutservice.RouteOptions{Site: "main", Locale: &escape}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, data.Sitecore.Route)
+
+	data, err = layout.FetchLayoutData("/../../../etc", layoutservice.RouteOptions{Site: "../..", Locale: &fr}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, data.Sitecore.Route)
+}
+
+func TestLayoutService_InvalidFile(t *testing.T) {
+	store, _ := newStore(t)
+	locale := "en"
+
+	_, err := disconnected.NewLayoutService(store).FetchLayoutData("/broken", layoutservice.RouteOptions{Locale: &locale}, nil)
+	assert.ErrorContains(t, err, "failed to parse")
+}
+
+func TestLayoutService_ReadsEditedFiles(t *testing.T) {
+	store, dir := newStore(t)
+	layout := disconnected.NewLayoutService(store)
+	locale := "en"
+
+	writeFiles(t, dir, map[string]string{"routes/en/index.json": `{"sitecore": {"route": {"name": "edited"}}}`})
+	data, err := layout.FetchLayoutData("/", layoutservice.RouteOptions{Locale: &locale}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "edited", data.Sitecore.Route.Name)
+}
+
+func TestDictionaryService(t *testing.T) {
+	store, _ := newStore(t)
+	dictionary := disconnected.NewDictionaryService(store)
+	ctx := context.Background()
+
+	phrases, err := dictionary.FetchDictionaryData(ctx, "en", "main")
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", phrases["greeting"])
+
+	phrases, err = dictionary.FetchDictionaryData(ctx, "en", "brand")
+	require.NoError(t, err)
+	assert.Equal(t, "Howdy", phrases["greeting"])
+
+	phrases, err = dictionary.FetchDictionaryData(ctx, "de", "main")
+	require.NoError(t, err)
+	assert.Empty(t, phrases)
+}
+
+func TestSiteInfoService(t *testing.T) {
+	store, _ := newStore(t)
+	sites := disconnected.NewSiteInfoService(store)
+	ctx := context.Background()
+
+	all, err := sites.FetchSites(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	brand, err := sites.FetchSiteInfo(ctx, "Brand")
+	require.NoError(t, err)
+	assert.Equal(t, "brand.localhost", brand.HostName)
+
+	_, err = sites.FetchSiteInfo(ctx, "missing")
+	assert.ErrorContains(t, err, "siteInfo not found for site missing")
+}
+
+func TestRouteService(t *testing.T) {
+	store, _ := newStore(t)
+	routes := disconnected.NewRouteService(store)
+
+	paths, err := routes.ListRoutes(context.Background(), []string{"main", "brand"}, []string{"en", "de"})
+	require.NoError(t, err)
+
+	var listed []string
+	for _, path := range paths {
+		assert.False(t, path.LastModified.IsZero())
+		listed = append(listed, path.Site+"|"+path.Locale+"|/"+strings.Join(path.Path, "/"))
+	}
+	assert.Equal(t, []string{
+		"main|en|/",
+		"main|en|/about",
+		"main|en|/broken",
+		"brand|en|/",
+		"brand|en|/about",
+		"brand|en|/broken",
+	}, listed)
+}
+
+func TestStore_NotifiesChanges(t *testing.T) {
+	store, dir := newStore(t)
+	changes := make(chan []string, 10)
+	unsubscribe := store.Subscribe(func(changed []string) { changes <- changed })
+	defer unsubscribe()
+
+	store.Start(context.Background())
+	defer store.Stop()
+
+	writeFiles(t, dir, map[string]string{"routes/en/contact/index.json": `{"sitecore": {"route": {"name": "contact"}}}`})
+	require.NoError(t, os.Remove(filepath.Join(dir, "sites.json")))
+
+	// The changes may be reported in one or two notifications
+	var changed []string
+	for len(changed) < 2 {
+		select {
+		case files := <-changes:
+			changed = append(changed, files...)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("expected change notifications, got %v", changed)
+		}
+	}
+	assert.ElementsMatch(t, []string{"routes/en/contact/index.json", "sites.json"}, changed)
+}
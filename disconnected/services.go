@@ -0,0 +1,186 @@
+package disconnected
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/guitarrich/content-sdk-go/debug"
+	layoutservice "github.com/guitarrich/content-sdk-go/layoutService"
+	"github.com/guitarrich/content-sdk-go/models"
+)
+
+// LayoutService serves layout data from route files; it implements layoutservice.LayoutFetcher
+type LayoutService struct {
+	store *Store
+}
+
+// NewLayoutService creates a layout service reading routes from the store
+func NewLayoutService(store *Store) *LayoutService {
+	return &LayoutService{store: store}
+}
+
+// FetchLayoutData fetches layout data for an item
+// It is FetchLayoutDataContext with a background context.
+func (ls *LayoutService) FetchLayoutData(
+	itemPath string,
+	routeOptions layoutservice.RouteOptions,
+	fetchOptions *layoutservice.FetchOptions,
+) (*layoutservice.LayoutServiceData, error) {
+	return ls.FetchLayoutDataContext(context.Background(), itemPath, routeOptions, fetchOptions)
+}
+
+// FetchLayoutDataContext reads <language>/<path>/index.json below the site's
+// routes directory, falling back to the shared routes directory. A missing
+// file returns layout data without route, which SitecoreClient reports as
+// not found. Context fields missing from the file are filled in.
+func (ls *LayoutService) FetchLayoutDataContext(
+	ctx context.Context,
+	itemPath string,
+	routeOptions layoutservice.RouteOptions,
+	fetchOptions *layoutservice.FetchOptions,
+) (*layoutservice.LayoutServiceData, error) {
+	language := "en"
+	if routeOptions.Locale != nil && *routeOptions.Locale != "" {
+		language = *routeOptions.Locale
+	}
+	routePath := path.Clean("/" + itemPath)
+	debug.Layout("disconnected: fetching layout data for %s %s %s", routePath, language, routeOptions.Site)
+
+	name := path.Join("routes", language, routePath, "index.json")
+	var layoutData layoutservice.LayoutServiceData
+	found := false
+	if !strings.ContainsAny(language, `/\`) && language != ".." {
+		var err error
+		if found, err = ls.store.readJSON(&layoutData, siteFiles(routeOptions.Site, name)...); err != nil {
+			return nil, fmt.Errorf("failed to read layout data: %w", err)
+		}
+	}
+	if !found {
+		debug.Layout("disconnected: no route file %s", name)
+		layoutData = layoutservice.LayoutServiceData{}
+	}
+
+	sitecoreContext := &layoutData.Sitecore.Context
+	if sitecoreContext.PageEditing == nil {
+		pageEditing := false
+		sitecoreContext.PageEditing = &pageEditing
+	}
+	if sitecoreContext.Language == nil {
+		sitecoreContext.Language = &language
+	}
+	if found && sitecoreContext.ItemPath == nil {
+		sitecoreContext.ItemPath = &routePath
+	}
+	if found && sitecoreContext.Site == nil && routeOptions.Site != "" {
+		site := routeOptions.Site
+		sitecoreContext.Site = &struct {
+			Name *string `json:"name,omitempty"`
+		}{Name: &site}
+	}
+	return &layoutData, nil
+}
+
+// DictionaryService serves dictionary phrases from dictionary files; it implements i18n.DictionaryService
+type DictionaryService struct {
+	store *Store
+}
+
+// NewDictionaryService creates a dictionary service reading phrases from the store
+func NewDictionaryService(store *Store) *DictionaryService {
+	return &DictionaryService{store: store}
+}
+
+// FetchDictionaryData reads dictionary/<locale>.json of the site, falling back
+// to the shared dictionary. A missing file returns no phrases.
+func (s *DictionaryService) FetchDictionaryData(ctx context.Context, locale, siteName string) (models.DictionaryPhrases, error) {
+	phrases := models.DictionaryPhrases{}
+	if strings.ContainsAny(locale, `/\`) || locale == ".." {
+		return phrases, nil
+	}
+	if _, err := s.store.readJSON(&phrases, siteFiles(siteName, "dictionary/"+locale+".json")...); err != nil {
+		return nil, fmt.Errorf("failed to read dictionary: %w", err)
+	}
+	return phrases, nil
+}
+
+// SiteInfoService serves sites from sites.json; it implements site.SiteInfoService
+type SiteInfoService struct {
+	store *Store
+}
+
+// NewSiteInfoService creates a site info service reading sites from the store
+func NewSiteInfoService(store *Store) *SiteInfoService {
+	return &SiteInfoService{store: store}
+}
+
+// FetchSiteInfo returns a site from sites.json
+func (s *SiteInfoService) FetchSiteInfo(ctx context.Context, siteName string) (*models.SiteInfo, error) {
+	sites, err := s.FetchSites(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sites {
+		if strings.EqualFold(sites[i].Name, siteName) {
+			return &sites[i], nil
+		}
+	}
+	return nil, fmt.Errorf("siteInfo not found for site %s", siteName)
+}
+
+// FetchSites returns the sites of sites.json; a missing file returns no sites
+func (s *SiteInfoService) FetchSites(ctx context.Context) ([]models.SiteInfo, error) {
+	sites := []models.SiteInfo{}
+	if _, err := s.store.readJSON(&sites, "sites.json"); err != nil {
+		return nil, fmt.Errorf("failed to read sites: %w", err)
+	}
+	return sites, nil
+}
+
+// RouteService lists routes from route files; it implements client.RouteLister
+type RouteService struct {
+	store *Store
+}
+
+// NewRouteService creates a route service listing the routes of the store
+func NewRouteService(store *Store) *RouteService {
+	return &RouteService{store: store}
+}
+
+// ListRoutes returns the routes of each site and language: the directories with
+// an index.json below the site's routes directory and the shared routes directory.
+// The modification time of a route file is the route's last modification time.
+func (s *RouteService) ListRoutes(ctx context.Context, sites []string, languages []string) ([]models.StaticPath, error) {
+	paths := []models.StaticPath{}
+	for _, site := range sites {
+		for _, language := range languages {
+			if strings.ContainsAny(language, `/\`) || language == ".." {
+				continue
+			}
+
+			seen := make(map[string]bool)
+			for _, name := range siteFiles(site, "routes/"+language) {
+				routes, err := s.store.routeFiles(name)
+				if err != nil {
+					return nil, fmt.Errorf("failed to list routes for site %s, language %s: %w", site, language, err)
+				}
+				for _, route := range routes {
+					if seen[route.path] {
+						continue
+					}
+					seen[route.path] = true
+					paths = append(paths, models.StaticPath{
+						Site:         site,
+						Locale:       language,
+						Path:         strings.FieldsFunc(route.path, func(r rune) bool { return r == '/' }),
+						LastModified: route.modTime,
+					})
+				}
+			}
+		}
+	}
+
+	debug.Layout("disconnected: found %d routes for sites=%v, languages=%v", len(paths), sites, languages)
+	return paths, nil
+}
@@ -0,0 +1,267 @@
+// Package disconnected serves layout, dictionary and site data from local
+// JSON files, for front-end development without a Sitecore instance.
+//
+// The data directory mirrors the site's routes:
+//
+//	data/
+//	├── routes/
+//	│   └── en/
+//	│       ├── index.json           # "/" in English
+//	│       └── about/
+//	│           └── index.json       # "/about"
+//	├── dictionary/
+//	│   └── en.json                  # {"key": "phrase"}
+//	├── sites.json                   # [models.SiteInfo]
+//	└── <site>/                      # optional per-site routes and dictionary
+//	    ├── routes/
+//	    └── dictionary/
+//
+// Route files contain layout service data ({"sitecore": {"context": ..., "route": ...}}).
+// Files are read on every request, so edits show up on the next page load;
+// Store.Start additionally notifies subscribers of changes, e.g. to reload
+// the browser.
+package disconnected
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/guitarrich/content-sdk-go/debug"
+)
+
+// Config contains configuration for the disconnected store
+type Config struct {
+	// DataDir is the directory content is read from (default: data)
+	DataDir string
+
+	// Interval is how often Start checks the files for changes (default: 1s)
+	Interval time.Duration
+}
+
+// Store reads content files from the data directory
+type Store struct {
+	config Config
+
+	mu          sync.Mutex
+	subscribers map[int]func(changed []string)
+	nextID      int
+	files       map[string]fileStamp
+	stop        context.CancelFunc
+	done        chan struct{}
+}
+
+// fileStamp identifies a version of a file
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewStore creates a store for a data directory
+func NewStore(config Config) *Store {
+	if config.DataDir == "" {
+		config.DataDir = "data"
+	}
+	if config.Interval <= 0 {
+		config.Interval = time.Second
+	}
+
+	return &Store{
+		config:      config,
+		subscribers: make(map[int]func(changed []string)),
+	}
+}
+
+// DataDir returns the data directory
+func (s *Store) DataDir() string {
+	return s.config.DataDir
+}
+
+// Subscribe registers a function called with the files that changed, relative
+// to the data directory, and returns a function that removes it
+func (s *Store) Subscribe(onChange func(changed []string)) (unsubscribe func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.nextID
+	s.nextID++
+	s.subscribers[id] = onChange
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.subscribers, id)
+	}
+}
+
+// Start watches the data directory until ctx is cancelled or Stop is called
+func (s *Store) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	s.mu.Lock()
+	if s.stop != nil {
+		s.mu.Unlock()
+		cancel()
+		return
+	}
+	s.stop = cancel
+	s.done = make(chan struct{})
+	s.files = s.scan()
+	s.mu.Unlock()
+
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.checkChanges()
+			}
+		}
+	}()
+}
+
+// Stop stops watching and waits for the watch loop to exit
+func (s *Store) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop != nil {
+		stop()
+		<-done
+	}
+}
+
+// checkChanges rescans the data directory and notifies subscribers of changed files
+func (s *Store) checkChanges() {
+	files := s.scan()
+
+	s.mu.Lock()
+	var changed []string
+	for name, stamp := range files {
+		if previous, ok := s.files[name]; !ok || previous != stamp {
+			changed = append(changed, name)
+		}
+	}
+	for name := range s.files {
+		if _, ok := files[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	s.files = files
+	subscribers := make([]func(changed []string), 0, len(s.subscribers))
+	for _, subscriber := range s.subscribers {
+		subscribers = append(subscribers, subscriber)
+	}
+	s.mu.Unlock()
+
+	if len(changed) == 0 {
+		return
+	}
+	sort.Strings(changed)
+	debug.Common("disconnected content changed: %v", changed)
+	for _, subscriber := range subscribers {
+		subscriber(changed)
+	}
+}
+
+// scan returns the JSON files of the data directory
+func (s *Store) scan() map[string]fileStamp {
+	files := map[string]fileStamp{}
+	filepath.WalkDir(s.config.DataDir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || filepath.Ext(file) != ".json" {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		name, err := filepath.Rel(s.config.DataDir, file)
+		if err != nil {
+			return nil
+		}
+		files[filepath.ToSlash(name)] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+	return files
+}
+
+// readJSON decodes the first of the given files that exists, relative to the
+// data directory. It returns false when none exists.
+func (s *Store) readJSON(value any, names ...string) (bool, error) {
+	for _, name := range names {
+		file := filepath.Join(s.config.DataDir, filepath.FromSlash(name))
+		data, err := os.ReadFile(file)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		if err := json.Unmarshal(data, value); err != nil {
+			return false, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		debug.Common("disconnected: read %s", file)
+		return true, nil
+	}
+	return false, nil
+}
+
+// routeFile is a route found in a routes directory
+type routeFile struct {
+	path    string
+	modTime time.Time
+}
+
+// routeFiles returns the routes below a directory relative to the data
+// directory, sorted by path: the directories containing an index.json. A missing
+// directory has no routes.
+func (s *Store) routeFiles(dir string) ([]routeFile, error) {
+	root := filepath.Join(s.config.DataDir, filepath.FromSlash(dir))
+	var routes []routeFile
+	err := filepath.WalkDir(root, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if file == root && os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() || entry.Name() != "index.json" {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(root, filepath.Dir(file))
+		if err != nil {
+			return err
+		}
+		routes = append(routes, routeFile{path: path.Clean("/" + filepath.ToSlash(name)), modTime: info.ModTime()})
+		return nil
+	})
+	sort.Slice(routes, func(i, j int) bool { return routes[i].path < routes[j].path })
+	return routes, err
+}
+
+// siteFiles returns the site-specific and shared names of a file
+func siteFiles(site string, name string) []string {
+	if site == "" || site != path.Base(path.Clean("/"+site)) {
+		return []string{name}
+	}
+	return []string{site + "/" + name, name}
+}
@@ -0,0 +1,404 @@
+// Package export renders sites to static HTML.
+//
+// Export lists the routes of each site and language, renders every page
+// through the application's page renderer and writes one index.html per
+// route, plus sitemap.xml, robots.txt and the media the pages reference.
+// With Incremental set, pages whose route hasn't changed since the previous
+// export are skipped. Pages of sites and languages outside an export are kept.
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/guitarrich/content-sdk-go/debug"
+	"github.com/guitarrich/content-sdk-go/handlers"
+	"github.com/guitarrich/content-sdk-go/models"
+	"github.com/guitarrich/content-sdk-go/seo"
+)
+
+// ManifestFile is the file in the output directory that records exported pages
+const ManifestFile = ".export-manifest.json"
+
+// PageSource lists routes and fetches pages; *client.SitecoreClient implements it
+type PageSource interface {
+	GetStaticPathsContext(ctx context.Context, sites []string, languages []string) ([]models.StaticPath, error)
+	GetPageContext(ctx context.Context, path string, options models.PageOptions) (*models.Page, error)
+}
+
+// Config contains configuration for an export
+type Config struct {
+	// Source lists routes and fetches pages
+	Source PageSource
+
+	// Renderer renders pages to HTML
+	Renderer handlers.PageRenderer
+
+	// Sites are the site names to export
+	Sites []string
+
+	// Languages are the languages to export
+	Languages []string
+
+	// DefaultLanguage is written without a language prefix (optional).
+	// Other languages are written below <site>/<language>/.
+	DefaultLanguage string
+
+	// OutputDir is the export root; each site is written to its own directory
+	OutputDir string
+
+	// Concurrency is the number of pages rendered at once (default: 4)
+	Concurrency int
+
+	// Incremental skips pages whose route hasn't changed since the last export
+	// and removes pages whose routes no longer exist
+	Incremental bool
+
+	// Sitemap writes <site>/sitemap.xml (optional)
+	Sitemap seo.SitemapXmlService
+
+	// Robots writes <site>/robots.txt (optional)
+	Robots seo.RobotsService
+
+	// BaseURL is the public URL of the output directory, used for the sitemap link
+	// in robots.txt (optional). A site's sitemap is at <BaseURL>/<site>/sitemap.xml.
+	BaseURL string
+
+	// SiteBaseURLs are the public URLs of site directories by site name, e.g. for
+	// sites served from their own host (optional). They take precedence over BaseURL.
+	SiteBaseURLs map[string]string
+
+	// MediaServerURL is the origin root-relative media URLs are fetched from.
+	// Media isn't exported when empty.
+	MediaServerURL string
+
+	// MediaPaths are the path prefixes of media URLs (default: /-/media/, /-/jssmedia/)
+	MediaPaths []string
+
+	// HTTPClient fetches media (default: client with a 30s timeout)
+	HTTPClient *http.Client
+}
+
+// Result summarizes an export
+type Result struct {
+	// Rendered is the number of pages rendered
+	Rendered int
+
+	// Skipped is the number of unchanged pages skipped by an incremental export
+	Skipped int
+
+	// Removed is the number of pages removed because their route no longer exists
+	Removed int
+
+	// Media is the number of media files written
+	Media int
+
+	// Errors are the per-page and per-file errors; the export continues past them
+	Errors []error
+}
+
+// manifestEntry records an exported page
+type manifestEntry struct {
+	File         string    `json:"file"`
+	LastModified time.Time `json:"lastModified,omitzero"`
+}
+
+// exporter holds the state of a running export
+type exporter struct {
+	config   Config
+	previous map[string]manifestEntry
+	media    *mediaFetcher
+
+	mu       sync.Mutex
+	result   Result
+	manifest map[string]manifestEntry
+}
+
+// Export renders the configured sites to the output directory. It returns an
+// error when routes can't be listed; page failures are collected in the
+// result and returned joined.
+func Export(ctx context.Context, config Config) (*Result, error) {
+	if config.Source == nil || config.Renderer == nil {
+		return nil, fmt.Errorf("export: source and renderer are required")
+	}
+	if config.OutputDir == "" {
+		return nil, fmt.Errorf("export: output directory is required")
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 4
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	paths, err := config.Source.GetStaticPathsContext(ctx, config.Sites, config.Languages)
+	if err != nil {
+		return nil, fmt.Errorf("export: failed to list routes: %w", err)
+	}
+	debug.Common("exporting %d pages to %s", len(paths), config.OutputDir)
+
+	e := &exporter{
+		config:   config,
+		previous: readManifest(filepath.Join(config.OutputDir, ManifestFile)),
+		manifest: map[string]manifestEntry{},
+	}
+	if config.MediaServerURL != "" {
+		e.media = newMediaFetcher(config)
+	}
+
+	// Render pages with bounded concurrency
+	jobs := make(chan models.StaticPath)
+	var wg sync.WaitGroup
+	for range config.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				e.exportPage(ctx, path)
+			}
+		}()
+	}
+	for _, path := range paths {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- path
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return &e.result, err
+	}
+
+	e.carryOver()
+	for _, site := range config.Sites {
+		e.exportSEO(ctx, site)
+	}
+	if err := writeManifest(filepath.Join(config.OutputDir, ManifestFile), e.manifest); err != nil {
+		e.fail(err)
+	}
+
+	debug.Common("export finished: %d rendered, %d skipped, %d removed, %d media, %d errors",
+		e.result.Rendered, e.result.Skipped, e.result.Removed, e.result.Media, len(e.result.Errors))
+	return &e.result, errors.Join(e.result.Errors...)
+}
+
+// exportPage renders and writes a page unless it is unchanged
+func (e *exporter) exportPage(ctx context.Context, path models.StaticPath) {
+	key := manifestKey(path)
+	file := e.pageFile(path)
+	entry := manifestEntry{File: file, LastModified: path.LastModified}
+
+	if previous, ok := e.previous[key]; ok && e.config.Incremental && e.unchanged(previous, entry) {
+		e.mu.Lock()
+		e.result.Skipped++
+		e.manifest[key] = entry
+		e.mu.Unlock()
+		return
+	}
+
+	route := "/" + strings.Join(path.Path, "/")
+	locale := path.Locale
+	page, err := e.config.Source.GetPageContext(ctx, route, models.PageOptions{Site: path.Site, Locale: &locale})
+	if err != nil {
+		e.failPage(key, fmt.Errorf("%s %s %s: %w", path.Site, path.Locale, route, err))
+		return
+	}
+
+	component, err := e.config.Renderer.RenderPage(ctx, page)
+	if err != nil {
+		e.failPage(key, fmt.Errorf("%s %s %s: %w", path.Site, path.Locale, route, err))
+		return
+	}
+	var html bytes.Buffer
+	if err := component.Render(ctx, &html); err != nil {
+		e.failPage(key, fmt.Errorf("%s %s %s: render failed: %w", path.Site, path.Locale, route, err))
+		return
+	}
+
+	if err := writeFile(filepath.Join(e.config.OutputDir, file), html.Bytes()); err != nil {
+		e.failPage(key, err)
+		return
+	}
+
+	e.mu.Lock()
+	e.result.Rendered++
+	e.manifest[key] = entry
+	e.mu.Unlock()
+
+	if e.media != nil {
+		written, errs := e.media.fetchReferenced(ctx, path.Site, html.Bytes())
+		e.mu.Lock()
+		e.result.Media += written
+		e.result.Errors = append(e.result.Errors, errs...)
+		e.mu.Unlock()
+	}
+}
+
+// unchanged reports whether a page can be skipped
+func (e *exporter) unchanged(previous, current manifestEntry) bool {
+	if current.LastModified.IsZero() || !previous.LastModified.Equal(current.LastModified) || previous.File != current.File {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(e.config.OutputDir, current.File))
+	return err == nil
+}
+
+// carryOver handles the pages of the previous export that weren't exported now.
+// Pages of sites and languages outside this export are kept in the manifest; with
+// Incremental, the others are removed because their routes no longer exist.
+func (e *exporter) carryOver() {
+	for key, entry := range e.previous {
+		if _, ok := e.manifest[key]; ok {
+			continue
+		}
+		if !e.inScope(key) {
+			e.manifest[key] = entry
+			continue
+		}
+		if !e.config.Incremental {
+			continue
+		}
+		err := os.Remove(filepath.Join(e.config.OutputDir, entry.File))
+		if err != nil && !os.IsNotExist(err) {
+			e.fail(err)
+			continue
+		}
+		e.result.Removed++
+	}
+}
+
+// inScope reports whether a manifest key belongs to the configured sites and
+// languages; empty lists include everything
+func (e *exporter) inScope(key string) bool {
+	site, rest, _ := strings.Cut(key, "|")
+	language, _, _ := strings.Cut(rest, "|")
+	return (len(e.config.Sites) == 0 || slices.Contains(e.config.Sites, site)) &&
+		(len(e.config.Languages) == 0 || slices.Contains(e.config.Languages, language))
+}
+
+// exportSEO writes the sitemap and robots.txt of a site
+func (e *exporter) exportSEO(ctx context.Context, site string) {
+	siteDir := filepath.Join(e.config.OutputDir, site)
+
+	var sitemapURLs []string
+	if e.config.Sitemap != nil {
+		entries, err := e.config.Sitemap.FetchSitemap(ctx, []string{site}, e.config.Languages)
+		if err == nil {
+			var xml string
+			if xml, err = e.config.Sitemap.GenerateSitemapXML(entries); err == nil {
+				err = writeFile(filepath.Join(siteDir, "sitemap.xml"), []byte(xml))
+			}
+		}
+		if err != nil {
+			e.fail(fmt.Errorf("%s sitemap: %w", site, err))
+		} else if baseURL := e.siteBaseURL(site); baseURL != "" {
+			sitemapURLs = append(sitemapURLs, baseURL+"/sitemap.xml")
+		}
+	}
+
+	if e.config.Robots != nil {
+		directive, err := e.config.Robots.FetchRobotsDirectives(ctx, site)
+		if err != nil {
+			debug.Robots("using default robots.txt for %s: %v", site, err)
+		}
+		robots := e.config.Robots.GenerateRobotsTxt(directive, sitemapURLs)
+		if err := writeFile(filepath.Join(siteDir, "robots.txt"), []byte(robots)); err != nil {
+			e.fail(fmt.Errorf("%s robots.txt: %w", site, err))
+		}
+	}
+}
+
+// siteBaseURL returns the public URL of a site's directory, or "" when unknown
+func (e *exporter) siteBaseURL(site string) string {
+	if baseURL := e.config.SiteBaseURLs[site]; baseURL != "" {
+		return strings.TrimSuffix(baseURL, "/")
+	}
+	if e.config.BaseURL == "" {
+		return ""
+	}
+	return strings.TrimSuffix(e.config.BaseURL, "/") + "/" + url.PathEscape(site)
+}
+
+// pageFile returns the file of a page relative to the output directory
+func (e *exporter) pageFile(path models.StaticPath) string {
+	parts := []string{path.Site}
+	if path.Locale != e.config.DefaultLanguage {
+		parts = append(parts, path.Locale)
+	}
+	for _, segment := range path.Path {
+		if segment == "" || segment == "." || segment == ".." {
+			continue
+		}
+		parts = append(parts, segment)
+	}
+	parts = append(parts, "index.html")
+	return filepath.Join(parts...)
+}
+
+// fail records an error
+func (e *exporter) fail(err error) {
+	debug.Common("export error: %v", err)
+	e.mu.Lock()
+	e.result.Errors = append(e.result.Errors, err)
+	e.mu.Unlock()
+}
+
+// failPage records a page error. The page of the previous export is kept,
+// so a failed render doesn't remove a published page.
+func (e *exporter) failPage(key string, err error) {
+	e.fail(err)
+	if previous, ok := e.previous[key]; ok {
+		e.mu.Lock()
+		e.manifest[key] = previous
+		e.mu.Unlock()
+	}
+}
+
+// manifestKey identifies a page in the manifest
+func manifestKey(path models.StaticPath) string {
+	return path.Site + "|" + path.Locale + "|/" + strings.Join(path.Path, "/")
+}
+
+// readManifest reads the manifest of a previous export; a missing manifest is empty
+func readManifest(path string) map[string]manifestEntry {
+	manifest := map[string]manifestEntry{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return manifest
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		debug.Common("ignoring unreadable export manifest %s: %v", path, err)
+		return map[string]manifestEntry{}
+	}
+	return manifest
+}
+
+// writeManifest writes the manifest
+func writeManifest(path string, manifest map[string]manifestEntry) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(path, append(data, '\n'))
+}
+
+// writeFile writes a file, creating its directory
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,293 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/guitarrich/content-sdk-go/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSource serves a fixed set of routes
+type fakeSource struct {
+	mu      sync.Mutex
+	paths   []models.StaticPath
+	fetched []string
+	fail    map[string]bool
+}
+
+func (s *fakeSource) GetStaticPathsContext(ctx context.Context, sites []string, languages []string) ([]models.StaticPath, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]models.StaticPath(nil), s.paths...), nil
+}
+
+func (s *fakeSource) GetPageContext(ctx context.Context, path string, options models.PageOptions) (*models.Page, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fetched = append(s.fetched, *options.Locale+path)
+	if s.fail[path] {
+		return nil, errors.New("layout unavailable")
+	}
+	return &models.Page{Path: path, Site: options.Site, Language: *options.Locale}, nil
+}
+
+// fakeRenderer renders the page path with an image reference
+type fakeRenderer struct {
+	active, peak atomic.Int32
+}
+
+func (r *fakeRenderer) RenderPage(ctx context.Context, page *models.Page) (templ.Component, error) {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		active := r.active.Add(1)
+		defer r.active.Add(-1)
+		for {
+			peak := r.peak.Load()
+			if active <= peak || r.peak.CompareAndSwap(peak, active) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		_, err := fmt.Fprintf(w, `<html><body>%s %s<img src="/-/media/hero.jpg?w=300&amp;h=200" srcset="/-/media/hero.jpg?w=600 600w, /-/media/../../secret 1w"><a href="/about">About</a></body></html>`,
+			page.Language, page.Path)
+		return err
+	}), nil
+}
+
+// fakeSitemap returns one entry per language
+type fakeSitemap struct{}
+
+func (fakeSitemap) FetchSitemap(ctx context.Context, sites []string, languages []string) ([]models.SitemapEntry, error) {
+	return []models.SitemapEntry{{Loc: "https://example.com/"}}, nil
+}
+
+func (fakeSitemap) GenerateSitemapXML(entries []models.SitemapEntry) (string, error) {
+	return "<urlset>" + entries[0].Loc + "</urlset>", nil
+}
+
+// fakeRobots returns default directives
+type fakeRobots struct{}
+
+func (fakeRobots) FetchRobotsDirectives(ctx context.Context, siteName string) (*models.RobotsDirective, error) {
+	return nil, errors.New("not configured")
+}
+
+func (fakeRobots) GenerateRobotsTxt(directive *models.RobotsDirective, sitemapURLs []string) string {
+	return "User-agent: *\nSitemap: " + strings.Join(sitemapURLs, ",") + "\n"
+}
+
+// newMediaServer serves media and counts requests
+func newMediaServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/-/media/hero.jpg" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("jpeg"))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestExport(t *testing.T) {
+	out := t.TempDir()
+	media, mediaRequests := newMediaServer(t)
+	renderer := &fakeRenderer{}
+	source := &fakeSource{paths: []models.StaticPath{
+		{Site: "main", Locale: "en", Path: nil},
+		{Site: "main", Locale: "en", Path: []string{"about"}},
+		{Site: "main", Locale: "fr", Path: []string{"about"}},
+		{Site: "main", Locale: "en", Path: []string{"news", "one"}},
+		{Site: "main", Locale: "en", Path: []string{"news", "two"}},
+	}}
+
+	result, err := Export(context.Background(), Config{
+		Source:          source,
+		Renderer:        renderer,
+		Sites:           []string{"main"},
+		Languages:       []string{"en", "fr"},
+		DefaultLanguage: "en",
+		OutputDir:       out,
+		Concurrency:     2,
+		Sitemap:         fakeSitemap{},
+		Robots:          fakeRobots{},
+		BaseURL:         "https://example.com/",
+		MediaServerURL:  media.URL,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, result.Rendered)
+	assert.Equal(t, 1, result.Media)
+	assert.Equal(t, int32(2), renderer.peak.Load())
+
+	assert.Contains(t, readFile(t, filepath.Join(out, "main", "index.html")), "en /")
+	assert.Contains(t, readFile(t, filepath.Join(out, "main", "about", "index.html")), "en /about")
+	assert.Contains(t, readFile(t, filepath.Join(out, "main", "fr", "about", "index.html")), "fr /about")
+	assert.Contains(t, readFile(t, filepath.Join(out, "main", "news", "two", "index.html")), "en /news/two")
+
+	assert.Equal(t, "jpeg", readFile(t, filepath.Join(out, "main", "-", "media", "hero.jpg")))
+	assert.Equal(t, int32(1), mediaRequests.Load())
+	assert.NoFileExists(t, filepath.Join(out, "secret"))
+
+	assert.Equal(t, "<urlset>https://example.com/</urlset>", readFile(t, filepath.Join(out, "main", "sitemap.xml")))
+	assert.Contains(t, readFile(t, filepath.Join(out, "main", "robots.txt")), "Sitemap: https://example.com/main/sitemap.xml")
+	assert.FileExists(t, filepath.Join(out, ManifestFile))
+}
+
+func TestExport_Incremental(t *testing.T) {
+	out := t.TempDir()
+	media, mediaRequests := newMediaServer(t)
+	modified := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	source := &fakeSource{paths: []models.StaticPath{
+		{Site: "main", Locale: "en", Path: []string{"about"}, LastModified: modified},
+		{Site: "main", Locale: "en", Path: []string{"news"}, LastModified: modified},
+		{Site: "main", Locale: "en", Path: []string{"old"}, LastModified: modified},
+		{Site: "main", Locale: "en", Path: []string{"undated"}},
+	}}
+	config := Config{
+		Source:          source,
+		Renderer:        &fakeRenderer{},
+		Sites:           []string{"main"},
+		Languages:       []string{"en"},
+		DefaultLanguage: "en",
+		OutputDir:       out,
+		Incremental:     true,
+		MediaServerURL:  media.URL,
+	}
+
+	result, err := Export(context.Background(), config)
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.Rendered)
+
+	// news changed, old was deleted, about is unchanged and undated pages always render
+	source.paths = []models.StaticPath{
+		{Site: "main", Locale: "en", Path: []string{"about"}, LastModified: modified},
+		{Site: "main", Locale: "en", Path: []string{"news"}, LastModified: modified.Add(time.Hour)},
+		{Site: "main", Locale: "en", Path: []string{"undated"}},
+	}
+	source.fetched = nil
+
+	result, err = Export(context.Background(), config)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Rendered)
+	assert.Equal(t, 1, result.Skipped)
+	assert.Equal(t, 1, result.Removed)
+	assert.ElementsMatch(t, []string{"en/news", "en/undated"}, source.fetched)
+	assert.NoFileExists(t, filepath.Join(out, "main", "old", "index.html"))
+	assert.FileExists(t, filepath.Join(out, "main", "about", "index.html"))
+
+	// Existing media isn't fetched again
+	assert.Equal(t, int32(1), mediaRequests.Load())
+}
+
+func TestExport_KeepsSitesOutsideExport(t *testing.T) {
+	out := t.TempDir()
+	modified := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	source := &fakeSource{paths: []models.StaticPath{
+		{Site: "a", Locale: "en", Path: []string{"about"}, LastModified: modified},
+		{Site: "a", Locale: "fr", Path: []string{"about"}, LastModified: modified},
+		{Site: "b", Locale: "en", Path: []string{"about"}, LastModified: modified},
+	}}
+	config := Config{
+		Source:          source,
+		Renderer:        &fakeRenderer{},
+		Sites:           []string{"a", "b"},
+		Languages:       []string{"en", "fr"},
+		DefaultLanguage: "en",
+		OutputDir:       out,
+		Incremental:     true,
+	}
+	_, err := Export(context.Background(), config)
+	require.NoError(t, err)
+
+	// Re-export only site a in English; its removed route is deleted, the rest is kept
+	source.paths = nil
+	config.Sites = []string{"a"}
+	config.Languages = []string{"en"}
+	result, err := Export(context.Background(), config)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Removed)
+	assert.NoFileExists(t, filepath.Join(out, "a", "about", "index.html"))
+	assert.FileExists(t, filepath.Join(out, "a", "fr", "about", "index.html"))
+	assert.FileExists(t, filepath.Join(out, "b", "about", "index.html"))
+
+	manifest := readManifest(filepath.Join(out, ManifestFile))
+	assert.Contains(t, manifest, "a|fr|/about")
+	assert.Contains(t, manifest, "b|en|/about")
+	assert.NotContains(t, manifest, "a|en|/about")
+}
+
+func TestExport_SitemapURLPerSite(t *testing.T) {
+	out := t.TempDir()
+	_, err := Export(context.Background(), Config{
+		Source:       &fakeSource{},
+		Renderer:     &fakeRenderer{},
+		Sites:        []string{"main", "brand-a"},
+		Languages:    []string{"en"},
+		OutputDir:    out,
+		Sitemap:      fakeSitemap{},
+		Robots:       fakeRobots{},
+		BaseURL:      "https://static.example.com",
+		SiteBaseURLs: map[string]string{"brand-a": "https://brand-a.example.com/"},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, readFile(t, filepath.Join(out, "main", "robots.txt")), "Sitemap: https://static.example.com/main/sitemap.xml\n")
+	assert.Contains(t, readFile(t, filepath.Join(out, "brand-a", "robots.txt")), "Sitemap: https://brand-a.example.com/sitemap.xml\n")
+}
+
+func TestExport_FailedPageKeepsPreviousExport(t *testing.T) {
+	out := t.TempDir()
+	modified := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	source := &fakeSource{paths: []models.StaticPath{
+		{Site: "main", Locale: "en", Path: []string{"about"}, LastModified: modified},
+	}}
+	config := Config{
+		Source:          source,
+		Renderer:        &fakeRenderer{},
+		Sites:           []string{"main"},
+		Languages:       []string{"en"},
+		DefaultLanguage: "en",
+		OutputDir:       out,
+		Incremental:     true,
+	}
+	_, err := Export(context.Background(), config)
+	require.NoError(t, err)
+
+	source.paths[0].LastModified = modified.Add(time.Hour)
+	source.fail = map[string]bool{"/about": true}
+	result, err := Export(context.Background(), config)
+
+	assert.ErrorContains(t, err, "main en /about: layout unavailable")
+	assert.Len(t, result.Errors, 1)
+	assert.Equal(t, 0, result.Removed)
+	assert.FileExists(t, filepath.Join(out, "main", "about", "index.html"))
+}
+
+func TestExport_RequiresSourceAndRenderer(t *testing.T) {
+	_, err := Export(context.Background(), Config{OutputDir: t.TempDir()})
+	assert.Error(t, err)
+}
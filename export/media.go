@@ -0,0 +1,138 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// mediaURLPattern matches root-relative URLs in HTML attributes and srcset lists
+var mediaURLPattern = regexp.MustCompile(`["'(,\s](/[^"'()\s<>,]+)`)
+
+// mediaFetcher downloads the media referenced by exported pages
+type mediaFetcher struct {
+	config Config
+
+	mu      sync.Mutex
+	fetched map[string]bool
+}
+
+// newMediaFetcher creates a media fetcher
+func newMediaFetcher(config Config) *mediaFetcher {
+	if len(config.MediaPaths) == 0 {
+		config.MediaPaths = []string{"/-/media/", "/-/jssmedia/"}
+	}
+	config.MediaServerURL = strings.TrimSuffix(config.MediaServerURL, "/")
+
+	return &mediaFetcher{
+		config:  config,
+		fetched: map[string]bool{},
+	}
+}
+
+// fetchReferenced writes the media referenced by a page to the site directory.
+// Query strings are dropped: a static host serves one file per path.
+func (m *mediaFetcher) fetchReferenced(ctx context.Context, site string, page []byte) (int, []error) {
+	written := 0
+	var errs []error
+	for _, mediaPath := range m.references(page) {
+		key := site + "|" + mediaPath
+		m.mu.Lock()
+		done := m.fetched[key]
+		m.fetched[key] = true
+		m.mu.Unlock()
+		if done {
+			continue
+		}
+
+		file := filepath.Join(m.config.OutputDir, site, filepath.FromSlash(mediaPath))
+		if m.config.Incremental {
+			if _, err := os.Stat(file); err == nil {
+				continue
+			}
+		}
+		if err := m.fetch(ctx, mediaPath, file); err != nil {
+			errs = append(errs, fmt.Errorf("%s media %s: %w", site, mediaPath, err))
+			continue
+		}
+		written++
+	}
+	return written, errs
+}
+
+// references returns the cleaned paths of the media URLs in a page
+func (m *mediaFetcher) references(page []byte) []string {
+	var paths []string
+	seen := map[string]bool{}
+	for _, match := range mediaURLPattern.FindAllSubmatch(page, -1) {
+		raw := html.UnescapeString(string(match[1]))
+		if !m.isMediaPath(raw) {
+			continue
+		}
+		if i := strings.IndexAny(raw, "?#"); i >= 0 {
+			raw = raw[:i]
+		}
+		if unescaped, err := url.PathUnescape(raw); err == nil {
+			raw = unescaped
+		}
+		cleaned := path.Clean(raw)
+		if !m.isMediaPath(cleaned+"/") || seen[cleaned] {
+			// Paths escaping the media prefixes are ignored
+			continue
+		}
+		seen[cleaned] = true
+		paths = append(paths, cleaned)
+	}
+	return paths
+}
+
+// isMediaPath reports whether a path starts with a media prefix
+func (m *mediaFetcher) isMediaPath(p string) bool {
+	lower := strings.ToLower(p)
+	for _, prefix := range m.config.MediaPaths {
+		if strings.HasPrefix(lower, strings.ToLower(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// fetch downloads a media path to a file
+func (m *mediaFetcher) fetch(ctx context.Context, mediaPath, file string) error {
+	mediaURL := m.config.MediaServerURL + (&url.URL{Path: mediaPath}).EscapedPath()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, mediaURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := m.config.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	out, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		out.Close()
+		os.Remove(file)
+		return err
+	}
+	return out.Close()
+}
@@ -6,13 +6,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"math"
 	"net/http"
+	"regexp"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/guitarrich/content-sdk-go/config"
 	"github.com/guitarrich/content-sdk-go/debug"
+	"github.com/guitarrich/content-sdk-go/metrics"
 	"github.com/guitarrich/content-sdk-go/models"
+	"github.com/guitarrich/content-sdk-go/telemetry"
 )
 
 // Client is the interface for GraphQL operations
@@ -22,6 +28,7 @@ type Client interface {
 
 // ClientImpl is the default implementation of the GraphQL client
 type ClientImpl struct {
+	mu         sync.RWMutex
 	endpoint   string
 	apiKey     string
 	httpClient *http.Client
@@ -75,6 +82,8 @@ func NewClient(endpoint, apiKey string, httpClient *http.Client, config *ClientC
 		httpClient.Timeout = config.Timeout
 	}
 
+	debug.RegisterSecret(apiKey)
+
 	return &ClientImpl{
 		endpoint:   endpoint,
 		apiKey:     apiKey,
@@ -83,6 +92,29 @@ func NewClient(endpoint, apiKey string, httpClient *http.Client, config *ClientC
 	}
 }
 
+// SetEndpoint atomically replaces the endpoint and API key used for subsequent requests
+func (c *ClientImpl) SetEndpoint(endpoint, apiKey string) {
+	debug.RegisterSecret(apiKey)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.endpoint = endpoint
+	c.apiKey = apiKey
+}
+
+// OnConfigChange implements config.Subscriber and applies the new GraphQL endpoint
+func (c *ClientImpl) OnConfigChange(old, new *config.Config) {
+	c.SetEndpoint(new.GetGraphQLEndpoint(), new.GetAPIKey())
+	debug.Common("GraphQL endpoint reloaded")
+}
+
+// target returns the current endpoint and API key
+func (c *ClientImpl) target() (string, string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.endpoint, c.apiKey
+}
+
 // Request executes a GraphQL query with retry logic
 func (c *ClientImpl) Request(
 	ctx context.Context,
@@ -90,6 +122,7 @@ func (c *ClientImpl) Request(
 	variables map[string]any,
 ) (map[string]any, error) {
 	var lastErr error
+	endpoint, apiKey := c.target()
 
 	debug.Common("Requesting GraphQL query: %s", query)
 	debug.Common("Variables: %+v", variables)
@@ -97,8 +130,8 @@ func (c *ClientImpl) Request(
 	debug.Common("Retries: %d", c.config.Retries)
 	debug.Common("RetryDelay: %v", c.config.RetryDelay)
 	debug.Common("Headers: %+v", c.config.Headers)
-	debug.Common("Endpoint: %s", c.endpoint)
-	debug.Common("API Key: %s", c.apiKey)
+	debug.Common("Endpoint: %s", endpoint)
+	debug.Common("API Key set: %t", apiKey != "")
 
 	// Add context timeout if not already set
 	if _, hasDeadline := ctx.Deadline(); !hasDeadline && c.config.Timeout > 0 {
@@ -107,6 +140,18 @@ func (c *ClientImpl) Request(
 		defer cancel()
 	}
 
+	operation := operationName(query)
+	ctx, span := telemetry.StartSpan(ctx, "graphql "+operation,
+		slog.String("graphql.operation.name", operation))
+	start := time.Now()
+	attempts := 0
+	defer func() {
+		span.SetAttributes(slog.Int("graphql.attempts", attempts))
+		telemetry.RecordEdgeRequest(ctx, operation, time.Since(start), attempts, lastErr)
+		metrics.RecordGraphQLRequest(operation, time.Since(start), attempts, lastErr)
+		telemetry.EndSpan(span, lastErr)
+	}()
+
 	// Retry loop with exponential backoff
 	for attempt := 0; attempt <= c.config.Retries; attempt++ {
 		if attempt > 0 {
@@ -117,11 +162,13 @@ func (c *ClientImpl) Request(
 			select {
 			case <-time.After(delay):
 			case <-ctx.Done():
-				return nil, ctx.Err()
+				lastErr = ctx.Err()
+				return nil, lastErr
 			}
 		}
 
-		result, err := c.doRequest(ctx, query, variables)
+		attempts++
+		result, err := c.doRequest(ctx, endpoint, apiKey, query, variables)
 		if err == nil {
 			return result, nil
 		}
@@ -137,12 +184,15 @@ func (c *ClientImpl) Request(
 		}
 	}
 
-	return nil, fmt.Errorf("GraphQL request failed after %d retries: %w", c.config.Retries, lastErr)
+	lastErr = fmt.Errorf("GraphQL request failed after %d retries: %w", c.config.Retries, lastErr)
+	return nil, lastErr
 }
 
 // doRequest performs a single GraphQL request
 func (c *ClientImpl) doRequest(
 	ctx context.Context,
+	endpoint string,
+	apiKey string,
 	query string,
 	variables map[string]any,
 ) (map[string]any, error) {
@@ -160,7 +210,7 @@ func (c *ClientImpl) doRequest(
 	debug.Http("Request body: %s", string(jsonData))
 
 	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
@@ -170,8 +220,8 @@ func (c *ClientImpl) doRequest(
 
 	// Only set sc_apikey header for local API (not Edge API)
 	// Edge API uses sitecoreContextId as a query parameter in the URL
-	if c.apiKey != "" && !isEdgeAPI(c.endpoint) {
-		req.Header.Set("sc_apikey", c.apiKey)
+	if apiKey != "" && !isEdgeAPI(endpoint) {
+		req.Header.Set("sc_apikey", apiKey)
 	}
 
 	// Apply custom headers
@@ -179,14 +229,18 @@ func (c *ClientImpl) doRequest(
 		req.Header.Set(key, value)
 	}
 
+	// Forward the W3C trace context to Edge
+	telemetry.InjectHeaders(ctx, req.Header)
+
 	// Execute request
-	debug.Http("GraphQL request to %s", c.endpoint)
+	debug.Http("GraphQL request to %s", endpoint)
 	debug.Http("Request headers: %+v", req.Header)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute GraphQL request: %w", err)
 	}
 	defer resp.Body.Close()
+	telemetry.SpanFromContext(ctx).SetAttributes(slog.Int("http.response.status_code", resp.StatusCode))
 
 	// Read response
 	body, err := io.ReadAll(resp.Body)
@@ -218,6 +272,20 @@ func (c *ClientImpl) doRequest(
 	return result.Data, nil
 }
 
+// operationPattern matches the operation type and name of a GraphQL document
+var operationPattern = regexp.MustCompile(`^\s*(query|mutation|subscription)\s+(\w+)`)
+
+// operationName returns the name of a GraphQL operation, or its type when it is anonymous
+func operationName(query string) string {
+	if match := operationPattern.FindStringSubmatch(query); match != nil {
+		return match[2]
+	}
+	if strings.HasPrefix(strings.TrimSpace(query), "mutation") {
+		return "mutation"
+	}
+	return "query"
+}
+
 // isEdgeAPI checks if the endpoint is using Edge API (contains sitecoreContextId query parameter)
 func isEdgeAPI(endpoint string) bool {
 	return strings.Contains(endpoint, "sitecoreContextId=")
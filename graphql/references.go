@@ -0,0 +1,104 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/guitarrich/content-sdk-go/debug"
+	"github.com/guitarrich/content-sdk-go/models"
+)
+
+// ReferenceLoader loads the fields of referenced items that were not expanded
+// in layout data, such as Multilist values returned as item IDs only
+type ReferenceLoader struct {
+	client Client
+}
+
+// NewReferenceLoader creates a new reference loader
+func NewReferenceLoader(client Client) *ReferenceLoader {
+	return &ReferenceLoader{
+		client: client,
+	}
+}
+
+// Load fetches the fields of every unexpanded item in the list in a single request.
+// Items are updated in place; items that don't exist in Edge are left unexpanded.
+func (l *ReferenceLoader) Load(ctx context.Context, list *models.ItemReferenceList, language string) error {
+	if list == nil {
+		return nil
+	}
+	return l.load(ctx, list.Unexpanded(), language)
+}
+
+// LoadItem fetches the fields of a single unexpanded item reference
+func (l *ReferenceLoader) LoadItem(ctx context.Context, reference *models.ItemReference, language string) error {
+	if reference == nil || reference.IsExpanded() || reference.ID == "" {
+		return nil
+	}
+	return l.load(ctx, []*models.ItemReference{reference}, language)
+}
+
+// load fetches the given items using one aliased item query per reference
+func (l *ReferenceLoader) load(ctx context.Context, references []*models.ItemReference, language string) error {
+	if len(references) == 0 {
+		return nil
+	}
+
+	debug.Common("loading %d unexpanded item references in %s", len(references), language)
+
+	query, variables := referenceQuery(references, language)
+	data, err := l.client.Request(ctx, query, variables)
+	if err != nil {
+		return fmt.Errorf("failed to load item references: %w", err)
+	}
+
+	for i, reference := range references {
+		item, ok := data[fmt.Sprintf("item%d", i)].(map[string]any)
+		if !ok {
+			debug.Common("item reference %s not found", reference.ID)
+			continue
+		}
+
+		loaded := models.ExtractItemReferenceFromMap(item)
+		reference.Name = loaded.Name
+		reference.DisplayName = loaded.DisplayName
+		reference.URL = loaded.URL
+		reference.Fields = loaded.Fields
+		if reference.Fields == nil {
+			reference.Fields = map[string]any{}
+		}
+	}
+
+	return nil
+}
+
+// referenceQuery builds an item query with one alias per reference
+func referenceQuery(references []*models.ItemReference, language string) (string, map[string]any) {
+	var params, items strings.Builder
+	variables := map[string]any{"language": language}
+
+	params.WriteString("$language: String!")
+	for i, reference := range references {
+		fmt.Fprintf(&params, ", $id%d: String!", i)
+		fmt.Fprintf(&items, `
+			item%d: item(path: $id%d, language: $language) {
+				id
+				name
+				displayName
+				url {
+					path
+				}
+				fields {
+					name
+					jsonValue
+				}
+			}`, i, i)
+		variables[fmt.Sprintf("id%d", i)] = reference.ID
+	}
+
+	return fmt.Sprintf(`
+		query ItemReferenceQuery(%s) {%s
+		}
+	`, params.String(), items.String()), variables
+}
@@ -0,0 +1,104 @@
+package graphql
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/guitarrich/content-sdk-go/models"
+)
+
+// mockReferenceClient returns items for the aliased item query
+type mockReferenceClient struct {
+	query     string
+	variables map[string]any
+	response  map[string]any
+}
+
+func (m *mockReferenceClient) Request(ctx context.Context, query string, variables map[string]any) (map[string]any, error) {
+	m.query = query
+	m.variables = variables
+	return m.response, nil
+}
+
+func TestReferenceLoader_Load(t *testing.T) {
+	fields := map[string]any{
+		"Links": map[string]any{
+			"value": "{11111111-1111-1111-1111-111111111111}|{22222222-2222-2222-2222-222222222222}",
+		},
+	}
+
+	list := models.GetItemReferenceList(fields, "Links")
+	if len(list.Items) != 2 || len(list.Unexpanded()) != 2 {
+		t.Fatalf("expected 2 unexpanded items, got %d/%d", len(list.Items), len(list.Unexpanded()))
+	}
+
+	client := &mockReferenceClient{
+		response: map[string]any{
+			"item0": map[string]any{
+				"id":   "11111111111111111111111111111111",
+				"name": "about",
+				"url":  map[string]any{"path": "/about"},
+				"fields": []any{
+					map[string]any{"name": "Title", "jsonValue": map[string]any{"value": "About us"}},
+				},
+			},
+			"item1": nil,
+		},
+	}
+
+	loader := NewReferenceLoader(client)
+	if err := loader.Load(context.Background(), list, "en"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(client.query, "item1: item(path: $id1, language: $language)") {
+		t.Errorf("expected aliased item query, got %s", client.query)
+	}
+	if client.variables["id0"] != "{11111111-1111-1111-1111-111111111111}" || client.variables["language"] != "en" {
+		t.Errorf("unexpected variables: %v", client.variables)
+	}
+
+	about := list.Items[0]
+	if !about.IsExpanded() {
+		t.Fatal("expected first item to be expanded")
+	}
+	if about.URL != "/about" || about.Name != "about" {
+		t.Errorf("expected url '/about' and name 'about', got '%s' and '%s'", about.URL, about.Name)
+	}
+	if title := about.GetTextField("Title").Value; title != "About us" {
+		t.Errorf("expected title 'About us', got '%s'", title)
+	}
+
+	// Missing items stay unexpanded
+	if list.Items[1].IsExpanded() {
+		t.Error("expected missing item to stay unexpanded")
+	}
+	if len(list.Unexpanded()) != 1 {
+		t.Errorf("expected 1 unexpanded item, got %d", len(list.Unexpanded()))
+	}
+}
+
+func TestReferenceLoader_SkipsExpandedItems(t *testing.T) {
+	fields := map[string]any{
+		"Category": map[string]any{
+			"id":     "{33333333-3333-3333-3333-333333333333}",
+			"url":    "/categories/news",
+			"name":   "news",
+			"fields": map[string]any{"Title": map[string]any{"value": "News"}},
+		},
+	}
+
+	reference := models.GetItemReference(fields, "Category")
+	if reference.GetTextField("Title").Value != "News" {
+		t.Errorf("expected title 'News', got '%s'", reference.GetTextField("Title").Value)
+	}
+
+	client := &mockReferenceClient{}
+	if err := NewReferenceLoader(client).LoadItem(context.Background(), reference, "en"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.query != "" {
+		t.Error("expected no request for an expanded item")
+	}
+}
@@ -33,7 +33,7 @@ func (h *CatchAllHandler) Handle(ctx middleware.Context) error {
 	debug.Layout("handling catch-all for path=%s, site=%s, locale=%s", path, site, locale)
 
 	// Fetch page data
-	page, err := h.client.GetPage(path, models.PageOptions{
+	page, err := h.client.GetPageContext(ctx.Request().Context(), path, models.PageOptions{
 		Site:   site,
 		Locale: &locale,
 	})
@@ -3,13 +3,13 @@ package handlers
 import (
 	"context"
 	"net/http"
-	"strings"
 
 	"github.com/a-h/templ"
 	"github.com/guitarrich/content-sdk-go/client"
 	"github.com/guitarrich/content-sdk-go/debug"
 	"github.com/guitarrich/content-sdk-go/middleware"
 	"github.com/guitarrich/content-sdk-go/models"
+	"github.com/guitarrich/content-sdk-go/telemetry"
 )
 
 // PageRenderer is an interface for rendering pages
@@ -37,66 +37,38 @@ func NewEditingRenderHandler(sitecoreClient *client.SitecoreClient, renderer Pag
 func (h *EditingRenderHandler) Handle(ctx middleware.Context) error {
 	debug.Editing("handling editing render request")
 
-	// Extract query parameters
-	itemID := ctx.Request().URL.Query().Get("sc_itemid")
-	language := ctx.Request().URL.Query().Get("sc_lang")
-	site := ctx.Request().URL.Query().Get("sc_site")
-	layoutKind := ctx.Request().URL.Query().Get("sc_layoutKind")
-	mode := ctx.Request().URL.Query().Get("mode")
-	route := ctx.Request().URL.Query().Get("route")
-	version := ctx.Request().URL.Query().Get("sc_version")
+	// Resolve the editing parameters (sc_itemid, sc_lang, sc_site, mode, ...)
+	editingContext := middleware.EditingContextFrom(ctx)
+	previewData := editingContext.PreviewData()
 
 	debug.Editing("query params: itemId=%s, lang=%s, site=%s, layoutKind=%s, mode=%s, route=%s",
-		itemID, language, site, layoutKind, mode, route)
+		previewData.ItemID, previewData.Language, previewData.Site, previewData.LayoutKind, previewData.Mode, previewData.Route)
 
 	// Validate required parameters
-	if itemID == "" {
+	if previewData.ItemID == "" {
 		debug.Editing("missing required parameter: sc_itemid")
 		return ctx.JSON(http.StatusBadRequest, map[string]string{
 			"error": "Missing required parameter: sc_itemid",
 		})
 	}
 
-	if language == "" {
+	if previewData.Language == "" {
 		debug.Editing("missing required parameter: sc_lang")
 		return ctx.JSON(http.StatusBadRequest, map[string]string{
 			"error": "Missing required parameter: sc_lang",
 		})
 	}
 
-	if site == "" {
+	if previewData.Site == "" {
 		debug.Editing("missing required parameter: sc_site")
 		return ctx.JSON(http.StatusBadRequest, map[string]string{
 			"error": "Missing required parameter: sc_site",
 		})
 	}
 
-	// Build preview data from query parameters
-	previewData := models.PreviewData{
-		ItemID:   itemID,
-		Language: language,
-		Site:     site,
-		Version:  version,
-		Route:    route,
-	}
-
-	// Set layout kind (default to final)
-	if layoutKind != "" && strings.ToLower(layoutKind) == "shared" {
-		previewData.LayoutKind = models.LayoutKindShared
-	} else {
-		previewData.LayoutKind = models.LayoutKindFinal
-	}
-
-	// Set preview mode
-	switch strings.ToLower(mode) {
-	case "edit":
+	// Default to edit mode
+	if previewData.Mode == "" {
 		previewData.Mode = models.PreviewModeEdit
-	case "preview":
-		previewData.Mode = models.PreviewModePreview
-	case "metadata":
-		previewData.Mode = models.PreviewModeMetadata
-	default:
-		previewData.Mode = models.PreviewModeEdit // Default to edit mode
 	}
 
 	debug.Editing("fetching preview: itemId=%s, language=%s, site=%s, mode=%s, layoutKind=%s",
@@ -132,7 +104,7 @@ func (h *EditingRenderHandler) Handle(ctx middleware.Context) error {
 
 	// Render component to response writer
 	// Note: The status code will default to 200 OK when we start writing
-	err = component.Render(ctx.Request().Context(), ctx.Response())
+	err = telemetry.InstrumentComponent("editing", component).Render(ctx.Request().Context(), ctx.Response())
 	if err != nil {
 		debug.Editing("error writing response: %v", err)
 		return err
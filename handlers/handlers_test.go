@@ -8,6 +8,8 @@ import (
 	"testing"
 
 	"github.com/guitarrich/content-sdk-go/middleware"
+	"github.com/guitarrich/content-sdk-go/models"
+	"github.com/guitarrich/content-sdk-go/site"
 )
 
 // MockContext for testing handlers
@@ -36,6 +38,7 @@ func NewMockContext(method, path string, body []byte) *MockContext {
 }
 
 func (m *MockContext) Request() *http.Request        { return m.request }
+func (m *MockContext) SetRequest(r *http.Request)    { m.request = r }
 func (m *MockContext) Response() http.ResponseWriter { return m.response }
 func (m *MockContext) Path() string                  { return m.path }
 func (m *MockContext) SetPath(path string)           { m.path = path }
@@ -192,3 +195,37 @@ func TestCatchAllHandler_GetLocaleFromContext_Default(t *testing.T) {
 		t.Errorf("expected default 'en', got '%s'", locale)
 	}
 }
+
+// MockSiteTable is a mock implementation for testing
+type MockSiteTable struct {
+	status site.SiteTableStatus
+}
+
+func (m *MockSiteTable) Status() site.SiteTableStatus {
+	return m.status
+}
+
+func TestSiteTableHandler(t *testing.T) {
+	handler := NewSiteTableHandler(&MockSiteTable{
+		status: site.SiteTableStatus{
+			Sites: []models.SiteInfo{
+				{Name: "brand-a", HostName: "a.example.com"},
+			},
+			DefaultSite: models.SiteInfo{Name: "brand-a"},
+		},
+	})
+	ctx := NewMockContext("GET", "/debug/sites", nil)
+
+	if err := handler.Handle(ctx); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	var response site.SiteTableStatus
+	if err := json.Unmarshal(ctx.response.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if len(response.Sites) != 1 || response.Sites[0].Name != "brand-a" {
+		t.Errorf("expected site table with brand-a, got %+v", response.Sites)
+	}
+}
@@ -0,0 +1,595 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/guitarrich/content-sdk-go/debug"
+	"github.com/guitarrich/content-sdk-go/media"
+	"github.com/guitarrich/content-sdk-go/metrics"
+	"github.com/guitarrich/content-sdk-go/middleware"
+	"github.com/guitarrich/content-sdk-go/telemetry"
+)
+
+// maxSourcePixels is the largest image decoded for resizing or conversion
+const maxSourcePixels = 50_000_000
+
+// MediaProxyHandler serves Sitecore media from the application's origin
+type MediaProxyHandler struct {
+	mediaHost    string
+	mediaPaths   []string
+	httpClient   *http.Client
+	cache        *diskCache
+	cacheControl string
+	resize       bool
+	dimensions   []int
+	sharedSecret string
+	encoders     map[string]ImageEncoder
+	negotiate    []string
+	maxBodySize  int64
+	maxDimension int
+}
+
+// MediaProxyHandlerConfig contains configuration for the media proxy handler
+type MediaProxyHandlerConfig struct {
+	// MediaHost is the Sitecore or Edge host that media requests are forwarded to
+	MediaHost string
+
+	// MediaPaths are the path prefixes served by the proxy (default: /-/media/, /-/jssmedia/)
+	MediaPaths []string
+
+	// HTTPClient is used for upstream requests (default: 30s timeout)
+	HTTPClient *http.Client
+
+	// CacheDir is the disk cache directory (optional). Without it nothing is cached.
+	CacheDir string
+
+	// MaxCacheSize is the disk cache size limit in bytes (default: 512 MB).
+	// The least recently used entries are evicted first.
+	MaxCacheSize int64
+
+	// CacheControl is the Cache-Control header of proxied responses (default: public, max-age=86400)
+	CacheControl string
+
+	// Resize resizes images locally from the w, h, mw, mh, q, as, iar and sc parameters
+	// instead of forwarding them. Requests must be signed with SharedSecret or only
+	// use dimensions from AllowedDimensions; other resize requests get a 400.
+	Resize bool
+
+	// AllowedDimensions are the w, h, mw and mh values accepted without a hash,
+	// e.g. the widths passed to media.GetSrcSet. Unsigned requests can't set q or sc.
+	AllowedDimensions []int
+
+	// SharedSecret accepts any resize parameters signed with the hash parameter,
+	// as media.ProtectionHash computes it (optional). It must match the
+	// media.MediaAPIConfig SharedSecret that generated the URLs.
+	SharedSecret string
+
+	// Encoders converts images to the first of these MIME types the client accepts,
+	// e.g. "image/avif" and "image/webp". The standard library has no WebP or AVIF
+	// encoder, so without encoders there is no format negotiation.
+	Encoders map[string]ImageEncoder
+
+	// FormatPreference is the order formats are negotiated in (default: image/avif, image/webp)
+	FormatPreference []string
+
+	// MaxBodySize is the largest upstream response accepted (default: 50 MB)
+	MaxBodySize int64
+
+	// MaxDimension is the largest width or height produced by resizing (default: 4096)
+	MaxDimension int
+}
+
+// NewMediaProxyHandler creates a new media proxy handler
+func NewMediaProxyHandler(config MediaProxyHandlerConfig) *MediaProxyHandler {
+	if len(config.MediaPaths) == 0 {
+		config.MediaPaths = []string{"/-/media/", "/-/jssmedia/"}
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	if config.MaxCacheSize == 0 {
+		config.MaxCacheSize = 512 << 20
+	}
+	if config.CacheControl == "" {
+		config.CacheControl = "public, max-age=86400"
+	}
+	if len(config.FormatPreference) == 0 {
+		config.FormatPreference = []string{"image/avif", "image/webp"}
+	}
+	if config.MaxBodySize == 0 {
+		config.MaxBodySize = 50 << 20
+	}
+	if config.MaxDimension == 0 {
+		config.MaxDimension = 4096
+	}
+
+	encoders := make(map[string]ImageEncoder, len(defaultEncoders)+len(config.Encoders))
+	for mimeType, encoder := range defaultEncoders {
+		encoders[mimeType] = encoder
+	}
+	var negotiate []string
+	for _, mimeType := range config.FormatPreference {
+		if encoder, ok := config.Encoders[mimeType]; ok {
+			encoders[mimeType] = encoder
+			negotiate = append(negotiate, mimeType)
+		}
+	}
+
+	debug.RegisterSecret(config.SharedSecret)
+
+	var cache *diskCache
+	if config.CacheDir != "" {
+		var err error
+		if cache, err = newDiskCache(config.CacheDir, config.MaxCacheSize); err != nil {
+			debug.Proxy("media cache disabled: %v", err)
+		}
+	}
+
+	return &MediaProxyHandler{
+		mediaHost:    strings.TrimSuffix(config.MediaHost, "/"),
+		mediaPaths:   config.MediaPaths,
+		httpClient:   config.HTTPClient,
+		cache:        cache,
+		cacheControl: config.CacheControl,
+		resize:       config.Resize,
+		dimensions:   config.AllowedDimensions,
+		sharedSecret: config.SharedSecret,
+		encoders:     encoders,
+		negotiate:    negotiate,
+		maxBodySize:  config.MaxBodySize,
+		maxDimension: config.MaxDimension,
+	}
+}
+
+// Handle processes media requests
+func (h *MediaProxyHandler) Handle(ctx middleware.Context) error {
+	request := ctx.Request()
+	path := request.URL.Path
+
+	if request.Method != http.MethodGet && request.Method != http.MethodHead {
+		return ctx.String(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+	if !h.isMediaPath(path) || strings.Contains(path, "..") {
+		return ctx.String(http.StatusNotFound, "Not found")
+	}
+
+	// Resize parameters are handled locally; everything else is forwarded
+	query := request.URL.Query()
+	var params *media.ImageParams
+	if h.resize {
+		params = parseImageParams(query)
+		if params != nil && !h.allowResize(request.URL, params) {
+			debug.Proxy("rejected unsigned resize request %s", request.URL.RequestURI())
+			return ctx.String(http.StatusBadRequest, "Invalid resize parameters")
+		}
+		for _, key := range resizeParameters {
+			query.Del(key)
+		}
+	}
+	upstreamURL := h.mediaHost + path
+	if encoded := query.Encode(); encoded != "" {
+		upstreamURL += "?" + encoded
+	}
+
+	format := h.negotiateFormat(ctx.Header("Accept"))
+	key := cacheKey(upstreamURL, params, format)
+
+	entry, body, ok := h.cache.get(key)
+	telemetry.RecordCacheLookup(ctx.Request().Context(), "media", ok)
+	metrics.RecordCacheLookup("media", ok)
+	if ok {
+		debug.Proxy("media cache hit: %s", upstreamURL)
+	} else {
+		var status int
+		var err error
+		entry, body, status, err = h.fetch(ctx, upstreamURL, params, format)
+		if err != nil {
+			debug.Proxy("media request failed for %s: %v", upstreamURL, err)
+			return ctx.String(http.StatusBadGateway, "Error fetching media")
+		}
+		if status != http.StatusOK {
+			debug.Proxy("media host returned %d for %s", status, upstreamURL)
+			return ctx.String(status, http.StatusText(status))
+		}
+		if err := h.cache.put(key, entry, body); err != nil {
+			debug.Proxy("failed to cache %s: %v", upstreamURL, err)
+		}
+	}
+
+	ctx.SetHeader("ETag", entry.ETag)
+	ctx.SetHeader("Cache-Control", h.cacheControl)
+	if len(h.negotiate) > 0 {
+		ctx.SetHeader("Vary", "Accept")
+	}
+	if etagMatches(ctx.Header("If-None-Match"), entry.ETag) {
+		return ctx.NoContent(http.StatusNotModified)
+	}
+
+	ctx.SetHeader("Content-Type", entry.ContentType)
+	ctx.SetHeader("Content-Length", fmt.Sprintf("%d", len(body)))
+	ctx.Response().WriteHeader(http.StatusOK)
+	if request.Method == http.MethodHead {
+		return nil
+	}
+	_, err := ctx.Response().Write(body)
+	return err
+}
+
+// fetch downloads a media item and transforms it when needed
+func (h *MediaProxyHandler) fetch(ctx middleware.Context, upstreamURL string, params *media.ImageParams, format string) (cacheEntry, []byte, int, error) {
+	debug.Proxy("fetching media %s", upstreamURL)
+
+	request, err := http.NewRequestWithContext(ctx.Request().Context(), http.MethodGet, upstreamURL, nil)
+	if err != nil {
+		return cacheEntry{}, nil, 0, err
+	}
+	response, err := h.httpClient.Do(request)
+	if err != nil {
+		return cacheEntry{}, nil, 0, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return cacheEntry{}, nil, response.StatusCode, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, h.maxBodySize+1))
+	if err != nil {
+		return cacheEntry{}, nil, 0, err
+	}
+	if int64(len(body)) > h.maxBodySize {
+		return cacheEntry{}, nil, 0, fmt.Errorf("media exceeds %d bytes", h.maxBodySize)
+	}
+
+	contentType := response.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+
+	if isTransformable(contentType) && (params != nil || format != "") {
+		if transformed, transformedType, err := h.transform(body, params, format); err != nil {
+			debug.Proxy("serving original %s: %v", upstreamURL, err)
+		} else {
+			body, contentType = transformed, transformedType
+		}
+	}
+
+	return cacheEntry{ContentType: contentType, ETag: computeETag(body)}, body, http.StatusOK, nil
+}
+
+// transform resizes and re-encodes an image. GIFs are re-encoded as PNG.
+func (h *MediaProxyHandler) transform(body []byte, params *media.ImageParams, format string) ([]byte, string, error) {
+	// Refuse images that would use too much memory when decoded
+	imageConfig, _, err := image.DecodeConfig(bytes.NewReader(body))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+	}
+	if imageConfig.Width*imageConfig.Height > maxSourcePixels {
+		return nil, "", fmt.Errorf("image is too large to transform (%dx%d)", imageConfig.Width, imageConfig.Height)
+	}
+
+	img, sourceFormat, err := image.Decode(bytes.NewReader(body))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	quality := 85
+	if params != nil {
+		if params.Quality != nil && *params.Quality <= 100 {
+			quality = *params.Quality
+		}
+		width, height, source := targetSize(img.Bounds(), params, h.maxDimension)
+		img = resizeImage(img, source, width, height)
+	}
+
+	if format == "" {
+		format = "image/" + sourceFormat
+		if sourceFormat == "gif" {
+			format = "image/png"
+		}
+	}
+	encoder, ok := h.encoders[format]
+	if !ok {
+		return nil, "", fmt.Errorf("no encoder for %s", format)
+	}
+
+	var buf bytes.Buffer
+	if err := encoder.Encode(&buf, img, quality); err != nil {
+		return nil, "", fmt.Errorf("failed to encode %s: %w", format, err)
+	}
+	return buf.Bytes(), format, nil
+}
+
+// allowResize reports whether a resize request is signed with the shared secret
+// or only uses allowed dimensions, so clients can't create unbounded variants
+func (h *MediaProxyHandler) allowResize(requestURL *url.URL, params *media.ImageParams) bool {
+	if h.sharedSecret != "" && h.validHash(requestURL) {
+		return true
+	}
+
+	if params.Quality != nil || params.Scale != nil {
+		return false
+	}
+	for _, dimension := range []*int{params.Width, params.Height, params.MaxWidth, params.MaxHeight} {
+		if dimension != nil && !slices.Contains(h.dimensions, *dimension) {
+			return false
+		}
+	}
+	return true
+}
+
+// validHash reports whether the hash parameter of a request matches its other parameters
+func (h *MediaProxyHandler) validHash(requestURL *url.URL) bool {
+	var hash string
+	var unsigned []string
+	for pair := range strings.SplitSeq(requestURL.RawQuery, "&") {
+		if value, ok := strings.CutPrefix(pair, "hash="); ok {
+			hash = value
+		} else if pair != "" {
+			unsigned = append(unsigned, pair)
+		}
+	}
+	if hash == "" {
+		return false
+	}
+
+	expected := media.ProtectionHash(requestURL.EscapedPath()+"?"+strings.Join(unsigned, "&"), h.sharedSecret)
+	return subtle.ConstantTimeCompare([]byte(strings.ToUpper(hash)), []byte(expected)) == 1
+}
+
+// negotiateFormat returns the preferred configured format the client accepts, or ""
+func (h *MediaProxyHandler) negotiateFormat(accept string) string {
+	for _, mimeType := range h.negotiate {
+		for part := range strings.SplitSeq(accept, ",") {
+			value, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+			if strings.EqualFold(strings.TrimSpace(value), mimeType) && !strings.Contains(strings.ReplaceAll(params, " ", ""), "q=0") {
+				return mimeType
+			}
+		}
+	}
+	return ""
+}
+
+// isMediaPath reports whether a path is served by the proxy
+func (h *MediaProxyHandler) isMediaPath(path string) bool {
+	lower := strings.ToLower(path)
+	return slices.ContainsFunc(h.mediaPaths, func(prefix string) bool {
+		return strings.HasPrefix(lower, strings.ToLower(prefix))
+	})
+}
+
+// isTransformable reports whether images of a content type can be decoded locally
+func isTransformable(contentType string) bool {
+	mimeType, _, _ := strings.Cut(contentType, ";")
+	switch strings.TrimSpace(strings.ToLower(mimeType)) {
+	case "image/jpeg", "image/png", "image/gif":
+		return true
+	}
+	return false
+}
+
+// cacheKey identifies a proxied response
+func cacheKey(upstreamURL string, params *media.ImageParams, format string) string {
+	value := upstreamURL + "|" + format
+	if params != nil {
+		encoded, _ := json.Marshal(params)
+		value += "|" + string(encoded)
+	}
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// computeETag returns a strong ETag for a response body
+func computeETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header matches an ETag
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for candidate := range strings.SplitSeq(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheEntry is the metadata stored next to a cached body
+type cacheEntry struct {
+	ContentType string `json:"contentType"`
+	ETag        string `json:"etag"`
+}
+
+// diskCache is a size-bounded LRU cache of media responses on disk.
+// A nil cache stores nothing.
+type diskCache struct {
+	mu      sync.Mutex
+	dir     string
+	maxSize int64
+	size    int64
+}
+
+// newDiskCache creates the cache directory and measures its current size
+func newDiskCache(dir string, maxSize int64) (*diskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	cache := &diskCache{dir: dir, maxSize: maxSize}
+	for _, file := range cache.files() {
+		cache.size += file.size
+	}
+	cache.evict()
+	return cache, nil
+}
+
+// get returns a cached entry and marks it as recently used
+func (c *diskCache) get(key string) (cacheEntry, []byte, bool) {
+	if c == nil {
+		return cacheEntry{}, nil, false
+	}
+
+	var entry cacheEntry
+	meta, err := os.ReadFile(c.path(key) + ".json")
+	if err != nil || json.Unmarshal(meta, &entry) != nil {
+		return cacheEntry{}, nil, false
+	}
+	body, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return cacheEntry{}, nil, false
+	}
+
+	now := time.Now()
+	os.Chtimes(c.path(key), now, now)
+	return entry, body, true
+}
+
+// put stores an entry and evicts the least recently used entries over the size limit
+func (c *diskCache) put(key string, entry cacheEntry, body []byte) error {
+	if c == nil || int64(len(body)) > c.maxSize {
+		return nil
+	}
+
+	meta, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := c.write(key, meta, body); err != nil {
+		return err
+	}
+
+	c.evict()
+	return nil
+}
+
+// write replaces the files of an entry, accounting for the size of a rewritten entry
+func (c *diskCache) write(key string, meta, body []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var previous int64
+	for _, path := range []string{c.path(key), c.path(key) + ".json"} {
+		if info, err := os.Stat(path); err == nil {
+			previous += info.Size()
+		}
+	}
+
+	// Write the metadata first so a body is never served without it
+	if err := writeFileAtomic(c.path(key)+".json", meta); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(c.path(key), body); err != nil {
+		// Drop the entry rather than keep the new metadata with the old body
+		os.Remove(c.path(key))
+		os.Remove(c.path(key) + ".json")
+		c.size -= previous
+		return err
+	}
+	c.size += int64(len(body)+len(meta)) - previous
+	return nil
+}
+
+// evict removes the least recently used entries until the cache fits its size limit
+func (c *diskCache) evict() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.size <= c.maxSize {
+		return
+	}
+
+	files := c.files()
+	c.size = 0
+	for _, file := range files {
+		c.size += file.size
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+
+	for _, file := range files {
+		if c.size <= c.maxSize {
+			break
+		}
+		if strings.HasSuffix(file.path, ".json") {
+			continue
+		}
+		removed := file.size
+		if info, err := os.Stat(file.path + ".json"); err == nil {
+			removed += info.Size()
+		}
+		os.Remove(file.path)
+		os.Remove(file.path + ".json")
+		c.size -= removed
+		debug.Proxy("evicted media cache entry %s", filepath.Base(file.path))
+	}
+}
+
+// cacheFile describes a file in the cache directory
+type cacheFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// files lists the files in the cache directory
+func (c *diskCache) files() []cacheFile {
+	var files []cacheFile
+	filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || strings.HasSuffix(path, ".tmp") {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			files = append(files, cacheFile{path: path, size: info.Size(), modTime: info.ModTime()})
+		}
+		return nil
+	})
+	return files
+}
+
+// path returns the body file path for a key, sharded by its first two characters
+func (c *diskCache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key)
+}
+
+// writeFileAtomic writes a file through a temporary file and rename
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
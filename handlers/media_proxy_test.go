@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/guitarrich/content-sdk-go/media"
+)
+
+// newMediaServer serves a 400x200 PNG and counts requests
+func newMediaServer(t *testing.T, requests *atomic.Int32, lastQuery *string) *httptest.Server {
+	img := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	for y := range 200 {
+		for x := range 400 {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		*lastQuery = r.URL.RawQuery
+		switch r.URL.Path {
+		case "/-/media/hero.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(buf.Bytes())
+		case "/-/media/doc.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Write([]byte("%PDF-1.4"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestMediaProxyHandler_ResizeAndCache(t *testing.T) {
+	var requests atomic.Int32
+	var lastQuery string
+	server := newMediaServer(t, &requests, &lastQuery)
+	defer server.Close()
+
+	handler := NewMediaProxyHandler(MediaProxyHandlerConfig{
+		MediaHost:         server.URL,
+		CacheDir:          t.TempDir(),
+		Resize:            true,
+		AllowedDimensions: []int{100},
+	})
+
+	ctx := NewMockContext("GET", "/-/media/hero.png?w=100&la=en&hash=ABC", nil)
+	if err := handler.Handle(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ctx.response.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", ctx.response.Code)
+	}
+	if lastQuery != "la=en" {
+		t.Errorf("expected resize parameters not to be forwarded, got %q", lastQuery)
+	}
+
+	img, err := png.Decode(ctx.response.Body)
+	if err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if bounds := img.Bounds(); bounds.Dx() != 100 || bounds.Dy() != 50 {
+		t.Errorf("expected 100x50 image, got %dx%d", bounds.Dx(), bounds.Dy())
+	}
+
+	etag := ctx.response.Header().Get("ETag")
+	if etag == "" {
+		t.Error("expected ETag header")
+	}
+	if cacheControl := ctx.response.Header().Get("Cache-Control"); cacheControl != "public, max-age=86400" {
+		t.Errorf("unexpected Cache-Control: %s", cacheControl)
+	}
+
+	// Second request is served from the disk cache and revalidated
+	ctx = NewMockContext("GET", "/-/media/hero.png?w=100&la=en", nil)
+	ctx.request.Header.Set("If-None-Match", etag)
+	handler.Handle(ctx)
+
+	if ctx.response.Code != http.StatusNotModified {
+		t.Errorf("expected status 304, got %d", ctx.response.Code)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected 1 upstream request, got %d", requests.Load())
+	}
+}
+
+func TestMediaProxyHandler_Crop(t *testing.T) {
+	var requests atomic.Int32
+	var lastQuery string
+	server := newMediaServer(t, &requests, &lastQuery)
+	defer server.Close()
+
+	handler := NewMediaProxyHandler(MediaProxyHandlerConfig{
+		MediaHost:         server.URL,
+		Resize:            true,
+		AllowedDimensions: []int{50},
+	})
+
+	ctx := NewMockContext("GET", "/-/media/hero.png?w=50&h=50", nil)
+	handler.Handle(ctx)
+
+	img, err := png.Decode(ctx.response.Body)
+	if err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if bounds := img.Bounds(); bounds.Dx() != 50 || bounds.Dy() != 50 {
+		t.Errorf("expected 50x50 image, got %dx%d", bounds.Dx(), bounds.Dy())
+	}
+}
+
+func TestMediaProxyHandler_RejectsUnsignedResize(t *testing.T) {
+	var requests atomic.Int32
+	var lastQuery string
+	server := newMediaServer(t, &requests, &lastQuery)
+	defer server.Close()
+
+	handler := NewMediaProxyHandler(MediaProxyHandlerConfig{
+		MediaHost:         server.URL,
+		Resize:            true,
+		AllowedDimensions: []int{100, 200},
+		SharedSecret:      "secret",
+	})
+	signed := media.NewMediaAPIWithConfig(media.MediaAPIConfig{SharedSecret: "secret"})
+	width, quality := 123, 40
+	signedURL := signed.GetMediaURL("/-/media/hero.png", &media.ImageParams{Width: &width, Quality: &quality})
+
+	tests := []struct {
+		path     string
+		expected int
+	}{
+		{"/-/media/hero.png?w=200&mh=100", http.StatusOK},
+		{"/-/media/hero.png?w=123", http.StatusBadRequest},
+		{"/-/media/hero.png?w=100&q=40", http.StatusBadRequest},
+		{"/-/media/hero.png?w=100&scale=0.5", http.StatusBadRequest},
+		{"/-/media/hero.png?w=123&hash=0123456789ABCDEF", http.StatusBadRequest},
+		{signedURL, http.StatusOK},
+	}
+	for _, tt := range tests {
+		ctx := NewMockContext("GET", tt.path, nil)
+		handler.Handle(ctx)
+		if ctx.response.Code != tt.expected {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.expected, ctx.response.Code)
+		}
+	}
+	if requests.Load() != 2 {
+		t.Errorf("expected only accepted requests upstream, got %d", requests.Load())
+	}
+
+	// Without an allow-list or secret, resizing is refused
+	handler = NewMediaProxyHandler(MediaProxyHandlerConfig{MediaHost: server.URL, Resize: true})
+	ctx := NewMockContext("GET", "/-/media/hero.png?w=100", nil)
+	handler.Handle(ctx)
+	if ctx.response.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", ctx.response.Code)
+	}
+}
+
+func TestMediaProxyHandler_NegotiateFormat(t *testing.T) {
+	var requests atomic.Int32
+	var lastQuery string
+	server := newMediaServer(t, &requests, &lastQuery)
+	defer server.Close()
+
+	webp := ImageEncoderFunc(func(w io.Writer, img image.Image, quality int) error {
+		_, err := w.Write([]byte("webp"))
+		return err
+	})
+	handler := NewMediaProxyHandler(MediaProxyHandlerConfig{
+		MediaHost: server.URL,
+		Encoders:  map[string]ImageEncoder{"image/webp": webp},
+	})
+
+	ctx := NewMockContext("GET", "/-/media/hero.png", nil)
+	ctx.request.Header.Set("Accept", "image/avif;q=0, image/webp, image/*")
+	handler.Handle(ctx)
+
+	if contentType := ctx.response.Header().Get("Content-Type"); contentType != "image/webp" {
+		t.Errorf("expected image/webp, got %s", contentType)
+	}
+	if ctx.response.Body.String() != "webp" {
+		t.Errorf("expected encoded body, got %q", ctx.response.Body.String())
+	}
+	if vary := ctx.response.Header().Get("Vary"); vary != "Accept" {
+		t.Errorf("expected Vary: Accept, got %s", vary)
+	}
+
+	// Non-images pass through unchanged
+	ctx = NewMockContext("GET", "/-/media/doc.pdf", nil)
+	ctx.request.Header.Set("Accept", "image/webp")
+	handler.Handle(ctx)
+
+	if contentType := ctx.response.Header().Get("Content-Type"); contentType != "application/pdf" {
+		t.Errorf("expected application/pdf, got %s", contentType)
+	}
+}
+
+func TestMediaProxyHandler_NotFound(t *testing.T) {
+	var requests atomic.Int32
+	var lastQuery string
+	server := newMediaServer(t, &requests, &lastQuery)
+	defer server.Close()
+
+	handler := NewMediaProxyHandler(MediaProxyHandlerConfig{MediaHost: server.URL})
+
+	for _, path := range []string{"/-/media/missing.png", "/-/media/../secret", "/api/layout"} {
+		ctx := NewMockContext("GET", path, nil)
+		handler.Handle(ctx)
+		if ctx.response.Code != http.StatusNotFound {
+			t.Errorf("%s: expected status 404, got %d", path, ctx.response.Code)
+		}
+	}
+}
+
+func TestDiskCache_Evict(t *testing.T) {
+	cache, err := newDiskCache(t.TempDir(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := cacheEntry{ContentType: "text/plain", ETag: `"1"`}
+	keys := []string{strings.Repeat("a", 64), strings.Repeat("b", 64), strings.Repeat("c", 64)}
+	for i, key := range keys {
+		if err := cache.put(key, entry, bytes.Repeat([]byte("x"), 30)); err != nil {
+			t.Fatal(err)
+		}
+		// Make the access order explicit regardless of file system time resolution
+		modTime := time.Now().Add(time.Duration(i-len(keys)) * time.Hour)
+		os.Chtimes(cache.path(key), modTime, modTime)
+	}
+
+	if cache.size > 100 {
+		t.Errorf("expected cache size within limit, got %d", cache.size)
+	}
+	if _, _, ok := cache.get(keys[2]); !ok {
+		t.Error("expected most recent entry to be cached")
+	}
+	if _, _, ok := cache.get(keys[0]); ok {
+		t.Error("expected oldest entry to be evicted")
+	}
+}
+
+func TestDiskCache_Rewrite(t *testing.T) {
+	cache, err := newDiskCache(t.TempDir(), 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := cacheEntry{ContentType: "text/plain", ETag: `"1"`}
+	key := strings.Repeat("a", 64)
+	for range 5 {
+		if err := cache.put(key, entry, bytes.Repeat([]byte("x"), 100)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Rewriting a key replaces its size instead of adding to it
+	var size int64
+	for _, file := range cache.files() {
+		size += file.size
+	}
+	if cache.size != size {
+		t.Errorf("expected cache size %d, got %d", size, cache.size)
+	}
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+	"net/url"
+	"strconv"
+
+	"github.com/guitarrich/content-sdk-go/media"
+)
+
+// ImageEncoder encodes an image in a format such as WebP or AVIF
+type ImageEncoder interface {
+	Encode(w io.Writer, img image.Image, quality int) error
+}
+
+// ImageEncoderFunc is a function type that implements ImageEncoder
+type ImageEncoderFunc func(w io.Writer, img image.Image, quality int) error
+
+// Encode implements the ImageEncoder interface
+func (f ImageEncoderFunc) Encode(w io.Writer, img image.Image, quality int) error {
+	return f(w, img, quality)
+}
+
+// defaultEncoders are the encoders available from the standard library
+var defaultEncoders = map[string]ImageEncoder{
+	"image/jpeg": ImageEncoderFunc(func(w io.Writer, img image.Image, quality int) error {
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+	}),
+	"image/png": ImageEncoderFunc(func(w io.Writer, img image.Image, quality int) error {
+		return png.Encode(w, img)
+	}),
+}
+
+// resizeParameters are the query parameters handled by local resizing
+var resizeParameters = []string{"w", "h", "mw", "mh", "q", "as", "iar", "sc", "scale", "hash"}
+
+// parseImageParams reads Sitecore media query parameters into ImageParams.
+// It returns nil when the query has no resize parameters.
+func parseImageParams(query url.Values) *media.ImageParams {
+	params := &media.ImageParams{}
+	found := false
+
+	intParam := func(key string) *int {
+		if value, err := strconv.Atoi(query.Get(key)); err == nil && value > 0 {
+			found = true
+			return &value
+		}
+		return nil
+	}
+	boolParam := func(key string) *bool {
+		if value := query.Get(key); value == "1" || value == "true" {
+			found = true
+			enabled := true
+			return &enabled
+		}
+		return nil
+	}
+
+	params.Width = intParam("w")
+	params.Height = intParam("h")
+	params.MaxWidth = intParam("mw")
+	params.MaxHeight = intParam("mh")
+	params.Quality = intParam("q")
+	params.AllowStretch = boolParam("as")
+	params.IgnoreAspectRatio = boolParam("iar")
+	for _, key := range []string{"sc", "scale"} {
+		if value, err := strconv.ParseFloat(query.Get(key), 64); err == nil && value > 0 {
+			found = true
+			params.Scale = &value
+		}
+	}
+
+	if !found {
+		return nil
+	}
+	return params
+}
+
+// targetSize computes the output size and the source rectangle to scale from.
+// Width and Height together crop to their aspect ratio unless IgnoreAspectRatio is set;
+// MaxWidth and MaxHeight only scale down. Images are not enlarged unless AllowStretch is set.
+func targetSize(bounds image.Rectangle, params *media.ImageParams, maxDimension int) (int, int, image.Rectangle) {
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	source := bounds
+	w, h := float64(srcW), float64(srcH)
+
+	if params.Scale != nil {
+		w, h = w**params.Scale, h**params.Scale
+	}
+
+	switch {
+	case params.Width != nil && params.Height != nil:
+		w, h = float64(*params.Width), float64(*params.Height)
+		if params.IgnoreAspectRatio == nil || !*params.IgnoreAspectRatio {
+			source = cropToAspect(bounds, w/h)
+		}
+	case params.Width != nil:
+		w, h = float64(*params.Width), float64(*params.Width)*float64(srcH)/float64(srcW)
+	case params.Height != nil:
+		w, h = float64(*params.Height)*float64(srcW)/float64(srcH), float64(*params.Height)
+	}
+
+	if params.MaxWidth != nil && w > float64(*params.MaxWidth) {
+		w, h = float64(*params.MaxWidth), h*float64(*params.MaxWidth)/w
+	}
+	if params.MaxHeight != nil && h > float64(*params.MaxHeight) {
+		w, h = w*float64(*params.MaxHeight)/h, float64(*params.MaxHeight)
+	}
+
+	// Don't enlarge unless allowed
+	if params.AllowStretch == nil || !*params.AllowStretch {
+		if ratio := math.Min(float64(source.Dx())/w, float64(source.Dy())/h); ratio < 1 {
+			w, h = w*ratio, h*ratio
+		}
+	}
+	if limit := float64(maxDimension); w > limit || h > limit {
+		ratio := math.Min(limit/w, limit/h)
+		w, h = w*ratio, h*ratio
+	}
+
+	return max(1, int(math.Round(w))), max(1, int(math.Round(h))), source
+}
+
+// cropToAspect returns the centered part of bounds with the given aspect ratio
+func cropToAspect(bounds image.Rectangle, aspect float64) image.Rectangle {
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	if float64(srcW)/float64(srcH) > aspect {
+		cropW := int(math.Round(float64(srcH) * aspect))
+		x := bounds.Min.X + (srcW-cropW)/2
+		return image.Rect(x, bounds.Min.Y, x+cropW, bounds.Max.Y)
+	}
+	cropH := int(math.Round(float64(srcW) / aspect))
+	y := bounds.Min.Y + (srcH-cropH)/2
+	return image.Rect(bounds.Min.X, y, bounds.Max.X, y+cropH)
+}
+
+// resizeImage scales the source rectangle of img to w x h using box filtering
+func resizeImage(img image.Image, source image.Rectangle, w, h int) image.Image {
+	src := image.NewRGBA(image.Rect(0, 0, source.Dx(), source.Dy()))
+	draw.Draw(src, src.Bounds(), img, source.Min, draw.Src)
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	scaleX := float64(src.Bounds().Dx()) / float64(w)
+	scaleY := float64(src.Bounds().Dy()) / float64(h)
+
+	for y := range h {
+		y0 := int(float64(y) * scaleY)
+		y1 := max(y0+1, int(float64(y+1)*scaleY))
+		for x := range w {
+			x0 := int(float64(x) * scaleX)
+			x1 := max(x0+1, int(float64(x+1)*scaleX))
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1 && sy < src.Bounds().Dy(); sy++ {
+				for sx := x0; sx < x1 && sx < src.Bounds().Dx(); sx++ {
+					offset := src.PixOffset(sx, sy)
+					r += uint64(src.Pix[offset])
+					g += uint64(src.Pix[offset+1])
+					b += uint64(src.Pix[offset+2])
+					a += uint64(src.Pix[offset+3])
+					n++
+				}
+			}
+			if n > 0 {
+				dst.SetRGBA(x, y, color.RGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(b / n), A: uint8(a / n)})
+			}
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/guitarrich/content-sdk-go/debug"
+	"github.com/guitarrich/content-sdk-go/middleware"
+	"github.com/guitarrich/content-sdk-go/site"
+)
+
+// SiteTable interface for accessing the current site table
+type SiteTable interface {
+	Status() site.SiteTableStatus
+}
+
+// SiteTableHandler exposes the current multisite site table for debugging.
+// It should only be mounted on an internal or protected route.
+type SiteTableHandler struct {
+	table SiteTable
+}
+
+// NewSiteTableHandler creates a new site table debug handler
+func NewSiteTableHandler(table SiteTable) *SiteTableHandler {
+	return &SiteTableHandler{
+		table: table,
+	}
+}
+
+// Handle processes site table requests
+func (h *SiteTableHandler) Handle(ctx middleware.Context) error {
+	debug.Multisite("handling site table request")
+
+	return ctx.JSON(http.StatusOK, h.table.Status())
+}
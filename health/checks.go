@@ -0,0 +1,116 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/guitarrich/content-sdk-go/config"
+	"github.com/guitarrich/content-sdk-go/graphql"
+	"github.com/guitarrich/content-sdk-go/models"
+	"github.com/guitarrich/content-sdk-go/site"
+)
+
+// pingQuery is the cheapest query Edge answers
+const pingQuery = `query HealthCheck { __typename }`
+
+// EdgeCheck checks that Experience Edge answers a __typename query.
+// Client retries count against the check timeout, so a client without
+// retries gives the most direct signal.
+func EdgeCheck(client graphql.Client) Check {
+	return Check{
+		Name: "edge",
+		Run: func(ctx context.Context) error {
+			if client == nil {
+				return errors.New("graphql client is not configured")
+			}
+			_, err := client.Request(ctx, pingQuery, nil)
+			return err
+		},
+	}
+}
+
+// ConfigCheck checks that the current configuration is valid.
+// Pass Watcher.Current to follow reloads.
+func ConfigCheck(current func() *config.Config) Check {
+	return Check{
+		Name: "config",
+		Run: func(ctx context.Context) error {
+			cfg := current()
+			if cfg == nil {
+				return errors.New("configuration is not loaded")
+			}
+			return cfg.Validate()
+		},
+	}
+}
+
+// CacheWarmer is a cache that can report and restore its load state,
+// such as middleware.RedirectsMiddleware
+type CacheWarmer interface {
+	Loaded() bool
+	Warm(ctx context.Context) error
+}
+
+// RedirectsCheck checks that redirects are loaded, loading them if needed so
+// a pod does not wait for its first request to become ready
+func RedirectsCheck(redirects CacheWarmer) Check {
+	return Check{
+		Name: "redirects",
+		Run: func(ctx context.Context) error {
+			if redirects.Loaded() {
+				return nil
+			}
+			if err := redirects.Warm(ctx); err != nil {
+				return fmt.Errorf("redirects not loaded: %w", err)
+			}
+			return nil
+		},
+	}
+}
+
+// SiteTableCheck checks that the site table is not empty.
+// Pass MultisiteMiddleware.Sites or DynamicSiteResolver.Sites.
+func SiteTableCheck(sites func() []models.SiteInfo) Check {
+	return Check{
+		Name: "site-table",
+		Run: func(ctx context.Context) error {
+			if len(sites()) == 0 {
+				return errors.New("site table is empty")
+			}
+			return nil
+		},
+	}
+}
+
+// DynamicSitesCheck checks that a dynamic site resolver has completed a refresh from Edge
+func DynamicSitesCheck(resolver *site.DynamicSiteResolver) Check {
+	return Check{
+		Name: "dynamic-sites",
+		Run: func(ctx context.Context) error {
+			status := resolver.Status()
+			if status.LastRefresh.IsZero() {
+				if status.LastError != "" {
+					return fmt.Errorf("site table not loaded: %s", status.LastError)
+				}
+				return errors.New("site table not loaded")
+			}
+			return nil
+		},
+	}
+}
+
+// CircuitBreakerCheck fails while a circuit breaker is open. The SDK has no
+// circuit breaker of its own; pass a function reporting the state of the
+// breaker the application wraps Edge calls with.
+func CircuitBreakerCheck(name string, open func() bool) Check {
+	return Check{
+		Name: name,
+		Run: func(ctx context.Context) error {
+			if open() {
+				return errors.New("circuit breaker is open")
+			}
+			return nil
+		},
+	}
+}
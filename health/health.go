@@ -0,0 +1,261 @@
+// Package health runs liveness and readiness checks for SDK applications.
+//
+// Checks take a context and return an error. A Checker runs them concurrently,
+// bounds each with a timeout and caches results so probes do not hammer
+// Experience Edge. Liveness and readiness checks are kept separate, as
+// Kubernetes expects: liveness failures restart the pod, readiness failures
+// only take it out of load balancing.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/guitarrich/content-sdk-go/debug"
+)
+
+// Status values of results and reports
+const (
+	StatusOK      = "ok"
+	StatusFailing = "failing"
+)
+
+// CheckFunc checks a dependency and returns an error if it is unhealthy
+type CheckFunc func(ctx context.Context) error
+
+// Check is a named health check
+type Check struct {
+	// Name identifies the check in reports
+	Name string
+
+	// Run performs the check
+	Run CheckFunc
+
+	// Timeout bounds the check (default: Config.Timeout)
+	Timeout time.Duration
+
+	// CacheTTL is how long the result is reused (default: Config.CacheTTL).
+	// Negative values disable caching.
+	CacheTTL time.Duration
+}
+
+// Config contains configuration for a Checker
+type Config struct {
+	// Timeout bounds each check (default: 2s)
+	Timeout time.Duration
+
+	// CacheTTL is how long check results are reused (default: 5s).
+	// Negative values disable caching.
+	CacheTTL time.Duration
+}
+
+// Result is the outcome of a check
+type Result struct {
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Duration  string    `json:"duration"`
+	CheckedAt time.Time `json:"checkedAt"`
+	Cached    bool      `json:"cached,omitempty"`
+}
+
+// Report is the outcome of a set of checks
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks,omitempty"`
+}
+
+// OK reports whether all checks passed
+func (r Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+// Checker runs liveness and readiness checks
+type Checker struct {
+	config Config
+
+	mu        sync.RWMutex
+	liveness  []*entry
+	readiness []*entry
+}
+
+// entry is a registered check with its cached result
+type entry struct {
+	check Check
+
+	mu      sync.Mutex
+	result  Result
+	expires time.Time
+}
+
+// NewChecker creates a checker without checks
+func NewChecker(config Config) *Checker {
+	if config.Timeout <= 0 {
+		config.Timeout = 2 * time.Second
+	}
+	if config.CacheTTL == 0 {
+		config.CacheTTL = 5 * time.Second
+	}
+
+	return &Checker{
+		config: config,
+	}
+}
+
+// AddLiveness registers checks that report whether the process should be restarted.
+// Check names must be unique among the liveness checks.
+func (c *Checker) AddLiveness(checks ...Check) *Checker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.liveness = c.add("liveness", c.liveness, checks)
+	return c
+}
+
+// AddReadiness registers checks that report whether the process can serve traffic.
+// Check names must be unique among the readiness checks.
+func (c *Checker) AddReadiness(checks ...Check) *Checker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readiness = c.add("readiness", c.readiness, checks)
+	return c
+}
+
+// add appends checks to registered entries. It panics on duplicate names, which
+// are programming errors: the report is keyed by name, so one result would be lost.
+func (c *Checker) add(kind string, registered []*entry, checks []Check) []*entry {
+	names := make(map[string]bool, len(registered)+len(checks))
+	for _, existing := range registered {
+		names[existing.check.Name] = true
+	}
+	for _, check := range checks {
+		if names[check.Name] {
+			panic(fmt.Sprintf("health: duplicate %s check %s", kind, check.Name))
+		}
+		names[check.Name] = true
+	}
+	return append(registered, c.entries(checks)...)
+}
+
+// Liveness runs the liveness checks. A checker without liveness checks is live.
+func (c *Checker) Liveness(ctx context.Context) Report {
+	c.mu.RLock()
+	entries := c.liveness
+	c.mu.RUnlock()
+	return run(ctx, entries)
+}
+
+// Readiness runs the readiness checks
+func (c *Checker) Readiness(ctx context.Context) Report {
+	c.mu.RLock()
+	entries := c.readiness
+	c.mu.RUnlock()
+	return run(ctx, entries)
+}
+
+// entries applies the checker defaults to checks
+func (c *Checker) entries(checks []Check) []*entry {
+	entries := make([]*entry, 0, len(checks))
+	for _, check := range checks {
+		if check.Timeout <= 0 {
+			check.Timeout = c.config.Timeout
+		}
+		if check.CacheTTL == 0 {
+			check.CacheTTL = c.config.CacheTTL
+		}
+		entries = append(entries, &entry{check: check})
+	}
+	return entries
+}
+
+// run runs the entries concurrently and collects their results
+func run(ctx context.Context, entries []*entry) Report {
+	report := Report{
+		Status: StatusOK,
+		Checks: make(map[string]Result, len(entries)),
+	}
+
+	results := make([]Result, len(entries))
+	var wg sync.WaitGroup
+	for i, e := range entries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = e.run(ctx)
+		}()
+	}
+	wg.Wait()
+
+	for i, e := range entries {
+		report.Checks[e.check.Name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusFailing
+		}
+	}
+	return report
+}
+
+// run returns the cached result or runs the check. Concurrent callers wait
+// for a single run instead of starting their own.
+func (e *entry) run(ctx context.Context) Result {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if time.Now().Before(e.expires) {
+		result := e.result
+		result.Cached = true
+		return result
+	}
+
+	start := time.Now()
+	err := e.call(ctx)
+	result := Result{
+		Status:    StatusOK,
+		Duration:  time.Since(start).Round(time.Microsecond).String(),
+		CheckedAt: start,
+	}
+	if err != nil {
+		result.Status = StatusFailing
+		result.Error = err.Error()
+		debug.Health("check %s failed: %v", e.check.Name, err)
+	}
+
+	// A canceled probe says nothing about the dependency, so it is not cached
+	if ctx.Err() == nil && e.check.CacheTTL > 0 {
+		e.result = result
+		e.expires = start.Add(e.check.CacheTTL)
+	}
+	return result
+}
+
+// call runs the check with its timeout. A check that ignores its context is
+// abandoned when the timeout expires.
+func (e *entry) call(ctx context.Context) error {
+	if e.check.Run == nil {
+		return errors.New("check has no Run function")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.check.Timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("check panicked: %v", r)
+			}
+		}()
+		done <- e.check.Run(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("timed out after %s", e.check.Timeout)
+		}
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,231 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/guitarrich/content-sdk-go/config"
+	"github.com/guitarrich/content-sdk-go/graphql"
+	"github.com/guitarrich/content-sdk-go/health"
+	"github.com/guitarrich/content-sdk-go/middleware"
+	"github.com/guitarrich/content-sdk-go/models"
+	"github.com/guitarrich/content-sdk-go/site"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sleepCheck returns a check that sleeps unless its context is done
+func sleepCheck(name string, d time.Duration) health.Check {
+	return health.Check{
+		Name: name,
+		Run: func(ctx context.Context) error {
+			select {
+			case <-time.After(d):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+}
+
+func TestChecker_RunsConcurrently(t *testing.T) {
+	checker := health.NewChecker(health.Config{}).AddReadiness(
+		sleepCheck("a", 100*time.Millisecond),
+		sleepCheck("b", 100*time.Millisecond),
+		sleepCheck("c", 100*time.Millisecond),
+	)
+
+	start := time.Now()
+	report := checker.Readiness(context.Background())
+
+	assert.True(t, report.OK())
+	assert.Len(t, report.Checks, 3)
+	assert.Less(t, time.Since(start), 250*time.Millisecond)
+}
+
+func TestChecker_Timeout(t *testing.T) {
+	checker := health.NewChecker(health.Config{Timeout: 20 * time.Millisecond}).AddReadiness(
+		sleepCheck("slow", time.Second),
+		health.Check{
+			Name: "stuck",
+			// Ignores its context, so it is abandoned
+			Run: func(ctx context.Context) error {
+				time.Sleep(time.Second)
+				return nil
+			},
+		},
+	)
+
+	start := time.Now()
+	report := checker.Readiness(context.Background())
+
+	assert.False(t, report.OK())
+	assert.Equal(t, health.StatusFailing, report.Status)
+	assert.Contains(t, report.Checks["slow"].Error, "timed out")
+	assert.Contains(t, report.Checks["stuck"].Error, "timed out")
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestChecker_CachesResults(t *testing.T) {
+	var calls atomic.Int32
+	counting := health.Check{
+		Name: "counting",
+		Run: func(ctx context.Context) error {
+			calls.Add(1)
+			return errors.New("down")
+		},
+	}
+	uncached := counting
+	uncached.Name = "uncached"
+	uncached.CacheTTL = -1
+
+	checker := health.NewChecker(health.Config{CacheTTL: time.Minute}).AddReadiness(counting, uncached)
+
+	first := checker.Readiness(context.Background())
+	second := checker.Readiness(context.Background())
+
+	assert.Equal(t, int32(3), calls.Load())
+	assert.False(t, first.Checks["counting"].Cached)
+	assert.True(t, second.Checks["counting"].Cached)
+	assert.Equal(t, "down", second.Checks["counting"].Error)
+	assert.False(t, second.Checks["uncached"].Cached)
+}
+
+func TestChecker_Panic(t *testing.T) {
+	checker := health.NewChecker(health.Config{}).AddLiveness(health.Check{
+		Name: "panics",
+		Run: func(ctx context.Context) error {
+			panic("boom")
+		},
+	})
+
+	report := checker.Liveness(context.Background())
+	assert.Equal(t, "check panicked: boom", report.Checks["panics"].Error)
+}
+
+func TestChecker_LivenessAndReadinessAreSeparate(t *testing.T) {
+	checker := health.NewChecker(health.Config{}).AddReadiness(health.Check{
+		Name: "edge",
+		Run:  func(ctx context.Context) error { return errors.New("unreachable") },
+	})
+
+	assert.True(t, checker.Liveness(context.Background()).OK())
+	assert.False(t, checker.Readiness(context.Background()).OK())
+}
+
+func TestChecker_RejectsDuplicateNames(t *testing.T) {
+	resolver := site.NewDynamicSiteResolver(site.DynamicSiteResolverConfig{})
+	sites := health.SiteTableCheck(func() []models.SiteInfo { return nil })
+
+	// The site checks have distinct names, and a check may be both live and ready
+	checker := health.NewChecker(health.Config{}).
+		AddReadiness(sites, health.DynamicSitesCheck(resolver)).
+		AddLiveness(sites)
+	assert.Len(t, checker.Readiness(context.Background()).Checks, 2)
+
+	assert.PanicsWithValue(t, "health: duplicate readiness check site-table", func() {
+		checker.AddReadiness(sites)
+	})
+	assert.PanicsWithValue(t, "health: duplicate liveness check a", func() {
+		checker.AddLiveness(sleepCheck("a", 0), sleepCheck("a", 0))
+	})
+	assert.Len(t, checker.Liveness(context.Background()).Checks, 1)
+}
+
+func TestEdgeCheck(t *testing.T) {
+	var query string
+	healthy := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		query = body.Query
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"data":{"__typename":"Query"}}`))
+	}))
+	defer server.Close()
+
+	check := health.EdgeCheck(graphql.NewClient(server.URL, "", nil, &graphql.ClientConfig{}))
+	require.NoError(t, check.Run(context.Background()))
+	assert.Contains(t, query, "__typename")
+
+	healthy = false
+	assert.Error(t, check.Run(context.Background()))
+}
+
+func TestConfigCheck(t *testing.T) {
+	cfg := &config.Config{}
+	check := health.ConfigCheck(func() *config.Config { return cfg })
+	assert.Error(t, check.Run(context.Background()))
+
+	cfg.API.Local.APIKey = "key"
+	cfg.API.Local.APIHost = "http://cm"
+	cfg.DefaultSite = "main"
+	assert.NoError(t, check.Run(context.Background()))
+}
+
+// warmer is a fake cache
+type warmer struct {
+	loaded bool
+	err    error
+}
+
+func (w *warmer) Loaded() bool { return w.loaded }
+
+func (w *warmer) Warm(ctx context.Context) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.loaded = true
+	return nil
+}
+
+func TestRedirectsCheck_WarmsCache(t *testing.T) {
+	cache := &warmer{}
+	require.NoError(t, health.RedirectsCheck(cache).Run(context.Background()))
+	assert.True(t, cache.loaded)
+
+	failing := &warmer{err: errors.New("edge down")}
+	err := health.RedirectsCheck(failing).Run(context.Background())
+	assert.EqualError(t, err, "redirects not loaded: edge down")
+}
+
+func TestReadinessCheckHandler(t *testing.T) {
+	breakerOpen := true
+	checker := health.NewChecker(health.Config{CacheTTL: -1}).AddReadiness(
+		health.CircuitBreakerCheck("edge-breaker", func() bool { return breakerOpen }),
+	)
+	handler := middleware.ReadinessCheckHandler(checker)
+
+	serve := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		ctx := middleware.NewEchoContext(echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/readyz", nil), rec))
+		require.NoError(t, handler(ctx))
+		return rec
+	}
+
+	rec := serve()
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.True(t, strings.Contains(rec.Body.String(), `"error":"circuit breaker is open"`))
+
+	breakerOpen = false
+	rec = serve()
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var report health.Report
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, health.StatusOK, report.Checks["edge-breaker"].Status)
+}
@@ -3,10 +3,15 @@ package i18n
 import (
 	"context"
 	"fmt"
+	"maps"
+	"time"
 
 	"github.com/guitarrich/content-sdk-go/debug"
 	"github.com/guitarrich/content-sdk-go/graphql"
+	"github.com/guitarrich/content-sdk-go/metrics"
 	"github.com/guitarrich/content-sdk-go/models"
+	"github.com/guitarrich/content-sdk-go/telemetry"
+	"github.com/guitarrich/content-sdk-go/utils"
 )
 
 // DictionaryService fetches dictionary phrases for internationalization
@@ -18,20 +23,35 @@ type DictionaryService interface {
 type DictionaryServiceConfig struct {
 	GraphQLClient graphql.Client
 	SiteName      string
+
+	// LanguageFallback merges in phrases from fallback languages for keys
+	// missing in the requested language (optional)
+	LanguageFallback *LanguageFallback
+
+	// CacheTTL caches the phrases of each site and language for the duration
+	// (default: no caching). Lookups are recorded as "dictionary" cache metrics.
+	CacheTTL time.Duration
 }
 
 // dictionaryServiceImpl is the default implementation
 type dictionaryServiceImpl struct {
-	graphQLClient graphql.Client
-	siteName      string
+	graphQLClient    graphql.Client
+	siteName         string
+	languageFallback *LanguageFallback
+	cache            *utils.TTLCache[models.DictionaryPhrases]
 }
 
 // NewDictionaryService creates a new dictionary service
 func NewDictionaryService(config DictionaryServiceConfig) DictionaryService {
-	return &dictionaryServiceImpl{
-		graphQLClient: config.GraphQLClient,
-		siteName:      config.SiteName,
+	s := &dictionaryServiceImpl{
+		graphQLClient:    config.GraphQLClient,
+		siteName:         config.SiteName,
+		languageFallback: config.LanguageFallback,
+	}
+	if config.CacheTTL > 0 {
+		s.cache = utils.NewTTLCache[models.DictionaryPhrases](config.CacheTTL)
 	}
+	return s
 }
 
 // FetchDictionaryData fetches all dictionary phrases for a given locale
@@ -48,6 +68,58 @@ func (s *dictionaryServiceImpl) FetchDictionaryData(
 		site = s.siteName
 	}
 
+	phrases, err := s.fetchPhrases(ctx, site, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	// Merge keys from fallback languages; phrases in more specific languages win
+	if chain := s.languageFallback.Chain(site, locale); len(chain) > 1 {
+		for _, language := range chain[1:] {
+			fallbackPhrases, err := s.fetchPhrases(ctx, site, language)
+			if err != nil {
+				debug.Dictionary("skipping fallback language %s: %v", language, err)
+				continue
+			}
+
+			merged := 0
+			for key, value := range fallbackPhrases {
+				if _, exists := phrases[key]; !exists {
+					phrases[key] = value
+					merged++
+				}
+			}
+			debug.Dictionary("merged %d phrases from fallback language %s", merged, language)
+		}
+	}
+
+	debug.Dictionary("fetched %d dictionary phrases", len(phrases))
+	return phrases, nil
+}
+
+// fetchPhrases fetches the dictionary phrases for a single language
+// Cached phrases are copied, as callers merge fallback phrases into the result.
+func (s *dictionaryServiceImpl) fetchPhrases(ctx context.Context, site, locale string) (models.DictionaryPhrases, error) {
+	if s.cache == nil {
+		return s.queryPhrases(ctx, site, locale)
+	}
+
+	key := site + "|" + locale
+	phrases, hit := s.cache.Get(key)
+	telemetry.RecordCacheLookup(ctx, "dictionary", hit)
+	metrics.RecordCacheLookup("dictionary", hit)
+	if !hit {
+		var err error
+		if phrases, err = s.queryPhrases(ctx, site, locale); err != nil {
+			return nil, err
+		}
+		s.cache.Set(key, phrases)
+	}
+	return maps.Clone(phrases), nil
+}
+
+// queryPhrases queries the dictionary phrases for a single language
+func (s *dictionaryServiceImpl) queryPhrases(ctx context.Context, site, locale string) (models.DictionaryPhrases, error) {
 	// Build GraphQL query
 	query := s.getDictionaryQuery(site, locale)
 
@@ -63,7 +135,6 @@ func (s *dictionaryServiceImpl) FetchDictionaryData(
 		return nil, fmt.Errorf("failed to parse dictionary response: %w", err)
 	}
 
-	debug.Dictionary("fetched %d dictionary phrases", len(phrases))
 	return phrases, nil
 }
 
@@ -3,6 +3,9 @@ package i18n
 import (
 	"context"
 	"testing"
+	"time"
+
+	"github.com/guitarrich/content-sdk-go/metrics"
 )
 
 // Mock GraphQL client for testing
@@ -160,3 +163,47 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestDictionaryService_FetchDictionaryData_Cache(t *testing.T) {
+	mockClient := &languageGraphQLClient{
+		dictionaries: map[string][]any{
+			"fr": {map[string]any{"key": "welcome", "value": "Bienvenue"}},
+			"en": {map[string]any{"key": "welcome", "value": "Welcome"}, map[string]any{"key": "hello", "value": "Hello"}},
+		},
+	}
+
+	service := NewDictionaryService(DictionaryServiceConfig{
+		GraphQLClient:    mockClient,
+		SiteName:         "testsite",
+		LanguageFallback: &LanguageFallback{DefaultLanguage: "en"},
+		CacheTTL:         time.Minute,
+	})
+
+	hits := metrics.CacheLookups.Value("dictionary", "hit")
+	misses := metrics.CacheLookups.Value("dictionary", "miss")
+
+	first, err := service.FetchDictionaryData(context.Background(), "fr", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Mutating the result must not leak into the cache
+	first["welcome"] = "changed"
+
+	second, err := service.FetchDictionaryData(context.Background(), "fr", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if mockClient.requests != 2 {
+		t.Errorf("expected 2 requests (fr and en), got %d", mockClient.requests)
+	}
+	if second["welcome"] != "Bienvenue" || second["hello"] != "Hello" {
+		t.Errorf("expected cached phrases, got %v", second)
+	}
+	if got := metrics.CacheLookups.Value("dictionary", "miss") - misses; got != 2 {
+		t.Errorf("expected 2 dictionary cache misses, got %v", got)
+	}
+	if got := metrics.CacheLookups.Value("dictionary", "hit") - hits; got != 2 {
+		t.Errorf("expected 2 dictionary cache hits, got %v", got)
+	}
+}
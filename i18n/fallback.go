@@ -0,0 +1,78 @@
+package i18n
+
+import "strings"
+
+// LanguageFallback configures the languages to try when content is missing
+// in the requested language. The zero value falls back through the RFC 4647
+// truncation chain only (fr-CA -> fr).
+type LanguageFallback struct {
+	// DefaultLanguage is tried after every other language in the chain (optional)
+	DefaultLanguage string
+
+	// Chains maps a language to its explicit fallback languages, e.g. "fr-CA": {"fr", "en-CA"}.
+	// Languages without an entry fall back by truncating subtags.
+	Chains map[string][]string
+
+	// Sites holds per-site settings keyed by site name. Site chains take precedence
+	// over Chains and a non-empty site DefaultLanguage replaces DefaultLanguage.
+	Sites map[string]LanguageFallback
+}
+
+// Chain returns the languages to try for a site, starting with the requested
+// language and without duplicates. A nil LanguageFallback returns only the language.
+func (f *LanguageFallback) Chain(site, language string) []string {
+	if language == "" {
+		return nil
+	}
+	if f == nil {
+		return []string{language}
+	}
+
+	defaultLanguage := f.DefaultLanguage
+	var siteChains map[string][]string
+	for name, siteFallback := range f.Sites {
+		if strings.EqualFold(name, site) {
+			siteChains = siteFallback.Chains
+			if siteFallback.DefaultLanguage != "" {
+				defaultLanguage = siteFallback.DefaultLanguage
+			}
+			break
+		}
+	}
+
+	var languages []string
+	if fallbacks, ok := findChain(siteChains, language); ok {
+		languages = append([]string{language}, fallbacks...)
+	} else if fallbacks, ok := findChain(f.Chains, language); ok {
+		languages = append([]string{language}, fallbacks...)
+	} else {
+		languages = FallbackChain(language)
+	}
+	if defaultLanguage != "" {
+		languages = append(languages, defaultLanguage)
+	}
+
+	// Remove duplicates, keeping the first occurrence
+	chain := make([]string, 0, len(languages))
+	seen := make(map[string]bool, len(languages))
+	for _, l := range languages {
+		key := strings.ToLower(l)
+		if l == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		chain = append(chain, l)
+	}
+
+	return chain
+}
+
+// findChain looks up the fallback languages for a language (case-insensitive)
+func findChain(chains map[string][]string, language string) ([]string, bool) {
+	for key, fallbacks := range chains {
+		if strings.EqualFold(key, language) {
+			return fallbacks, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,99 @@
+package i18n
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestLanguageFallback_Chain(t *testing.T) {
+	fallback := &LanguageFallback{
+		DefaultLanguage: "en",
+		Chains: map[string][]string{
+			"fr-CA": {"fr", "en-CA"},
+		},
+		Sites: map[string]LanguageFallback{
+			"swiss": {
+				DefaultLanguage: "de",
+				Chains:          map[string][]string{"fr-CH": {"fr", "de-CH"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		site     string
+		language string
+		expected []string
+	}{
+		{"global", "fr-CA", []string{"fr-CA", "fr", "en-CA", "en"}},
+		{"global", "de-AT", []string{"de-AT", "de", "en"}},
+		{"global", "en", []string{"en"}},
+		{"Swiss", "fr-CH", []string{"fr-CH", "fr", "de-CH", "de"}},
+		{"swiss", "fr-CA", []string{"fr-CA", "fr", "en-CA", "de"}},
+		{"global", "", nil},
+	}
+
+	for _, tt := range tests {
+		if chain := fallback.Chain(tt.site, tt.language); !reflect.DeepEqual(chain, tt.expected) {
+			t.Errorf("Chain(%q, %q): expected %v, got %v", tt.site, tt.language, tt.expected, chain)
+		}
+	}
+
+	var none *LanguageFallback
+	if chain := none.Chain("global", "fr-CA"); !reflect.DeepEqual(chain, []string{"fr-CA"}) {
+		t.Errorf("expected nil fallback to return only the language, got %v", chain)
+	}
+}
+
+// languageGraphQLClient returns a dictionary response per requested language
+type languageGraphQLClient struct {
+	dictionaries map[string][]any
+	requests     int
+}
+
+var dictionaryLanguagePattern = regexp.MustCompile(`dictionary\(language: "([^"]+)"\)`)
+
+func (m *languageGraphQLClient) Request(ctx context.Context, query string, variables map[string]any) (map[string]any, error) {
+	m.requests++
+	language := dictionaryLanguagePattern.FindStringSubmatch(query)[1]
+	return map[string]any{
+		"site": map[string]any{
+			"siteInfo": map[string]any{
+				"dictionary": m.dictionaries[language],
+			},
+		},
+	}, nil
+}
+
+func TestDictionaryService_FetchDictionaryData_LanguageFallback(t *testing.T) {
+	mockClient := &languageGraphQLClient{
+		dictionaries: map[string][]any{
+			"fr-CA": {map[string]any{"key": "welcome", "value": "Bienvenue!"}},
+			"fr":    {map[string]any{"key": "welcome", "value": "Bienvenue"}, map[string]any{"key": "goodbye", "value": "Au revoir"}},
+			"en":    {map[string]any{"key": "goodbye", "value": "Goodbye"}, map[string]any{"key": "hello", "value": "Hello"}},
+		},
+	}
+
+	service := NewDictionaryService(DictionaryServiceConfig{
+		GraphQLClient:    mockClient,
+		SiteName:         "testsite",
+		LanguageFallback: &LanguageFallback{DefaultLanguage: "en"},
+	})
+
+	phrases, err := service.FetchDictionaryData(context.Background(), "fr-CA", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"welcome": "Bienvenue!",
+		"goodbye": "Au revoir",
+		"hello":   "Hello",
+	}
+	for key, value := range expected {
+		if phrases[key] != value {
+			t.Errorf("expected %s='%s', got '%s'", key, value, phrases[key])
+		}
+	}
+}
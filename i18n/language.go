@@ -0,0 +1,121 @@
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LanguageRange is a weighted language range from an Accept-Language header
+type LanguageRange struct {
+	// Tag is the language range (e.g. "fr-CA" or "*")
+	Tag string
+
+	// Quality is the q-value between 0 and 1
+	Quality float64
+}
+
+// ParseAcceptLanguage parses an Accept-Language header into language ranges
+// ordered by descending quality. Ranges with q=0 or an invalid q-value are dropped.
+func ParseAcceptLanguage(header string) []LanguageRange {
+	var ranges []LanguageRange
+
+	for part := range strings.SplitSeq(header, ",") {
+		params := strings.Split(part, ";")
+		tag := strings.TrimSpace(params[0])
+		if tag == "" {
+			continue
+		}
+
+		quality := 1.0
+		valid := true
+		for _, param := range params[1:] {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.TrimSpace(name) != "q" {
+				continue
+			}
+			q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil || q < 0 || q > 1 {
+				valid = false
+				break
+			}
+			quality = q
+		}
+		if !valid || quality == 0 {
+			continue
+		}
+
+		ranges = append(ranges, LanguageRange{Tag: tag, Quality: quality})
+	}
+
+	// Stable sort keeps header order for equal q-values
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].Quality > ranges[j].Quality
+	})
+
+	return ranges
+}
+
+// FallbackChain returns the RFC 4647 lookup chain for a language tag by
+// progressively truncating subtags, e.g. "zh-Hant-TW" -> "zh-Hant-TW", "zh-Hant", "zh".
+// Single-character subtags (extensions) are removed together with the subtag after them.
+func FallbackChain(tag string) []string {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return nil
+	}
+
+	chain := []string{tag}
+	for {
+		idx := strings.LastIndex(tag, "-")
+		if idx <= 0 {
+			return chain
+		}
+		tag = tag[:idx]
+
+		// Drop a trailing singleton such as "x" in "en-x-private"
+		if idx := strings.LastIndex(tag, "-"); idx > 0 && len(tag)-idx == 2 {
+			tag = tag[:idx]
+		}
+		chain = append(chain, tag)
+	}
+}
+
+// Lookup returns the supported language matching tag using RFC 4647 lookup.
+// Matching is case-insensitive and the language is returned as configured in supported.
+func Lookup(tag string, supported []string) (string, bool) {
+	for _, candidate := range FallbackChain(tag) {
+		for _, language := range supported {
+			if strings.EqualFold(language, candidate) {
+				return language, true
+			}
+		}
+	}
+	return "", false
+}
+
+// MatchAcceptLanguage returns the best supported language for an Accept-Language header.
+// Ranges are tried in quality order, first by lookup (fr-CA -> fr) and then by
+// prefix filtering ("fr" matches "fr-FR"). "*" matches the first supported language.
+func MatchAcceptLanguage(header string, supported []string) (string, bool) {
+	if len(supported) == 0 {
+		return "", false
+	}
+
+	for _, r := range ParseAcceptLanguage(header) {
+		if r.Tag == "*" {
+			return supported[0], true
+		}
+		if language, ok := Lookup(r.Tag, supported); ok {
+			return language, true
+		}
+		for _, language := range supported {
+			if len(language) > len(r.Tag) && language[len(r.Tag)] == '-' &&
+				strings.EqualFold(language[:len(r.Tag)], r.Tag) {
+				return language, true
+			}
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,62 @@
+package i18n
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	ranges := ParseAcceptLanguage("fr;q=0.8, en-US, de;q=0, es;q=0.8, *;q=0.1, it;q=abc")
+
+	expected := []LanguageRange{
+		{Tag: "en-US", Quality: 1},
+		{Tag: "fr", Quality: 0.8},
+		{Tag: "es", Quality: 0.8},
+		{Tag: "*", Quality: 0.1},
+	}
+	if !reflect.DeepEqual(ranges, expected) {
+		t.Errorf("expected %v, got %v", expected, ranges)
+	}
+}
+
+func TestFallbackChain(t *testing.T) {
+	tests := map[string][]string{
+		"fr-CA":              {"fr-CA", "fr"},
+		"zh-Hant-TW":         {"zh-Hant-TW", "zh-Hant", "zh"},
+		"en-x-private":       {"en-x-private", "en"},
+		"en":                 {"en"},
+		"":                   nil,
+		"de-DE-u-co-phonebk": {"de-DE-u-co-phonebk", "de-DE-u-co", "de-DE", "de"},
+	}
+
+	for tag, expected := range tests {
+		if chain := FallbackChain(tag); !reflect.DeepEqual(chain, expected) {
+			t.Errorf("FallbackChain(%q): expected %v, got %v", tag, expected, chain)
+		}
+	}
+}
+
+func TestMatchAcceptLanguage(t *testing.T) {
+	supported := []string{"en", "fr", "de-DE"}
+
+	tests := []struct {
+		header   string
+		expected string
+		ok       bool
+	}{
+		{"fr-CA,en;q=0.5", "fr", true},
+		{"FR", "fr", true},
+		{"es,de;q=0.9", "de-DE", true},
+		{"es;q=0.9,en;q=0.1", "en", true},
+		{"es,*;q=0.5", "en", true},
+		{"es,it", "", false},
+		{"fr;q=0,en;q=0.1", "en", true},
+	}
+
+	for _, tt := range tests {
+		language, ok := MatchAcceptLanguage(tt.header, supported)
+		if language != tt.expected || ok != tt.ok {
+			t.Errorf("MatchAcceptLanguage(%q): expected %q/%v, got %q/%v", tt.header, tt.expected, tt.ok, language, ok)
+		}
+	}
+}
@@ -4,11 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"time"
 
+	"github.com/guitarrich/content-sdk-go/config"
 	"github.com/guitarrich/content-sdk-go/debug"
 	"github.com/guitarrich/content-sdk-go/graphql"
+	"github.com/guitarrich/content-sdk-go/i18n"
+	"github.com/guitarrich/content-sdk-go/metrics"
+	"github.com/guitarrich/content-sdk-go/telemetry"
+	"github.com/guitarrich/content-sdk-go/utils"
 )
 
 // GraphQLLayoutQueryName is the name of the GraphQL query for layout data
@@ -38,12 +44,27 @@ type LayoutServiceConfig struct {
 	GraphQLServiceConfig
 	// FormatLayoutQuery is an optional function to customize the layout query
 	FormatLayoutQuery func(site, itemPath string, language *string) string
+
+	// LanguageFallback is tried in order when the item has no version in the
+	// requested language (optional)
+	LanguageFallback *i18n.LanguageFallback
+
+	// CacheTTL caches layout responses per site, language and path for the
+	// duration (default: no caching). Lookups are recorded as "layout" cache metrics.
+	CacheTTL time.Duration
+}
+
+// LayoutFetcher fetches layout data for routes. It is implemented by
+// LayoutService and by the file-based service of the disconnected package.
+type LayoutFetcher interface {
+	FetchLayoutDataContext(ctx context.Context, itemPath string, routeOptions RouteOptions, fetchOptions *FetchOptions) (*LayoutServiceData, error)
 }
 
 // LayoutService fetches layout data using Sitecore's GraphQL API
 type LayoutService struct {
 	serviceConfig LayoutServiceConfig
 	graphQLClient GraphQLClient
+	cache         *utils.TTLCache[map[string]any]
 }
 
 // Note: defaultGraphQLClient removed - now using graphql.Client
@@ -58,34 +79,61 @@ func NewLayoutService(serviceConfig LayoutServiceConfig) *LayoutService {
 		nil, // Use default config
 	)
 
-	return &LayoutService{
-		serviceConfig: serviceConfig,
-		graphQLClient: graphQLClient,
-	}
+	return NewLayoutServiceWithClient(serviceConfig, graphQLClient)
 }
 
 // NewLayoutServiceWithClient creates a new LayoutService with a custom GraphQL client
 func NewLayoutServiceWithClient(serviceConfig LayoutServiceConfig, graphQLClient GraphQLClient) *LayoutService {
-	return &LayoutService{
+	ls := &LayoutService{
 		serviceConfig: serviceConfig,
 		graphQLClient: graphQLClient,
 	}
+	if serviceConfig.CacheTTL > 0 {
+		ls.cache = utils.NewTTLCache[map[string]any](serviceConfig.CacheTTL)
+	}
+	return ls
+}
+
+// OnConfigChange implements config.Subscriber and forwards the change to the GraphQL client
+func (ls *LayoutService) OnConfigChange(old, new *config.Config) {
+	ls.ClearCache()
+	if subscriber, ok := ls.graphQLClient.(config.Subscriber); ok {
+		subscriber.OnConfigChange(old, new)
+	}
+}
+
+// ClearCache drops the cached layout responses
+func (ls *LayoutService) ClearCache() {
+	if ls.cache != nil {
+		ls.cache.Clear()
+	}
 }
 
 // FetchLayoutData fetches layout data for an item
+// It is FetchLayoutDataContext with a background context.
+func (ls *LayoutService) FetchLayoutData(
+	itemPath string,
+	routeOptions RouteOptions,
+	fetchOptions *FetchOptions,
+) (*LayoutServiceData, error) {
+	return ls.FetchLayoutDataContext(context.Background(), itemPath, routeOptions, fetchOptions)
+}
+
+// FetchLayoutDataContext fetches layout data for an item
 // Parameters:
+//   - ctx: context for cancellation and tracing
 //   - itemPath: item path to fetch layout data for
 //   - routeOptions: Request options like language and site to retrieve data for
 //   - fetchOptions: Options to override graphQL client details like retries and fetch implementation
 //
 // Returns: layout service data
-func (ls *LayoutService) FetchLayoutData(
+func (ls *LayoutService) FetchLayoutDataContext(
+	ctx context.Context,
 	itemPath string,
 	routeOptions RouteOptions,
 	fetchOptions *FetchOptions,
-) (*LayoutServiceData, error) {
+) (layoutData *LayoutServiceData, err error) {
 	site := routeOptions.Site
-	query := ls.getLayoutQuery(itemPath, site, routeOptions.Locale)
 
 	localeStr := ""
 	if routeOptions.Locale != nil {
@@ -93,21 +141,70 @@ func (ls *LayoutService) FetchLayoutData(
 	}
 	debug.Layout("fetching layout data for %s %s %s", itemPath, localeStr, site)
 
+	ctx, span := telemetry.StartSpan(ctx, "layout.fetch",
+		slog.String("sitecore.site", site),
+		slog.String("sitecore.locale", localeStr),
+		slog.String("sitecore.path", itemPath))
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	// Create context with timeout if specified in fetchOptions
-	ctx := context.Background()
 	if fetchOptions != nil && fetchOptions.Timeout != nil {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, *fetchOptions.Timeout)
 		defer cancel()
 	}
 
-	data, err := ls.graphQLClient.Request(ctx, query, map[string]any{})
+	layoutData, err = ls.fetchLayout(ctx, itemPath, site, routeOptions.Locale)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch layout data: %w", err)
+		return nil, err
+	}
+
+	// Try fallback languages when the item has no version in the requested language
+	if layoutData.Sitecore.Route == nil {
+		if chain := ls.serviceConfig.LanguageFallback.Chain(site, localeStr); len(chain) > 1 {
+			for _, language := range chain[1:] {
+				debug.Layout("no route for %s in %s, trying fallback language %s", itemPath, localeStr, language)
+
+				fallbackData, err := ls.fetchLayout(ctx, itemPath, site, &language)
+				if err != nil {
+					return nil, err
+				}
+				if fallbackData.Sitecore.Route != nil {
+					// Record the language that was actually served
+					fallbackData.Sitecore.Context.Language = &language
+					return fallbackData, nil
+				}
+			}
+		}
+	}
+
+	return layoutData, nil
+}
+
+// fetchLayout fetches and parses layout data for a single language
+func (ls *LayoutService) fetchLayout(ctx context.Context, itemPath, site string, locale *string) (*LayoutServiceData, error) {
+	query := ls.getLayoutQuery(itemPath, site, locale)
+
+	// Responses are cached rather than parsed data, so every caller gets its own copy
+	data, hit := map[string]any(nil), false
+	if ls.cache != nil {
+		data, hit = ls.cache.Get(query)
+		telemetry.RecordCacheLookup(ctx, "layout", hit)
+		metrics.RecordCacheLookup("layout", hit)
+	}
+	if !hit {
+		var err error
+		data, err = ls.graphQLClient.Request(ctx, query, map[string]any{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch layout data: %w", err)
+		}
+		if ls.cache != nil {
+			ls.cache.Set(query, data)
+		}
 	}
 
 	// Parse the response
-	layoutData, err := ls.parseLayoutResponse(data, routeOptions.Locale)
+	layoutData, err := ls.parseLayoutResponse(data, locale)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse layout response: %w", err)
 	}
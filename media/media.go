@@ -2,8 +2,12 @@ package media
 
 import (
 	"fmt"
+	"math"
 	"net/url"
+	"slices"
 	"strings"
+
+	"github.com/guitarrich/content-sdk-go/debug"
 )
 
 // ImageParams contains parameters for image transformation
@@ -64,12 +68,49 @@ type ImageFieldValue struct {
 // MediaAPI provides functions for working with Sitecore media
 type MediaAPI struct {
 	mediaServerURL string
+	mediaHost      string
+	sharedSecret   string
+	mediaPaths     []string
+}
+
+// MediaAPIConfig contains configuration for the media API
+type MediaAPIConfig struct {
+	// MediaServerURL is prepended to relative media paths
+	MediaServerURL string
+
+	// MediaHost is a CDN or Edge host that media URLs are rewritten to (optional).
+	// URLs whose path starts with one of MediaPaths are rewritten, whether relative
+	// or on another host. The host may include a base path.
+	MediaHost string
+
+	// MediaPaths are the path prefixes rewritten to MediaHost (default: /-/media/, /-/jssmedia/)
+	MediaPaths []string
+
+	// SharedSecret signs resize parameters with a hash, as Sitecore media request
+	// protection requires (optional). It must match the Media.RequestProtection.SharedSecret setting.
+	SharedSecret string
 }
 
 // NewMediaAPI creates a new media API instance
 func NewMediaAPI(mediaServerURL string) *MediaAPI {
+	return NewMediaAPIWithConfig(MediaAPIConfig{
+		MediaServerURL: mediaServerURL,
+	})
+}
+
+// NewMediaAPIWithConfig creates a new media API instance with host rewriting and request protection
+func NewMediaAPIWithConfig(config MediaAPIConfig) *MediaAPI {
+	mediaPaths := config.MediaPaths
+	if len(mediaPaths) == 0 {
+		mediaPaths = []string{"/-/media/", "/-/jssmedia/"}
+	}
+	debug.RegisterSecret(config.SharedSecret)
+
 	return &MediaAPI{
-		mediaServerURL: strings.TrimSuffix(mediaServerURL, "/"),
+		mediaServerURL: strings.TrimSuffix(config.MediaServerURL, "/"),
+		mediaHost:      strings.TrimSuffix(config.MediaHost, "/"),
+		sharedSecret:   config.SharedSecret,
+		mediaPaths:     mediaPaths,
 	}
 }
 
@@ -79,23 +120,71 @@ func (m *MediaAPI) GetImageURL(imageField *ImageField, params *ImageParams) stri
 		return ""
 	}
 
-	src := imageField.Value.Src
+	return m.GetMediaURL(imageField.Value.Src, params)
+}
+
+// GetMediaURL generates a URL for a media path (e.g. /-/media/file.pdf) with optional parameters.
+// Legacy ~/media/ paths are normalized to /-/media/.
+func (m *MediaAPI) GetMediaURL(src string, params *ImageParams) string {
+	if src == "" {
+		return ""
+	}
 
 	// If src is already a full URL, use it as base
 	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
-		return m.buildURL(src, params)
+		return m.buildURL(m.rewriteHost(src), params)
 	}
 
-	// If src is a relative path, prepend media server URL
-	if strings.HasPrefix(src, "/") {
-		src = m.mediaServerURL + src
+	// Normalize legacy media paths
+	if rest, ok := strings.CutPrefix(src, "~/media/"); ok {
+		src = "/-/media/" + rest
+	} else if rest, ok := strings.CutPrefix(src, "/~/media/"); ok {
+		src = "/-/media/" + rest
+	}
+
+	if !strings.HasPrefix(src, "/") {
+		src = "/" + src
+	}
+
+	// Prepend the media host for media paths, otherwise the media server URL
+	if m.mediaHost != "" && m.isMediaPath(src) {
+		src = m.mediaHost + src
 	} else {
-		src = m.mediaServerURL + "/" + src
+		src = m.mediaServerURL + src
 	}
 
 	return m.buildURL(src, params)
 }
 
+// rewriteHost replaces the host of an absolute media URL with the media host
+func (m *MediaAPI) rewriteHost(src string) string {
+	if m.mediaHost == "" {
+		return src
+	}
+
+	u, err := url.Parse(src)
+	if err != nil || !m.isMediaPath(u.Path) {
+		return src
+	}
+
+	rewritten := m.mediaHost + u.EscapedPath()
+	if u.RawQuery != "" {
+		rewritten += "?" + u.RawQuery
+	}
+	return rewritten
+}
+
+// isMediaPath reports whether a path starts with one of the media path prefixes
+func (m *MediaAPI) isMediaPath(path string) bool {
+	lower := strings.ToLower(path)
+	for _, prefix := range m.mediaPaths {
+		if strings.HasPrefix(lower, strings.ToLower(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
 // buildURL builds the final URL with query parameters
 func (m *MediaAPI) buildURL(baseURL string, params *ImageParams) string {
 	if params == nil {
@@ -164,8 +253,12 @@ func (m *MediaAPI) buildURL(baseURL string, params *ImageParams) string {
 		q.Set("vs", *params.Version)
 	}
 
-	// Set the query string
+	// Sign the resize parameters
+	q.Del(hashParameter)
 	u.RawQuery = q.Encode()
+	if m.sharedSecret != "" && hasProtectedParameters(q) {
+		u.RawQuery += "&" + hashParameter + "=" + ProtectionHash(u.EscapedPath()+"?"+u.RawQuery, m.sharedSecret)
+	}
 
 	return u.String()
 }
@@ -184,3 +277,42 @@ func (m *MediaAPI) GetResponsiveImageURL(imageField *ImageField, widths []int) m
 
 	return result
 }
+
+// GetSrcSet generates a srcset attribute value with one URL per width ("url 320w, url 640w").
+// When params sets both Width and Height, each height is scaled to keep that aspect ratio.
+func (m *MediaAPI) GetSrcSet(imageField *ImageField, widths []int, params *ImageParams) string {
+	if imageField == nil || imageField.Value == nil || imageField.Value.Src == "" {
+		return ""
+	}
+
+	sorted := slices.Clone(widths)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+
+	candidates := make([]string, 0, len(sorted))
+	for _, width := range sorted {
+		if width <= 0 {
+			continue
+		}
+		candidates = append(candidates, fmt.Sprintf("%s %dw", m.GetImageURL(imageField, ParamsForWidth(params, width)), width))
+	}
+
+	return strings.Join(candidates, ", ")
+}
+
+// ParamsForWidth returns a copy of params with Width set to width.
+// When params sets both Width and Height, Height is scaled to keep that aspect ratio.
+func ParamsForWidth(params *ImageParams, width int) *ImageParams {
+	scaled := ImageParams{}
+	if params != nil {
+		scaled = *params
+	}
+
+	if scaled.Width != nil && scaled.Height != nil && *scaled.Width > 0 {
+		height := int(math.Round(float64(width) * float64(*scaled.Height) / float64(*scaled.Width)))
+		scaled.Height = &height
+	}
+	scaled.Width = &width
+
+	return &scaled
+}
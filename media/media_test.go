@@ -100,6 +100,25 @@ func TestMediaAPI_GetImageURL_EmptyValue(t *testing.T) {
 	}
 }
 
+func TestMediaAPI_GetMediaURL(t *testing.T) {
+	api := NewMediaAPI("https://media.example.com")
+
+	tests := map[string]string{
+		"/-/media/file.pdf":     "https://media.example.com/-/media/file.pdf",
+		"-/media/file.pdf":      "https://media.example.com/-/media/file.pdf",
+		"~/media/ABC.ashx":      "https://media.example.com/-/media/ABC.ashx",
+		"/~/media/ABC.ashx":     "https://media.example.com/-/media/ABC.ashx",
+		"https://cdn.test/a.js": "https://cdn.test/a.js",
+		"":                      "",
+	}
+
+	for src, expected := range tests {
+		if url := api.GetMediaURL(src, nil); url != expected {
+			t.Errorf("GetMediaURL(%q): expected %s, got %s", src, expected, url)
+		}
+	}
+}
+
 func TestMediaAPI_GetResponsiveImageURL(t *testing.T) {
 	api := NewMediaAPI("https://media.example.com")
 
@@ -129,6 +148,34 @@ func TestMediaAPI_GetResponsiveImageURL(t *testing.T) {
 	}
 }
 
+func TestMediaAPI_GetSrcSet(t *testing.T) {
+	api := NewMediaAPI("https://media.example.com")
+
+	imageField := &ImageField{
+		Value: &ImageFieldValue{
+			Src: "/-/media/hero.jpg",
+		},
+	}
+
+	srcSet := api.GetSrcSet(imageField, []int{640, 320, 640}, nil)
+	expected := "https://media.example.com/-/media/hero.jpg?w=320 320w, https://media.example.com/-/media/hero.jpg?w=640 640w"
+	if srcSet != expected {
+		t.Errorf("expected %s, got %s", expected, srcSet)
+	}
+
+	// Crop to 16:9
+	width, height := 1600, 900
+	srcSet = api.GetSrcSet(imageField, []int{800}, &ImageParams{Width: &width, Height: &height})
+	expected = "https://media.example.com/-/media/hero.jpg?h=450&w=800 800w"
+	if srcSet != expected {
+		t.Errorf("expected %s, got %s", expected, srcSet)
+	}
+
+	if srcSet := api.GetSrcSet(&ImageField{}, []int{320}, nil); srcSet != "" {
+		t.Errorf("expected empty srcset, got %s", srcSet)
+	}
+}
+
 func TestMediaAPI_BuildURL_AllParams(t *testing.T) {
 	api := NewMediaAPI("https://media.example.com")
 
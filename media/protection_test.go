@@ -0,0 +1,59 @@
+package media
+
+import "testing"
+
+func TestProtectionHash(t *testing.T) {
+	// Only protected parameters are hashed and the URL is lower-cased
+	hash := ProtectionHash("/-/media/Hero.jpg?la=en&h=450&w=800", "s3cret")
+	if expected := "66B029CB4494BE925DF7384FE2F46F59"; hash != expected {
+		t.Errorf("expected %s, got %s", expected, hash)
+	}
+}
+
+func TestMediaAPI_SharedSecret(t *testing.T) {
+	api := NewMediaAPIWithConfig(MediaAPIConfig{
+		MediaServerURL: "https://cm.example.com",
+		SharedSecret:   "s3cret",
+	})
+
+	width := 800
+	imageField := &ImageField{
+		Value: &ImageFieldValue{
+			Src: "/-/media/hero.jpg?hash=STALE",
+		},
+	}
+
+	url := api.GetImageURL(imageField, &ImageParams{Width: &width})
+	expected := "https://cm.example.com/-/media/hero.jpg?w=800&hash=EFF09D59848AA6EBCAC46414CA9B4EF4"
+	if url != expected {
+		t.Errorf("expected %s, got %s", expected, url)
+	}
+
+	// Parameters that are not protected don't need a hash
+	language := "en"
+	url = api.GetImageURL(imageField, &ImageParams{Language: &language})
+	if expected := "https://cm.example.com/-/media/hero.jpg?la=en"; url != expected {
+		t.Errorf("expected %s, got %s", expected, url)
+	}
+}
+
+func TestMediaAPI_MediaHost(t *testing.T) {
+	api := NewMediaAPIWithConfig(MediaAPIConfig{
+		MediaServerURL: "https://cm.example.com",
+		MediaHost:      "https://cdn.example.com/",
+	})
+
+	tests := map[string]string{
+		"/-/media/hero.jpg":                           "https://cdn.example.com/-/media/hero.jpg",
+		"https://cm.example.com/-/jssmedia/a.png?w=1": "https://cdn.example.com/-/jssmedia/a.png?w=1",
+		"~/media/ABC.ashx":                            "https://cdn.example.com/-/media/ABC.ashx",
+		"/assets/logo.svg":                            "https://cm.example.com/assets/logo.svg",
+		"https://other.example.com/img/a.png":         "https://other.example.com/img/a.png",
+	}
+
+	for src, expected := range tests {
+		if url := api.GetMediaURL(src, nil); url != expected {
+			t.Errorf("GetMediaURL(%q): expected %s, got %s", src, expected, url)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+// Package metrics exposes SDK internals as Prometheus metrics.
+//
+// The package implements the counters and histograms the SDK needs and the
+// Prometheus text exposition format, so no Prometheus client library is required.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the content type of the Prometheus text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are the default histogram buckets, in seconds
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// collector is a metric family that writes itself in the text format
+type collector interface {
+	name() string
+	write(w *bufio.Writer)
+}
+
+// Registry holds metric families and writes them in the Prometheus text format
+type Registry struct {
+	mu         sync.RWMutex
+	collectors []collector
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// register adds a collector. It panics on duplicate names, which are programming errors.
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.collectors {
+		if existing.name() == c.name() {
+			panic(fmt.Sprintf("metrics: duplicate metric %s", c.name()))
+		}
+	}
+	r.collectors = append(r.collectors, c)
+}
+
+// WriteTo writes all metrics in the Prometheus text exposition format
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.RLock()
+	collectors := slices.Clone(r.collectors)
+	r.mu.RUnlock()
+
+	counter := &countingWriter{w: w}
+	buf := bufio.NewWriter(counter)
+	for _, c := range collectors {
+		c.write(buf)
+	}
+	err := buf.Flush()
+	return counter.n, err
+}
+
+// ServeHTTP serves the metrics
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	r.WriteTo(w)
+}
+
+// NewCounter creates and registers a counter with the given label names
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{family: newFamily(name, help, labels)}
+	r.register(c)
+	return c
+}
+
+// NewHistogram creates and registers a histogram with the given buckets
+// (default: DefaultBuckets) and label names
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+
+	h := &Histogram{family: newFamily(name, help, labels), buckets: buckets}
+	r.register(h)
+	return h
+}
+
+// family contains the name, help and series of a metric family
+type family struct {
+	metricName string
+	help       string
+	labels     []string
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+// series is a labelled time series of a family
+type series struct {
+	labelValues []string
+	value       float64
+	counts      []uint64
+	count       uint64
+}
+
+// newFamily creates a metric family
+func newFamily(name, help string, labels []string) family {
+	return family{metricName: name, help: help, labels: labels, series: map[string]*series{}}
+}
+
+// name returns the metric name
+func (f *family) name() string {
+	return f.metricName
+}
+
+// get returns the series for the label values, creating it; f.mu must be held.
+// Missing label values are empty and extra values are ignored.
+func (f *family) get(labelValues []string) *series {
+	values := f.values(labelValues)
+	key := strings.Join(values, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labelValues: values}
+		f.series[key] = s
+	}
+	return s
+}
+
+// lookup returns the series for the label values without creating it, or an
+// empty series; f.mu must be held
+func (f *family) lookup(labelValues []string) *series {
+	if s, ok := f.series[strings.Join(f.values(labelValues), "\xff")]; ok {
+		return s
+	}
+	return &series{}
+}
+
+// values returns the label values padded or truncated to the family's labels
+func (f *family) values(labelValues []string) []string {
+	values := make([]string, len(f.labels))
+	copy(values, labelValues)
+	return values
+}
+
+// sorted returns the series sorted by label values; f.mu must be held
+func (f *family) sorted() []*series {
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	result := make([]*series, len(keys))
+	for i, key := range keys {
+		result[i] = f.series[key]
+	}
+	return result
+}
+
+// writeHeader writes the HELP and TYPE lines
+func (f *family) writeHeader(w *bufio.Writer, metricType string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", f.metricName, strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(f.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.metricName, metricType)
+}
+
+// writeSample writes a sample line
+func (f *family) writeSample(w *bufio.Writer, name string, labelValues []string, extraName, extraValue string, value float64) {
+	w.WriteString(name)
+
+	names := f.labels
+	if extraName != "" {
+		names = append(slices.Clone(names), extraName)
+		labelValues = append(slices.Clone(labelValues), extraValue)
+	}
+	if len(names) > 0 {
+		w.WriteByte('{')
+		for i, label := range names {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, `%s="%s"`, label, escapeLabelValue(labelValues[i]))
+		}
+		w.WriteByte('}')
+	}
+
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(value))
+	w.WriteByte('\n')
+}
+
+// Counter is a monotonically increasing counter with labels
+type Counter struct {
+	family
+}
+
+// Inc increments the counter for the label values
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds a non-negative value to the counter for the label values
+func (c *Counter) Add(value float64, labelValues ...string) {
+	if value < 0 {
+		return
+	}
+	c.mu.Lock()
+	c.get(labelValues).value += value
+	c.mu.Unlock()
+}
+
+// Value returns the counter value for the label values
+func (c *Counter) Value(labelValues ...string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lookup(labelValues).value
+}
+
+// write writes the counter in the text format
+func (c *Counter) write(w *bufio.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.writeHeader(w, "counter")
+	for _, s := range c.sorted() {
+		c.writeSample(w, c.metricName, s.labelValues, "", "", s.value)
+	}
+}
+
+// Histogram counts observations in buckets, with labels
+type Histogram struct {
+	family
+	buckets []float64
+}
+
+// Observe adds an observation for the label values
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := h.get(labelValues)
+	if s.counts == nil {
+		s.counts = make([]uint64, len(h.buckets))
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			s.counts[i]++
+		}
+	}
+	s.count++
+	s.value += value
+}
+
+// Count returns the number of observations for the label values
+func (h *Histogram) Count(labelValues ...string) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.lookup(labelValues).count
+}
+
+// write writes the histogram in the text format
+func (h *Histogram) write(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.writeHeader(w, "histogram")
+	for _, s := range h.sorted() {
+		for i, bound := range h.buckets {
+			var count uint64
+			if s.counts != nil {
+				count = s.counts[i]
+			}
+			h.writeSample(w, h.metricName+"_bucket", s.labelValues, "le", formatFloat(bound), float64(count))
+		}
+		h.writeSample(w, h.metricName+"_bucket", s.labelValues, "le", "+Inf", float64(s.count))
+		h.writeSample(w, h.metricName+"_sum", s.labelValues, "", "", s.value)
+		h.writeSample(w, h.metricName+"_count", s.labelValues, "", "", float64(s.count))
+	}
+}
+
+// escapeLabelValue escapes a label value for the text format
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// formatFloat formats a sample value for the text format
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// countingWriter counts the bytes written
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write implements io.Writer
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_TextFormat(t *testing.T) {
+	registry := NewRegistry()
+	requests := registry.NewCounter("app_requests_total", "Requests by path.", "path", "code")
+	duration := registry.NewHistogram("app_duration_seconds", "Request duration.", []float64{0.5, 0.1}, "path")
+
+	requests.Inc("/b", "200")
+	requests.Add(2, "/a", "404")
+	requests.Add(-1, "/a", "404")
+	duration.Observe(0.05, "/a")
+	duration.Observe(0.3, "/a")
+	duration.Observe(2, "/a")
+
+	var buf bytes.Buffer
+	_, err := registry.WriteTo(&buf)
+	require.NoError(t, err)
+
+	expected := `# HELP app_requests_total Requests by path.
+# TYPE app_requests_total counter
+app_requests_total{path="/a",code="404"} 2
+app_requests_total{path="/b",code="200"} 1
+# HELP app_duration_seconds Request duration.
+# TYPE app_duration_seconds histogram
+app_duration_seconds_bucket{path="/a",le="0.1"} 1
+app_duration_seconds_bucket{path="/a",le="0.5"} 2
+app_duration_seconds_bucket{path="/a",le="+Inf"} 3
+app_duration_seconds_sum{path="/a"} 2.35
+app_duration_seconds_count{path="/a"} 3
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestRegistry_ReadingDoesNotCreateSeries(t *testing.T) {
+	registry := NewRegistry()
+	counter := registry.NewCounter("read_total", "Read.", "result")
+	histogram := registry.NewHistogram("read_seconds", "Read.", nil, "result")
+
+	assert.Equal(t, float64(0), counter.Value("hit"))
+	assert.Equal(t, uint64(0), histogram.Count("hit"))
+
+	var buf bytes.Buffer
+	registry.WriteTo(&buf)
+	assert.Equal(t, "# HELP read_total Read.\n# TYPE read_total counter\n# HELP read_seconds Read.\n# TYPE read_seconds histogram\n", buf.String())
+}
+
+func TestRegistry_EscapesLabelValues(t *testing.T) {
+	registry := NewRegistry()
+	counter := registry.NewCounter("escaped_total", "Escaping.", "value")
+	counter.Inc("a \"quoted\"\nvalue\\")
+
+	var buf bytes.Buffer
+	registry.WriteTo(&buf)
+	assert.Contains(t, buf.String(), `escaped_total{value="a \"quoted\"\nvalue\\"} 1`)
+}
+
+func TestRegistry_DuplicatePanics(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounter("dup_total", "First.")
+	assert.Panics(t, func() { registry.NewCounter("dup_total", "Second.") })
+}
+
+func TestRegistry_ServeHTTP(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounter("served_total", "Served.").Inc()
+
+	rec := httptest.NewRecorder()
+	registry.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+	assert.True(t, strings.HasSuffix(rec.Body.String(), "served_total 1\n"))
+}
+
+func TestRecordGraphQLRequest(t *testing.T) {
+	requests := GraphQLRequests.Value("TestQuery", "error")
+	retries := GraphQLRetries.Value("TestQuery")
+
+	RecordGraphQLRequest("TestQuery", 20*time.Millisecond, 3, assert.AnError)
+
+	assert.Equal(t, requests+1, GraphQLRequests.Value("TestQuery", "error"))
+	assert.Equal(t, retries+2, GraphQLRetries.Value("TestQuery"))
+	assert.Equal(t, uint64(1), GraphQLDuration.Count("TestQuery"))
+}
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+)
+
+// Default is the registry the SDK records into
+var Default = NewRegistry()
+
+// SDK metrics
+var (
+	// GraphQLRequests counts GraphQL requests by operation and outcome (success or error)
+	GraphQLRequests = Default.NewCounter("content_sdk_graphql_requests_total",
+		"GraphQL requests by operation and outcome.", "operation", "outcome")
+
+	// GraphQLRetries counts GraphQL request retries by operation
+	GraphQLRetries = Default.NewCounter("content_sdk_graphql_retries_total",
+		"GraphQL request retries by operation.", "operation")
+
+	// GraphQLDuration observes the duration of GraphQL requests, including retries
+	GraphQLDuration = Default.NewHistogram("content_sdk_graphql_request_duration_seconds",
+		"Duration of GraphQL requests, including retries.", nil, "operation")
+
+	// CacheLookups counts cache hits and misses by cache
+	CacheLookups = Default.NewCounter("content_sdk_cache_lookups_total",
+		"Cache lookups by cache and result (hit or miss).", "cache", "result")
+
+	// Redirects counts redirects applied by the redirects middleware by type
+	Redirects = Default.NewCounter("content_sdk_redirects_total",
+		"Redirects applied by type.", "type")
+
+	// SiteResolutions counts multisite resolutions by source (query, cookie, host or default)
+	SiteResolutions = Default.NewCounter("content_sdk_site_resolutions_total",
+		"Multisite resolutions by source.", "source")
+
+	// LocaleResolutions counts locale resolutions by source (path, query, cookie, domain, accept-language, custom or default)
+	LocaleResolutions = Default.NewCounter("content_sdk_locale_resolutions_total",
+		"Locale resolutions by source.", "source")
+
+	// ErrorResponses counts 404 and 500 responses by site and status code
+	ErrorResponses = Default.NewCounter("content_sdk_error_responses_total",
+		"Not found and server error responses by site and status code.", "site", "code")
+)
+
+// RecordGraphQLRequest records a GraphQL request and its retries
+func RecordGraphQLRequest(operation string, duration time.Duration, attempts int, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	GraphQLRequests.Inc(operation, outcome)
+	GraphQLDuration.Observe(duration.Seconds(), operation)
+	if attempts > 1 {
+		GraphQLRetries.Add(float64(attempts-1), operation)
+	}
+}
+
+// RecordCacheLookup records a cache hit or miss
+func RecordCacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	CacheLookups.Inc(cache, result)
+}
+
+// RecordErrorResponse records a 404 or 500 response for a site
+func RecordErrorResponse(site string, code int) {
+	ErrorResponses.Inc(site, strconv.Itoa(code))
+}
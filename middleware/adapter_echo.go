@@ -25,9 +25,21 @@ func (c *EchoContext) Request() *http.Request {
 	return c.Context.Request()
 }
 
-// Response returns the response writer
+// SetRequest replaces the HTTP request, implementing RequestSetter
+func (c *EchoContext) SetRequest(request *http.Request) {
+	c.Context.SetRequest(request)
+}
+
+// Response returns the response writer. Writes go through Echo's response, so
+// its status and committed state stay accurate.
 func (c *EchoContext) Response() http.ResponseWriter {
-	return c.Context.Response().Writer
+	return c.Context.Response()
+}
+
+// ResponseStatus returns the status code written to the response, implementing StatusReporter
+func (c *EchoContext) ResponseStatus() (int, bool) {
+	response := c.Context.Response()
+	return response.Status, response.Committed
 }
 
 // Path returns the request path
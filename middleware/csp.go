@@ -0,0 +1,203 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"slices"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/guitarrich/content-sdk-go/debug"
+)
+
+// NonceSource is a placeholder source replaced with the per-request nonce ('nonce-...')
+const NonceSource = "{nonce}"
+
+// CSPNonceKey is the context key for the per-request CSP nonce
+const CSPNonceKey = "cspNonce"
+
+// CSP builds a Content-Security-Policy header. Directives are written in the
+// order they were first added.
+type CSP struct {
+	directives []cspDirective
+	reportOnly bool
+}
+
+// cspDirective is a directive name and its sources
+type cspDirective struct {
+	name    string
+	sources []string
+}
+
+// NewCSP creates an empty policy
+func NewCSP() *CSP {
+	return &CSP{}
+}
+
+// StrictCSP creates a nonce-based strict policy: scripts need the request nonce,
+// plugins are blocked and the page can only be framed by its own origin
+func StrictCSP() *CSP {
+	return NewCSP().
+		Add("default-src", "'self'").
+		ScriptSrc(NonceSource, "'strict-dynamic'").
+		Add("style-src", "'self'", "'unsafe-inline'").
+		ImgSrc("'self'", "data:").
+		Add("object-src", "'none'").
+		Add("base-uri", "'self'").
+		FrameAncestors("'self'")
+}
+
+// Add appends sources to a directive, creating it if needed
+func (p *CSP) Add(directive string, sources ...string) *CSP {
+	directive = strings.ToLower(strings.TrimSpace(directive))
+	for i := range p.directives {
+		if p.directives[i].name == directive {
+			for _, source := range sources {
+				if !slices.Contains(p.directives[i].sources, source) {
+					p.directives[i].sources = append(p.directives[i].sources, source)
+				}
+			}
+			return p
+		}
+	}
+	p.directives = append(p.directives, cspDirective{name: directive})
+	return p.Add(directive, sources...)
+}
+
+// Set replaces the sources of a directive
+func (p *CSP) Set(directive string, sources ...string) *CSP {
+	p.Remove(directive)
+	return p.Add(directive, sources...)
+}
+
+// Remove deletes a directive
+func (p *CSP) Remove(directive string) *CSP {
+	directive = strings.ToLower(strings.TrimSpace(directive))
+	p.directives = slices.DeleteFunc(p.directives, func(d cspDirective) bool {
+		return d.name == directive
+	})
+	return p
+}
+
+// FrameAncestors sets the origins allowed to frame the page
+func (p *CSP) FrameAncestors(origins ...string) *CSP {
+	return p.Set("frame-ancestors", origins...)
+}
+
+// ScriptSrc adds script sources; use NonceSource for the per-request nonce
+func (p *CSP) ScriptSrc(sources ...string) *CSP {
+	return p.Add("script-src", sources...)
+}
+
+// ImgSrc adds image sources, such as media and CDN hosts
+func (p *CSP) ImgSrc(sources ...string) *CSP {
+	return p.Add("img-src", sources...)
+}
+
+// ReportURI sets the URI violations are reported to
+func (p *CSP) ReportURI(uri string) *CSP {
+	return p.Set("report-uri", uri)
+}
+
+// ReportOnly sends the policy as Content-Security-Policy-Report-Only,
+// so violations are reported but not enforced
+func (p *CSP) ReportOnly(enabled bool) *CSP {
+	p.reportOnly = enabled
+	return p
+}
+
+// Clone returns a copy of the policy
+func (p *CSP) Clone() *CSP {
+	clone := &CSP{reportOnly: p.reportOnly}
+	for _, directive := range p.directives {
+		clone.directives = append(clone.directives, cspDirective{
+			name:    directive.name,
+			sources: slices.Clone(directive.sources),
+		})
+	}
+	return clone
+}
+
+// HeaderName returns the header the policy is sent in
+func (p *CSP) HeaderName() string {
+	if p.reportOnly {
+		return "Content-Security-Policy-Report-Only"
+	}
+	return "Content-Security-Policy"
+}
+
+// String renders the policy with the given nonce. Without a nonce,
+// NonceSource placeholders are left out.
+func (p *CSP) String(nonce string) string {
+	parts := make([]string, 0, len(p.directives))
+	for _, directive := range p.directives {
+		var sources []string
+		for _, source := range directive.sources {
+			if source == NonceSource {
+				if nonce == "" {
+					continue
+				}
+				source = "'nonce-" + nonce + "'"
+			}
+			sources = append(sources, source)
+		}
+		if len(sources) == 0 {
+			parts = append(parts, directive.name)
+		} else {
+			parts = append(parts, directive.name+" "+strings.Join(sources, " "))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// CSPMiddleware sets a Content-Security-Policy header with a per-request nonce
+type CSPMiddleware struct {
+	policy *CSP
+}
+
+// NewCSPMiddleware creates a new CSP middleware.
+// The nonce is stored under CSPNonceKey and in the request context for templ
+// (templ.GetNonce), so SDK components add it to the scripts they render.
+func NewCSPMiddleware(policy *CSP) *CSPMiddleware {
+	if policy == nil {
+		policy = StrictCSP()
+	}
+	return &CSPMiddleware{
+		policy: policy,
+	}
+}
+
+// Handle processes the CSP middleware
+func (m *CSPMiddleware) Handle(ctx Context, next HandlerFunc) error {
+	nonce, err := generateNonce()
+	if err != nil {
+		return err
+	}
+
+	ctx.Set(CSPNonceKey, nonce)
+
+	request := ctx.Request()
+	setRequest(ctx, request.WithContext(templ.WithNonce(request.Context(), nonce)))
+
+	ctx.SetHeader(m.policy.HeaderName(), m.policy.String(nonce))
+	debug.Common("CSP nonce generated for %s", ctx.Path())
+
+	return next(ctx)
+}
+
+// CSPNonce returns the nonce of the current request, or ""
+func CSPNonce(ctx Context) string {
+	if nonce, ok := ctx.Get(CSPNonceKey).(string); ok {
+		return nonce
+	}
+	return templ.GetNonce(ctx.Request().Context())
+}
+
+// generateNonce returns a random base64 nonce
+func generateNonce() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSP_String(t *testing.T) {
+	policy := NewCSP().
+		Add("default-src", "'self'").
+		ScriptSrc(NonceSource, "'strict-dynamic'").
+		ImgSrc("'self'", "https://cdn.example.com").
+		ImgSrc("https://cdn.example.com").
+		Add("upgrade-insecure-requests").
+		FrameAncestors("https://pages.sitecorecloud.io")
+
+	assert.Equal(t,
+		"default-src 'self'; script-src 'nonce-abc' 'strict-dynamic'; img-src 'self' https://cdn.example.com; upgrade-insecure-requests; frame-ancestors https://pages.sitecorecloud.io",
+		policy.String("abc"))
+
+	// The nonce placeholder is dropped without a nonce
+	assert.Contains(t, policy.String(""), "script-src 'strict-dynamic';")
+	assert.Equal(t, "Content-Security-Policy", policy.HeaderName())
+
+	// Set replaces sources and Clone doesn't share them
+	clone := policy.Clone().FrameAncestors("'none'").ReportOnly(true)
+	assert.Contains(t, clone.String(""), "frame-ancestors 'none'")
+	assert.Contains(t, policy.String(""), "frame-ancestors https://pages.sitecorecloud.io")
+	assert.Equal(t, "Content-Security-Policy-Report-Only", clone.HeaderName())
+}
+
+func TestCSPMiddleware_Nonce(t *testing.T) {
+	mw := NewCSPMiddleware(StrictCSP().ImgSrc("https://cdn.example.com").ReportURI("/csp-report"))
+
+	ctx := NewMockContext("GET", "/")
+	var nonce, templNonce string
+	err := mw.Handle(ctx, func(ctx Context) error {
+		nonce = CSPNonce(ctx)
+		templNonce = templ.GetNonce(ctx.Request().Context())
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, nonce)
+	assert.Equal(t, nonce, templNonce)
+
+	header := ctx.response.Header().Get("Content-Security-Policy")
+	assert.Contains(t, header, "script-src 'nonce-"+nonce+"' 'strict-dynamic'")
+	assert.Contains(t, header, "img-src 'self' data: https://cdn.example.com")
+	assert.True(t, strings.HasSuffix(header, "report-uri /csp-report"))
+
+	// Every request gets a new nonce
+	other := NewMockContext("GET", "/")
+	mw.Handle(other, func(ctx Context) error { return nil })
+	assert.NotEqual(t, nonce, CSPNonce(other))
+}
+
+// fixedRequestContext is a context that can't replace its request
+type fixedRequestContext struct {
+	Context
+}
+
+func TestCSPMiddleware_FixedRequest(t *testing.T) {
+	ctx := fixedRequestContext{NewMockContext("GET", "/")}
+	_, ok := Context(ctx).(RequestSetter)
+	assert.False(t, ok)
+
+	// The nonce is still available through the context, including wrapped in other middleware
+	var nonce, templNonce string
+	err := Chain(NewErrorResponseMetrics(), NewCSPMiddleware(nil)).Handle(ctx, func(ctx Context) error {
+		nonce = CSPNonce(ctx)
+		templNonce = templ.GetNonce(ctx.Request().Context())
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, nonce)
+	assert.Empty(t, templNonce)
+
+	// Wrapping contexts forward the replaced request
+	mock := NewMockContext("GET", "/")
+	Chain(NewErrorResponseMetrics(), NewCSPMiddleware(nil)).Handle(mock, func(ctx Context) error {
+		templNonce = templ.GetNonce(ctx.Request().Context())
+		return nil
+	})
+	assert.Equal(t, CSPNonce(mock), templNonce)
+}
+
+func TestCSPMiddleware_EchoRequest(t *testing.T) {
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+	// Echo handlers after the middleware see the replaced request
+	var templNonce string
+	handler := AdaptMiddlewareToEcho(NewCSPMiddleware(nil))(func(c echo.Context) error {
+		templNonce = templ.GetNonce(c.Request().Context())
+		return nil
+	})
+
+	assert.NoError(t, handler(c))
+	assert.NotEmpty(t, templNonce)
+	assert.Contains(t, rec.Header().Get("Content-Security-Policy"), "'nonce-"+templNonce+"'")
+}
+
+func TestEditingSecurity_Agnostic(t *testing.T) {
+	csp := NewCSPMiddleware(StrictCSP())
+	security := NewEditingSecurity(EditingSecurityConfig{
+		Secret:         "test-secret",
+		AllowedOrigins: []string{"https://pages.sitecorecloud.io"},
+		Policy:         StrictCSP(),
+	})
+
+	ctx := NewMockContext("GET", "/api/editing/render")
+	ctx.request.Header.Set("X-Editing-Secret", "test-secret")
+
+	called := false
+	err := Chain(csp, security).Handle(ctx, func(ctx Context) error {
+		called = true
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, called)
+
+	header := ctx.response.Header().Get("Content-Security-Policy")
+	assert.Contains(t, header, "frame-ancestors https://pages.sitecorecloud.io")
+	assert.Contains(t, header, "'nonce-"+CSPNonce(ctx)+"'")
+
+	// Invalid secrets are rejected without calling the handler
+	ctx = NewMockContext("GET", "/api/editing/render?secret=wrong")
+	called = false
+	security.Handle(ctx, func(ctx Context) error {
+		called = true
+		return nil
+	})
+	assert.False(t, called)
+	assert.Equal(t, http.StatusUnauthorized, ctx.response.Code)
+}
@@ -2,72 +2,260 @@ package middleware
 
 import (
 	"context"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
 
+	"github.com/guitarrich/content-sdk-go/debug"
 	"github.com/guitarrich/content-sdk-go/models"
 	"github.com/labstack/echo/v4"
 )
 
-// EditingModeMiddleware detects and stores editing mode information
-// It checks for Sitecore query parameters (sc_mode, sc_lang, sc_itemid)
-// and stores them in the request context for use by handlers and renderers
-func EditingModeMiddleware() echo.MiddlewareFunc {
-	return func(next echo.HandlerFunc) echo.HandlerFunc {
-		return func(c echo.Context) error {
-			// Detect editing mode from query parameters
-			scMode := c.QueryParam("sc_mode")
-			scLang := c.QueryParam("sc_lang")
-			scItemID := c.QueryParam("sc_itemid")
-
-			// Determine if we're in editing mode
-			isEditingMode := scMode == "edit" || scMode == "preview"
-			isPreview := scMode == "preview"
-			isEdit := scMode == "edit"
-
-			// Determine the page mode
-			var mode models.PageMode
-			switch scMode {
-			case "edit":
-				mode = models.PageModeEdit
-			case "preview":
-				mode = models.PageModePreview
-			default:
-				mode = models.PageModeNormal
+// editingContextKey is the request context key for the EditingContext
+type editingContextKey struct{}
+
+// editingParams are the Sitecore Pages and Preview query parameters kept in the EditingContext
+var editingParams = []string{"sc_mode", "mode", "sc_itemid", "sc_lang", "sc_site", "sc_version", "sc_layoutKind", "sc_variant", "route"}
+
+// EditingContextConfig contains configuration for editing mode detection
+type EditingContextConfig struct {
+	// PreviewCookieName is the cookie that keeps preview mode across
+	// navigation inside the Pages iframe (default: "sc_preview")
+	PreviewCookieName string
+
+	// PersistPreview stores the editing parameters in the preview cookie, so
+	// links followed in preview mode stay in preview mode. sc_mode=normal clears it.
+	PersistPreview bool
+
+	// CookieSecure sets the Secure attribute of the preview cookie
+	CookieSecure bool
+
+	// PagesOrigins are the origins of the Sitecore Pages editor
+	// (default: https://pages.sitecorecloud.io)
+	PagesOrigins []string
+}
+
+// EditingContextResolver detects editing and preview mode. It understands the
+// Sitecore Pages render parameters (mode, sc_itemid, sc_lang, sc_site, ...),
+// the sc_mode parameter, the preview cookie and the Pages iframe headers, and
+// stores the result in both the middleware context and the request context.
+//
+// The resolver doesn't authenticate requests; protect editing routes with EditingSecurity.
+type EditingContextResolver struct {
+	config EditingContextConfig
+}
+
+// NewEditingContextResolver creates a new editing context resolver
+func NewEditingContextResolver(config EditingContextConfig) *EditingContextResolver {
+	if config.PreviewCookieName == "" {
+		config.PreviewCookieName = "sc_preview"
+	}
+	if len(config.PagesOrigins) == 0 {
+		config.PagesOrigins = []string{"https://pages.sitecorecloud.io"}
+	}
+	return &EditingContextResolver{
+		config: config,
+	}
+}
+
+// Handle processes the editing context middleware
+func (r *EditingContextResolver) Handle(ctx Context, next HandlerFunc) error {
+	editingContext := r.Resolve(ctx.Request())
+	setEditingContext(ctx, editingContext)
+
+	if r.config.PersistPreview {
+		r.persist(ctx, editingContext)
+	}
+
+	return next(ctx)
+}
+
+// Resolve detects the editing context of a request. Query parameters take
+// precedence over the preview cookie. A request from the Pages iframe with an
+// item ID but no mode is in edit mode.
+func (r *EditingContextResolver) Resolve(req *http.Request) *models.EditingContext {
+	editingContext := &models.EditingContext{Mode: models.PageModeNormal}
+	editingContext.InPagesFrame = r.inPagesFrame(req)
+
+	params := req.URL.Query()
+	source := "query"
+	if !hasEditingMode(params) && params.Get("sc_mode") != string(models.PageModeNormal) {
+		if cookie, err := req.Cookie(r.config.PreviewCookieName); err == nil {
+			if values, err := url.ParseQuery(cookie.Value); err == nil && hasEditingMode(values) {
+				params, source = values, "cookie"
 			}
+		}
+	}
+
+	applyEditingParams(editingContext, params)
+
+	if editingContext.Mode == models.PageModeNormal && editingContext.InPagesFrame && editingContext.ItemID != "" {
+		editingContext.Mode = models.PageModeEdit
+		editingContext.PreviewMode = models.PreviewModeEdit
+		source = "header"
+	}
+
+	switch editingContext.Mode {
+	case models.PageModeEdit, models.PageModeDesignLibrary:
+		editingContext.IsEditing = true
+	case models.PageModePreview:
+		editingContext.IsPreview = true
+	}
+	if editingContext.Mode != models.PageModeNormal {
+		editingContext.Source = source
+		debug.Editing("editing context resolved: mode=%s, previewMode=%s, source=%s, itemId=%s",
+			editingContext.Mode, editingContext.PreviewMode, source, editingContext.ItemID)
+	}
 
-			// Store in request context
-			ctx := c.Request().Context()
-			ctx = context.WithValue(ctx, "sc_mode", scMode)
-			ctx = context.WithValue(ctx, "sc_lang", scLang)
-			ctx = context.WithValue(ctx, "sc_itemid", scItemID)
-			ctx = context.WithValue(ctx, "isEditingMode", isEditingMode)
-			ctx = context.WithValue(ctx, "isPreview", isPreview)
-			ctx = context.WithValue(ctx, "isEdit", isEdit)
-			ctx = context.WithValue(ctx, "pageMode", mode)
-
-			// Create EditingContext for easy access
-			editingContext := &models.EditingContext{
-				IsEditing: isEdit,
-				IsPreview: isPreview,
-				Mode:      mode,
-				QueryParams: map[string]string{
-					"sc_mode":   scMode,
-					"sc_lang":   scLang,
-					"sc_itemid": scItemID,
-				},
+	return editingContext
+}
+
+// persist keeps the preview cookie in sync with the editing parameters of the request
+func (r *EditingContextResolver) persist(ctx Context, editingContext *models.EditingContext) {
+	query := ctx.Request().URL.Query()
+	switch {
+	case editingContext.Source == "query":
+		values := url.Values{}
+		for _, name := range editingParams {
+			if value := query.Get(name); value != "" {
+				values.Set(name, value)
 			}
-			ctx = context.WithValue(ctx, "editingContext", editingContext)
+		}
+		ctx.SetCookie(r.previewCookie(values.Encode(), 0))
+	case query.Get("sc_mode") == string(models.PageModeNormal):
+		ctx.SetCookie(r.previewCookie("", -1))
+	}
+}
 
-			// Update request with new context
-			c.SetRequest(c.Request().WithContext(ctx))
+// previewCookie creates the preview cookie. Secure cookies use SameSite=None
+// so they are sent inside the cross-site Pages iframe.
+func (r *EditingContextResolver) previewCookie(value string, maxAge int) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     r.config.PreviewCookieName,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   r.config.CookieSecure,
+		SameSite: http.SameSiteLaxMode,
+	}
+	if r.config.CookieSecure {
+		cookie.SameSite = http.SameSiteNoneMode
+	}
+	return cookie
+}
+
+// inPagesFrame checks if the request is a frame navigation from the Pages editor
+func (r *EditingContextResolver) inPagesFrame(req *http.Request) bool {
+	if req.Header.Get("Sec-Fetch-Dest") != "iframe" {
+		return false
+	}
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		if referer, err := url.Parse(req.Header.Get("Referer")); err == nil && referer.Host != "" {
+			origin = referer.Scheme + "://" + referer.Host
+		}
+	}
+	return origin != "" && slices.Contains(r.config.PagesOrigins, origin)
+}
+
+// hasEditingMode checks if the parameters select an editing or preview mode
+func hasEditingMode(params url.Values) bool {
+	return parseMode(params.Get("mode")) != "" || parseMode(params.Get("sc_mode")) != ""
+}
+
+// parseMode maps a mode parameter to a page mode, or "" for normal and unknown modes
+func parseMode(mode string) models.PageMode {
+	switch strings.ToLower(mode) {
+	case "edit", "metadata":
+		return models.PageModeEdit
+	case "preview":
+		return models.PageModePreview
+	case "library", "designlibrary":
+		return models.PageModeDesignLibrary
+	}
+	return ""
+}
+
+// applyEditingParams reads the Sitecore editing parameters into the EditingContext.
+// The Pages "mode" parameter takes precedence over "sc_mode".
+func applyEditingParams(editingContext *models.EditingContext, params url.Values) {
+	editingContext.QueryParams = map[string]string{}
+	for _, name := range editingParams {
+		if value := params.Get(name); value != "" {
+			editingContext.QueryParams[name] = value
+		}
+	}
+
+	editingContext.ItemID = params.Get("sc_itemid")
+	editingContext.Language = params.Get("sc_lang")
+	editingContext.Site = params.Get("sc_site")
+	editingContext.Version = params.Get("sc_version")
+	editingContext.Route = params.Get("route")
+	if variants := params.Get("sc_variant"); variants != "" {
+		editingContext.VariantIds = strings.Split(variants, ",")
+	}
+
+	editingContext.LayoutKind = models.LayoutKindFinal
+	if strings.EqualFold(params.Get("sc_layoutKind"), string(models.LayoutKindShared)) {
+		editingContext.LayoutKind = models.LayoutKindShared
+	}
 
-			return next(c)
+	mode := params.Get("mode")
+	if parseMode(mode) == "" {
+		mode = params.Get("sc_mode")
+	}
+	if pageMode := parseMode(mode); pageMode != "" {
+		editingContext.Mode = pageMode
+		switch strings.ToLower(mode) {
+		case "metadata":
+			editingContext.PreviewMode = models.PreviewModeMetadata
+		case "preview":
+			editingContext.PreviewMode = models.PreviewModePreview
+		default:
+			editingContext.PreviewMode = models.PreviewModeEdit
 		}
 	}
 }
 
+// setEditingContext stores the EditingContext in the middleware and request contexts
+func setEditingContext(ctx Context, editingContext *models.EditingContext) {
+	ctx.Set(EditingContextKey, editingContext)
+
+	request := ctx.Request()
+	setRequest(ctx, request.WithContext(WithEditingContext(request.Context(), editingContext)))
+}
+
+// WithEditingContext returns a copy of ctx carrying the EditingContext
+func WithEditingContext(ctx context.Context, editingContext *models.EditingContext) context.Context {
+	return context.WithValue(ctx, editingContextKey{}, editingContext)
+}
+
+// EditingModeMiddleware detects and stores editing mode information
+// It is the Echo adapter of an EditingContextResolver with the default configuration
+func EditingModeMiddleware() echo.MiddlewareFunc {
+	return AdaptMiddlewareToEcho(NewEditingContextResolver(EditingContextConfig{}))
+}
+
+// EditingContextFrom returns the EditingContext of a request. It is resolved
+// with the default configuration when no resolver has run.
+func EditingContextFrom(ctx Context) *models.EditingContext {
+	if editingContext, ok := ctx.Get(EditingContextKey).(*models.EditingContext); ok {
+		return editingContext
+	}
+	if editingContext, ok := ctx.Request().Context().Value(editingContextKey{}).(*models.EditingContext); ok {
+		return editingContext
+	}
+
+	editingContext := NewEditingContextResolver(EditingContextConfig{}).Resolve(ctx.Request())
+	setEditingContext(ctx, editingContext)
+	return editingContext
+}
+
 // GetEditingContext retrieves the EditingContext from the request context
 func GetEditingContext(ctx context.Context) *models.EditingContext {
-	if editingCtx, ok := ctx.Value("editingContext").(*models.EditingContext); ok {
+	if editingCtx, ok := ctx.Value(editingContextKey{}).(*models.EditingContext); ok {
 		return editingCtx
 	}
 	return &models.EditingContext{
@@ -79,24 +267,16 @@ func GetEditingContext(ctx context.Context) *models.EditingContext {
 
 // IsEditingMode is a helper to check if we're in any editing mode
 func IsEditingMode(ctx context.Context) bool {
-	if isEditing, ok := ctx.Value("isEditingMode").(bool); ok {
-		return isEditing
-	}
-	return false
+	editingCtx := GetEditingContext(ctx)
+	return editingCtx.IsEditing || editingCtx.IsPreview
 }
 
 // IsPreviewMode is a helper to check if we're in preview mode
 func IsPreviewMode(ctx context.Context) bool {
-	if isPreview, ok := ctx.Value("isPreview").(bool); ok {
-		return isPreview
-	}
-	return false
+	return GetEditingContext(ctx).IsPreview
 }
 
 // IsEditMode is a helper to check if we're in edit mode
 func IsEditMode(ctx context.Context) bool {
-	if isEdit, ok := ctx.Value("isEdit").(bool); ok {
-		return isEdit
-	}
-	return false
+	return GetEditingContext(ctx).IsEditing
 }
@@ -1,10 +1,19 @@
 package middleware
 
 import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"math"
+	"net"
 	"net/http"
 	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/guitarrich/content-sdk-go/config"
 	"github.com/guitarrich/content-sdk-go/debug"
 	"github.com/labstack/echo/v4"
 )
@@ -14,113 +23,435 @@ type EditingSecurityConfig struct {
 	// Secret is the editing secret for validation
 	Secret string
 
+	// Secrets are additional accepted secrets, so a new secret can be rolled
+	// out before the old one is retired
+	Secrets []string
+
+	// SecretList is an optional reloadable secret list. When set, it takes
+	// precedence over Secret and Secrets and is consulted on every request.
+	SecretList *SecretList
+
+	// SecretHeader is the request header the secret can be sent in instead of
+	// the secret query parameter, keeping it out of access logs (default: X-Editing-Secret)
+	SecretHeader string
+
+	// MaxFailedAttempts is the number of failed attempts allowed per client IP
+	// within FailureWindow before requests are rejected with 429 (default: 10)
+	MaxFailedAttempts int
+
+	// FailureWindow is the period failed attempts are counted over (default: 1 minute)
+	FailureWindow time.Duration
+
+	// TrustedProxies are the IPs and CIDR ranges of reverse proxies whose
+	// X-Forwarded-For and X-Real-IP headers are honoured. Without them, the
+	// client IP is the remote address, so clients can't spoof their IP.
+	TrustedProxies []string
+
+	// OnAuthFailure is called for every failed authentication attempt (optional)
+	OnAuthFailure func(event EditingAuthFailure)
+
+	// Policy is the Content-Security-Policy sent on editing requests, with
+	// frame-ancestors replaced by the allowed origins (default: frame-ancestors only)
+	Policy *CSP
+
 	// AllowedOrigins is the list of origins allowed to access editing APIs
 	AllowedOrigins []string
 
+	// Origins is an optional reloadable origin list. When set, it takes
+	// precedence over AllowedOrigins and is consulted on every request.
+	Origins *OriginList
+
 	// SkipSecretValidation skips secret validation (for testing)
 	SkipSecretValidation bool
 }
 
+// EditingAuthFailure describes a failed editing authentication attempt
+type EditingAuthFailure struct {
+	// IP is the client IP address
+	IP string
+
+	// Method and Path identify the request
+	Method string
+	Path   string
+
+	// Reason is "missing_secret", "invalid_secret" or "rate_limited"
+	Reason string
+
+	// Time is when the attempt was made
+	Time time.Time
+}
+
+// EditingSecurity validates the editing secret and enforces CORS and framing for editing endpoints
+type EditingSecurity struct {
+	config  EditingSecurityConfig
+	secrets *SecretList
+	limiter *failureLimiter
+	proxies []*net.IPNet
+}
+
+// NewEditingSecurity creates a new framework-agnostic editing security middleware
+func NewEditingSecurity(config EditingSecurityConfig) *EditingSecurity {
+	if config.SecretHeader == "" {
+		config.SecretHeader = "X-Editing-Secret"
+	}
+	if config.MaxFailedAttempts == 0 {
+		config.MaxFailedAttempts = 10
+	}
+	if config.FailureWindow == 0 {
+		config.FailureWindow = time.Minute
+	}
+	secrets := config.SecretList
+	if secrets == nil {
+		secrets = NewSecretList(append([]string{config.Secret}, config.Secrets...)...)
+	}
+
+	return &EditingSecurity{
+		config:  config,
+		secrets: secrets,
+		limiter: newFailureLimiter(config.MaxFailedAttempts, config.FailureWindow),
+		proxies: parseTrustedProxies(config.TrustedProxies),
+	}
+}
+
 // EditingSecurityMiddleware validates the editing secret and enforces CORS for editing endpoints
 func EditingSecurityMiddleware(config EditingSecurityConfig) echo.MiddlewareFunc {
-	return func(next echo.HandlerFunc) echo.HandlerFunc {
-		return func(c echo.Context) error {
-			// Get origin from request
-			origin := c.Request().Header.Get("Origin")
-
-			// Handle CORS preflight requests
-			if c.Request().Method == http.MethodOptions {
-				return handleCORSPreflight(c, origin, config.AllowedOrigins)
+	return AdaptMiddlewareToEcho(NewEditingSecurity(config))
+}
+
+// Handle processes the editing security middleware
+func (m *EditingSecurity) Handle(ctx Context, next HandlerFunc) error {
+	// Get origin from request
+	origin := ctx.Header("Origin")
+
+	allowedOrigins := m.config.AllowedOrigins
+	if m.config.Origins != nil {
+		allowedOrigins = m.config.Origins.Get()
+	}
+
+	// Handle CORS preflight requests
+	if ctx.Request().Method == http.MethodOptions {
+		return handleCORSPreflight(ctx, origin, allowedOrigins)
+	}
+
+	// Validate editing secret from header or query parameter
+	if !m.config.SkipSecretValidation {
+		if rejected, err := m.validateSecret(ctx); rejected {
+			return err
+		}
+	}
+
+	// Set CORS headers for actual requests
+	setCORSHeaders(ctx, origin, allowedOrigins)
+
+	// Set iframe/embedding headers for allowed origins
+	setIframeHeaders(ctx, allowedOrigins, m.config.Policy)
+
+	return next(ctx)
+}
+
+// validateSecret checks the editing secret and writes the error response when it is rejected
+func (m *EditingSecurity) validateSecret(ctx Context) (bool, error) {
+	ip := m.clientIP(ctx)
+	fail := func(reason string) {
+		if m.config.OnAuthFailure != nil {
+			m.config.OnAuthFailure(EditingAuthFailure{
+				IP:     ip,
+				Method: ctx.Request().Method,
+				Path:   ctx.Request().URL.Path,
+				Reason: reason,
+				Time:   time.Now(),
+			})
+		}
+	}
+
+	if retryAfter := m.limiter.blocked(ip); retryAfter > 0 {
+		debug.Editing("too many failed editing attempts from %s", ip)
+		fail("rate_limited")
+		ctx.SetHeader("Retry-After", fmt.Sprintf("%d", int(math.Ceil(retryAfter.Seconds()))))
+		return true, ctx.JSON(http.StatusTooManyRequests, map[string]string{
+			"error": "Too many failed attempts",
+		})
+	}
+
+	secret := ctx.Header(m.config.SecretHeader)
+	if secret == "" {
+		secret = ctx.Request().URL.Query().Get("secret")
+	}
+	if secret == "" {
+		debug.Editing("editing secret missing in request")
+		m.limiter.fail(ip)
+		fail("missing_secret")
+		return true, ctx.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized: editing secret is required",
+		})
+	}
+
+	if !m.secrets.Contains(secret) {
+		debug.Editing("invalid editing secret provided")
+		m.limiter.fail(ip)
+		fail("invalid_secret")
+		return true, ctx.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized: invalid editing secret",
+		})
+	}
+
+	debug.Editing("editing secret validated successfully")
+	return false, nil
+}
+
+// clientIP returns the client IP. Forwarding headers are only honoured when
+// the request comes from a trusted proxy: X-Forwarded-For is read from the
+// right, skipping trusted proxies, then X-Real-IP is used.
+func (m *EditingSecurity) clientIP(ctx Context) string {
+	remote := ctx.Request().RemoteAddr
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		remote = host
+	}
+	if !m.trusted(remote) {
+		return remote
+	}
+
+	if forwarded := ctx.Header("X-Forwarded-For"); forwarded != "" {
+		hops := strings.Split(forwarded, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if hop != "" && !m.trusted(hop) {
+				return hop
 			}
+		}
+	}
+	if realIP := strings.TrimSpace(ctx.Header("X-Real-IP")); realIP != "" {
+		return realIP
+	}
+	return remote
+}
 
-			// Validate editing secret from query parameter
-			if !config.SkipSecretValidation {
-				secret := c.QueryParam("secret")
-				if secret == "" {
-					debug.Editing("editing secret missing in request")
-					return c.JSON(http.StatusUnauthorized, map[string]string{
-						"error": "Unauthorized: editing secret is required",
-					})
-				}
+// trusted reports whether ip is a trusted proxy
+func (m *EditingSecurity) trusted(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, proxy := range m.proxies {
+		if proxy.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
 
-				if secret != config.Secret {
-					debug.Editing("invalid editing secret provided")
-					return c.JSON(http.StatusUnauthorized, map[string]string{
-						"error": "Unauthorized: invalid editing secret",
-					})
+// parseTrustedProxies parses IPs and CIDR ranges; invalid entries are ignored
+func parseTrustedProxies(proxies []string) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil {
+				bits := 8 * len(ip.To16())
+				if ip.To4() != nil {
+					ip, bits = ip.To4(), 32
 				}
-
-				debug.Editing("editing secret validated successfully")
+				networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+				continue
 			}
+		}
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			networks = append(networks, network)
+		} else {
+			debug.Editing("ignoring invalid trusted proxy %q", proxy)
+		}
+	}
+	return networks
+}
 
-			// Set CORS headers for actual requests
-			setCORSHeaders(c, origin, config.AllowedOrigins)
+// SecretList is a list of accepted editing secrets that can be replaced at runtime
+type SecretList struct {
+	digests atomic.Pointer[[][sha256.Size]byte]
+}
 
-			// Set iframe/embedding headers for allowed origins
-			setIframeHeaders(c, config.AllowedOrigins)
+// NewSecretList creates a new secret list. Empty secrets are ignored.
+func NewSecretList(secrets ...string) *SecretList {
+	l := &SecretList{}
+	l.Set(secrets...)
+	return l
+}
 
-			return next(c)
+// Set atomically replaces the secrets
+func (l *SecretList) Set(secrets ...string) {
+	digests := make([][sha256.Size]byte, 0, len(secrets))
+	for _, secret := range secrets {
+		if secret != "" {
+			debug.RegisterSecret(secret)
+			digests = append(digests, sha256.Sum256([]byte(secret)))
 		}
 	}
+	l.digests.Store(&digests)
+}
+
+// Contains reports whether secret is one of the accepted secrets.
+// Secrets are compared as SHA-256 digests in constant time, and every secret
+// is compared, so timing reveals neither the match nor the secret lengths.
+func (l *SecretList) Contains(secret string) bool {
+	digest := sha256.Sum256([]byte(secret))
+	match := 0
+	for _, candidate := range *l.digests.Load() {
+		match |= subtle.ConstantTimeCompare(digest[:], candidate[:])
+	}
+	return match == 1
+}
+
+// OnConfigChange implements config.Subscriber and applies the new editing secrets
+func (l *SecretList) OnConfigChange(old, new *config.Config) {
+	l.Set(append([]string{new.Editing.Secret}, new.Editing.Secrets...)...)
+	debug.Editing("editing secrets reloaded (%d additional)", len(new.Editing.Secrets))
+}
+
+// failureLimiter counts failed attempts per client IP in fixed windows
+type failureLimiter struct {
+	mu        sync.Mutex
+	max       int
+	window    time.Duration
+	attempts  map[string]*failureWindow
+	lastSweep time.Time
+}
+
+// failureWindow is the failure count of one client IP
+type failureWindow struct {
+	start time.Time
+	count int
+}
+
+// newFailureLimiter creates a new failure limiter
+func newFailureLimiter(max int, window time.Duration) *failureLimiter {
+	return &failureLimiter{
+		max:      max,
+		window:   window,
+		attempts: make(map[string]*failureWindow),
+	}
+}
+
+// blocked returns how long an IP must wait before trying again, or 0
+func (l *failureLimiter) blocked(ip string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	attempts, ok := l.attempts[ip]
+	if !ok || attempts.count < l.max {
+		return 0
+	}
+	remaining := l.window - time.Since(attempts.start)
+	if remaining <= 0 {
+		delete(l.attempts, ip)
+		return 0
+	}
+	return remaining
+}
+
+// fail records a failed attempt for an IP
+func (l *failureLimiter) fail(ip string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	// Drop expired windows so the map doesn't grow without bound
+	if now.Sub(l.lastSweep) > l.window {
+		for key, attempts := range l.attempts {
+			if now.Sub(attempts.start) > l.window {
+				delete(l.attempts, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	attempts, ok := l.attempts[ip]
+	if !ok || now.Sub(attempts.start) > l.window {
+		attempts = &failureWindow{start: now}
+		l.attempts[ip] = attempts
+	}
+	attempts.count++
+}
+
+// OriginList is a list of allowed origins that can be replaced at runtime
+type OriginList struct {
+	origins atomic.Pointer[[]string]
+}
+
+// NewOriginList creates a new origin list
+func NewOriginList(origins []string) *OriginList {
+	l := &OriginList{}
+	l.Set(origins)
+	return l
+}
+
+// Get returns the current origins
+func (l *OriginList) Get() []string {
+	return *l.origins.Load()
+}
+
+// Set atomically replaces the origins
+func (l *OriginList) Set(origins []string) {
+	origins = slices.Clone(origins)
+	l.origins.Store(&origins)
+}
+
+// OnConfigChange implements config.Subscriber and applies the new editing origins
+func (l *OriginList) OnConfigChange(old, new *config.Config) {
+	l.Set(new.Editing.AllowedOrigins)
+	debug.Editing("allowed origins reloaded: %v", new.Editing.AllowedOrigins)
 }
 
 // handleCORSPreflight handles OPTIONS preflight requests
-func handleCORSPreflight(c echo.Context, origin string, allowedOrigins []string) error {
+func handleCORSPreflight(ctx Context, origin string, allowedOrigins []string) error {
 	// Check if origin is allowed
 	if origin != "" && isOriginAllowed(origin, allowedOrigins) {
-		c.Response().Header().Set("Access-Control-Allow-Origin", origin)
-		c.Response().Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Response().Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Response().Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With")
-		c.Response().Header().Set("Access-Control-Max-Age", "3600")
+		ctx.SetHeader("Access-Control-Allow-Origin", origin)
+		ctx.SetHeader("Access-Control-Allow-Credentials", "true")
+		ctx.SetHeader("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		ctx.SetHeader("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, X-Editing-Secret")
+		ctx.SetHeader("Access-Control-Max-Age", "3600")
 
 		debug.Editing("CORS preflight request handled for origin: %s", origin)
-		return c.NoContent(http.StatusNoContent)
+		return ctx.NoContent(http.StatusNoContent)
 	}
 
 	debug.Editing("CORS preflight request rejected for origin: %s", origin)
-	return c.NoContent(http.StatusForbidden)
+	return ctx.NoContent(http.StatusForbidden)
 }
 
 // setCORSHeaders sets CORS headers for actual requests
-func setCORSHeaders(c echo.Context, origin string, allowedOrigins []string) {
+func setCORSHeaders(ctx Context, origin string, allowedOrigins []string) {
 	if origin != "" && isOriginAllowed(origin, allowedOrigins) {
-		c.Response().Header().Set("Access-Control-Allow-Origin", origin)
-		c.Response().Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Response().Header().Set("Access-Control-Expose-Headers", "Content-Length, Content-Type")
+		ctx.SetHeader("Access-Control-Allow-Origin", origin)
+		ctx.SetHeader("Access-Control-Allow-Credentials", "true")
+		ctx.SetHeader("Access-Control-Expose-Headers", "Content-Length, Content-Type")
 		debug.Editing("CORS headers set for origin: %s", origin)
 	}
 }
 
-// setIframeHeaders sets headers to allow iframe embedding from allowed origins
-func setIframeHeaders(c echo.Context, allowedOrigins []string) {
-	// If no origins specified, allow all (for development)
-	if len(allowedOrigins) == 0 {
-		debug.Editing("no allowed origins configured, allowing iframe from all origins (development mode)")
-		c.Response().Header().Set("Content-Security-Policy", "frame-ancestors *")
-		// Don't set X-Frame-Options when using CSP frame-ancestors
-		return
+// setIframeHeaders sets the CSP frame-ancestors directive to allow iframe embedding from allowed origins
+func setIframeHeaders(ctx Context, allowedOrigins []string, base *CSP) {
+	policy := NewCSP()
+	if base != nil {
+		policy = base.Clone()
 	}
 
-	// Check for wildcard
-	if slices.Contains(allowedOrigins, "*") {
+	switch {
+	case len(allowedOrigins) == 0:
+		// If no origins specified, allow all (for development)
+		debug.Editing("no allowed origins configured, allowing iframe from all origins (development mode)")
+		policy.FrameAncestors("*")
+	case slices.Contains(allowedOrigins, "*"):
 		debug.Editing("wildcard configured, allowing iframe from all origins")
-		c.Response().Header().Set("Content-Security-Policy", "frame-ancestors *")
-		return
+		policy.FrameAncestors("*")
+	default:
+		policy.FrameAncestors(allowedOrigins...)
+		debug.Editing("iframe headers set for origins: %v", allowedOrigins)
 	}
 
-	// Build frame-ancestors directive with specific origins
-	// The CSP frame-ancestors directive takes a space-separated list of origins
-	frameAncestors := strings.Join(allowedOrigins, " ")
-	cspHeader := "frame-ancestors " + frameAncestors
-
-	c.Response().Header().Set("Content-Security-Policy", cspHeader)
-	debug.Editing("iframe headers set for origins: %v", allowedOrigins)
-
-	// Note: X-Frame-Options is deprecated in favor of CSP frame-ancestors
-	// If you need backwards compatibility with very old browsers, you could also set:
-	// c.Response().Header().Set("X-Frame-Options", "ALLOW-FROM "+allowedOrigins[0])
-	// However, X-Frame-Options only supports a single origin, so CSP is preferred
+	// X-Frame-Options is deprecated in favor of CSP frame-ancestors and only supports
+	// a single origin, so it is not set
+	ctx.SetHeader(policy.HeaderName(), policy.String(CSPNonce(ctx)))
 }
 
 // isOriginAllowed checks if the origin is in the allowed list
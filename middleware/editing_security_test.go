@@ -5,6 +5,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/guitarrich/content-sdk-go/config"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 )
@@ -408,3 +409,190 @@ func TestIsOriginAllowed(t *testing.T) {
 	}
 }
 
+func TestEditingSecurityMiddleware_ReloadableOrigins(t *testing.T) {
+	origins := NewOriginList([]string{"https://old.example.com"})
+
+	middleware := EditingSecurityMiddleware(EditingSecurityConfig{
+		Secret:  "test-secret",
+		Origins: origins,
+	})
+	handler := middleware(func(c echo.Context) error {
+		return c.String(http.StatusOK, "success")
+	})
+
+	origins.OnConfigChange(nil, &config.Config{
+		Editing: config.EditingConfig{AllowedOrigins: []string{"https://new.example.com"}},
+	})
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/editing/config?secret=test-secret", nil)
+	req.Header.Set("Origin", "https://new.example.com")
+	rec := httptest.NewRecorder()
+
+	err := handler(e.NewContext(req, rec))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://new.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "frame-ancestors https://new.example.com", rec.Header().Get("Content-Security-Policy"))
+}
+
+func TestEditingSecurityMiddleware_HeaderSecret(t *testing.T) {
+	middleware := EditingSecurityMiddleware(EditingSecurityConfig{
+		Secret: "test-secret",
+	})
+	handler := middleware(func(c echo.Context) error {
+		return c.String(http.StatusOK, "success")
+	})
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/editing/config", nil)
+	req.Header.Set("X-Editing-Secret", "test-secret")
+	rec := httptest.NewRecorder()
+
+	err := handler(e.NewContext(req, rec))
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestEditingSecurityMiddleware_RotatingSecrets(t *testing.T) {
+	middleware := EditingSecurityMiddleware(EditingSecurityConfig{
+		Secret:  "new-secret",
+		Secrets: []string{"old-secret"},
+	})
+	handler := middleware(func(c echo.Context) error {
+		return c.String(http.StatusOK, "success")
+	})
+
+	e := echo.New()
+	for secret, expected := range map[string]int{
+		"new-secret":   http.StatusOK,
+		"old-secret":   http.StatusOK,
+		"older-secret": http.StatusUnauthorized,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/editing/config?secret="+secret, nil)
+		rec := httptest.NewRecorder()
+
+		assert.NoError(t, handler(e.NewContext(req, rec)))
+		assert.Equal(t, expected, rec.Code, secret)
+	}
+}
+
+func TestEditingSecurityMiddleware_ReloadableSecrets(t *testing.T) {
+	secrets := NewSecretList("old-secret")
+
+	middleware := EditingSecurityMiddleware(EditingSecurityConfig{
+		SecretList: secrets,
+	})
+	handler := middleware(func(c echo.Context) error {
+		return c.String(http.StatusOK, "success")
+	})
+
+	secrets.OnConfigChange(nil, &config.Config{
+		Editing: config.EditingConfig{Secret: "new-secret"},
+	})
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/editing/config?secret=old-secret", nil)
+	rec := httptest.NewRecorder()
+	assert.NoError(t, handler(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/editing/config?secret=new-secret", nil)
+	rec = httptest.NewRecorder()
+	assert.NoError(t, handler(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestEditingSecurityMiddleware_RateLimitAndAudit(t *testing.T) {
+	var failures []EditingAuthFailure
+	middleware := EditingSecurityMiddleware(EditingSecurityConfig{
+		Secret:            "test-secret",
+		MaxFailedAttempts: 2,
+		OnAuthFailure: func(event EditingAuthFailure) {
+			failures = append(failures, event)
+		},
+	})
+	handler := middleware(func(c echo.Context) error {
+		return c.String(http.StatusOK, "success")
+	})
+
+	e := echo.New()
+	request := func(secret, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/editing/config?secret="+secret, nil)
+		req.RemoteAddr = ip + ":1234"
+		rec := httptest.NewRecorder()
+		assert.NoError(t, handler(e.NewContext(req, rec)))
+		return rec
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, request("wrong", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusUnauthorized, request("", "10.0.0.1").Code)
+
+	// The correct secret is rejected too once the limit is reached
+	rec := request("test-secret", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+	// Other clients are unaffected
+	assert.Equal(t, http.StatusOK, request("test-secret", "10.0.0.2").Code)
+
+	if assert.Len(t, failures, 3) {
+		assert.Equal(t, "invalid_secret", failures[0].Reason)
+		assert.Equal(t, "missing_secret", failures[1].Reason)
+		assert.Equal(t, "rate_limited", failures[2].Reason)
+		assert.Equal(t, "10.0.0.1", failures[0].IP)
+		assert.Equal(t, "/api/editing/config", failures[0].Path)
+	}
+}
+
+func TestSecretList_Contains(t *testing.T) {
+	secrets := NewSecretList("first", "", "second")
+
+	assert.True(t, secrets.Contains("first"))
+	assert.True(t, secrets.Contains("second"))
+	assert.False(t, secrets.Contains(""))
+	assert.False(t, secrets.Contains("firs"))
+}
+
+func TestEditingSecurityMiddleware_RateLimitIgnoresSpoofedHeaders(t *testing.T) {
+	middleware := EditingSecurityMiddleware(EditingSecurityConfig{
+		Secret:            "test-secret",
+		MaxFailedAttempts: 2,
+		TrustedProxies:    []string{"10.1.0.0/16"},
+	})
+	handler := middleware(func(c echo.Context) error {
+		return c.String(http.StatusOK, "success")
+	})
+
+	e := echo.New()
+	request := func(secret, remote, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/editing/config?secret="+secret, nil)
+		req.RemoteAddr = remote + ":1234"
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		rec := httptest.NewRecorder()
+		assert.NoError(t, handler(e.NewContext(req, rec)))
+		return rec.Code
+	}
+
+	// A direct client changing X-Forwarded-For on every attempt stays limited by its address
+	assert.Equal(t, http.StatusUnauthorized, request("wrong", "203.0.113.7", "198.51.100.1"))
+	assert.Equal(t, http.StatusUnauthorized, request("wrong", "203.0.113.7", "198.51.100.2"))
+	assert.Equal(t, http.StatusTooManyRequests, request("test-secret", "203.0.113.7", "198.51.100.3"))
+
+	// Spoofing a victim's IP doesn't lock the victim out
+	assert.Equal(t, http.StatusUnauthorized, request("wrong", "203.0.113.8", "192.0.2.50"))
+	assert.Equal(t, http.StatusUnauthorized, request("wrong", "203.0.113.8", "192.0.2.50"))
+	assert.Equal(t, http.StatusOK, request("test-secret", "192.0.2.50", ""))
+
+	// Behind a trusted proxy the forwarded client IP is limited, not the proxy
+	assert.Equal(t, http.StatusUnauthorized, request("wrong", "10.1.0.5", "192.0.2.60"))
+	assert.Equal(t, http.StatusUnauthorized, request("wrong", "10.1.0.5", "192.0.2.60"))
+	assert.Equal(t, http.StatusTooManyRequests, request("test-secret", "10.1.0.5", "192.0.2.60"))
+	assert.Equal(t, http.StatusOK, request("test-secret", "10.1.0.5", "192.0.2.61"))
+
+	// A client can't prepend a fake hop in front of the proxy-appended address
+	assert.Equal(t, http.StatusTooManyRequests, request("test-secret", "10.1.0.5", "192.0.2.99, 192.0.2.60"))
+}
@@ -3,6 +3,9 @@ package middleware
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/guitarrich/content-sdk-go/health"
+	"github.com/guitarrich/content-sdk-go/metrics"
 )
 
 // HealthcheckConfig contains configuration for healthcheck middleware
@@ -101,3 +104,41 @@ func ReadinessHandler(checks map[string]func() bool) HandlerFunc {
 		return json.NewEncoder(ctx.Response()).Encode(response)
 	}
 }
+
+// LivenessHandler serves the liveness checks of a checker, for example on /livez.
+// It responds 200 when all checks pass and 503 otherwise.
+func LivenessHandler(checker *health.Checker) HandlerFunc {
+	return func(ctx Context) error {
+		return writeHealthReport(ctx, checker.Liveness(ctx.Request().Context()))
+	}
+}
+
+// ReadinessCheckHandler serves the readiness checks of a checker, for example on /readyz.
+// It responds 200 when all checks pass and 503 otherwise.
+func ReadinessCheckHandler(checker *health.Checker) HandlerFunc {
+	return func(ctx Context) error {
+		return writeHealthReport(ctx, checker.Readiness(ctx.Request().Context()))
+	}
+}
+
+// writeHealthReport writes a health report with its status code
+func writeHealthReport(ctx Context, report health.Report) error {
+	ctx.SetHeader("Cache-Control", "no-store")
+	if !report.OK() {
+		return ctx.JSON(http.StatusServiceUnavailable, report)
+	}
+	return ctx.JSON(http.StatusOK, report)
+}
+
+// MetricsHandler serves SDK metrics in the Prometheus text format (default registry: metrics.Default)
+func MetricsHandler(registry *metrics.Registry) HandlerFunc {
+	if registry == nil {
+		registry = metrics.Default
+	}
+	return func(ctx Context) error {
+		ctx.SetHeader("Content-Type", metrics.ContentType)
+		ctx.Response().WriteHeader(http.StatusOK)
+		_, err := registry.WriteTo(ctx.Response())
+		return err
+	}
+}
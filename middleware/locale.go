@@ -5,6 +5,9 @@ import (
 	"strings"
 
 	"github.com/guitarrich/content-sdk-go/debug"
+	"github.com/guitarrich/content-sdk-go/i18n"
+	"github.com/guitarrich/content-sdk-go/metrics"
+	"github.com/guitarrich/content-sdk-go/site"
 )
 
 // LocaleConfig contains configuration for locale middleware
@@ -21,6 +24,24 @@ type LocaleConfig struct {
 	// UseAcceptLanguage enables Accept-Language header parsing
 	UseAcceptLanguage bool
 
+	// DomainLocales maps hosts to locales. A key can be an exact host ("example.fr"),
+	// a top-level domain with a leading dot (".fr") or a subdomain with a trailing dot ("fr.")
+	DomainLocales map[string]string
+
+	// Sites resolves the current site for per-site languages (optional).
+	// A site's Languages replace SupportedLanguages and its Language replaces DefaultLanguage.
+	Sites site.SiteResolver
+
+	// HideDefaultLocalePrefix redirects paths prefixed with the default language
+	// to the unprefixed path (e.g. /en/about -> /about). Unprefixed paths are then
+	// always in the default language, whatever the cookie or Accept-Language say.
+	HideDefaultLocalePrefix bool
+
+	// Strategies overrides the locale detection order (optional). By default the
+	// path, query, cookie, domain (with DomainLocales) and Accept-Language
+	// (with UseAcceptLanguage) strategies are tried in that order.
+	Strategies []LocaleStrategy
+
 	// CookieSecure sets the Secure attribute
 	CookieSecure bool
 
@@ -31,9 +52,162 @@ type LocaleConfig struct {
 	CookieSameSite http.SameSite
 }
 
+// LanguageSet is the set of languages available for a request
+type LanguageSet struct {
+	// Default is the default language
+	Default string
+
+	// Supported is the list of supported languages; empty means all languages
+	Supported []string
+}
+
+// Contains returns the supported language equal to locale (case-insensitive), or ""
+func (s LanguageSet) Contains(locale string) string {
+	if locale == "" {
+		return ""
+	}
+	if len(s.Supported) == 0 {
+		return locale
+	}
+	for _, supported := range s.Supported {
+		if strings.EqualFold(supported, locale) {
+			return supported
+		}
+	}
+	return ""
+}
+
+// Match returns the supported language for locale following its fallback chain
+// (fr-CA -> fr), or "" if none is supported
+func (s LanguageSet) Match(locale string) string {
+	if locale == "" {
+		return ""
+	}
+	if len(s.Supported) == 0 {
+		return locale
+	}
+	language, _ := i18n.Lookup(locale, s.Supported)
+	return language
+}
+
+// LocaleStrategy resolves a locale from a request.
+// It returns "" when the request carries no supported locale.
+type LocaleStrategy interface {
+	ResolveLocale(ctx Context, languages LanguageSet) string
+}
+
+// LocaleStrategyFunc is a function type that implements LocaleStrategy
+type LocaleStrategyFunc func(ctx Context, languages LanguageSet) string
+
+// ResolveLocale implements the LocaleStrategy interface
+func (f LocaleStrategyFunc) ResolveLocale(ctx Context, languages LanguageSet) string {
+	return f(ctx, languages)
+}
+
+// NamedLocaleStrategy names a strategy. The name is reported as the locale
+// resolution source in metrics; unnamed strategies are reported as "custom".
+func NamedLocaleStrategy(name string, strategy LocaleStrategy) LocaleStrategy {
+	return namedLocaleStrategy{LocaleStrategy: strategy, name: name}
+}
+
+// namedLocaleStrategy is a LocaleStrategy with a name
+type namedLocaleStrategy struct {
+	LocaleStrategy
+	name string
+}
+
+// localeStrategyName returns the name of a strategy
+func localeStrategyName(strategy LocaleStrategy) string {
+	if named, ok := strategy.(namedLocaleStrategy); ok {
+		return named.name
+	}
+	return "custom"
+}
+
+// PathLocaleStrategy resolves the locale from the first path segment (e.g. /fr/page)
+func PathLocaleStrategy() LocaleStrategy {
+	return NamedLocaleStrategy("path", LocaleStrategyFunc(func(ctx Context, languages LanguageSet) string {
+		return localeFromPath(ctx.Path(), languages)
+	}))
+}
+
+// QueryLocaleStrategy resolves the locale from query parameters (default: sc_lang, locale)
+func QueryLocaleStrategy(params ...string) LocaleStrategy {
+	if len(params) == 0 {
+		params = []string{"sc_lang", "locale"}
+	}
+	return NamedLocaleStrategy("query", LocaleStrategyFunc(func(ctx Context, languages LanguageSet) string {
+		query := ctx.Request().URL.Query()
+		for _, param := range params {
+			if value := query.Get(param); value != "" {
+				return languages.Match(value)
+			}
+		}
+		return ""
+	}))
+}
+
+// CookieLocaleStrategy resolves the locale from a cookie
+func CookieLocaleStrategy(name string) LocaleStrategy {
+	return NamedLocaleStrategy("cookie", LocaleStrategyFunc(func(ctx Context, languages LanguageSet) string {
+		if cookie, err := ctx.Cookie(name); err == nil && cookie != nil {
+			return languages.Match(cookie.Value)
+		}
+		return ""
+	}))
+}
+
+// DomainLocaleStrategy resolves the locale from the request host.
+// Exact hosts take precedence over subdomains, which take precedence over top-level domains.
+func DomainLocaleStrategy(locales map[string]string) LocaleStrategy {
+	normalized := make(map[string]string, len(locales))
+	for key, locale := range locales {
+		normalized[strings.ToLower(strings.TrimSpace(key))] = locale
+	}
+
+	return NamedLocaleStrategy("domain", LocaleStrategyFunc(func(ctx Context, languages LanguageSet) string {
+		host := strings.ToLower(requestHost(ctx))
+
+		if locale, ok := normalized[host]; ok {
+			return languages.Match(locale)
+		}
+		if sub, _, ok := strings.Cut(host, "."); ok {
+			if locale, ok := normalized[sub+"."]; ok {
+				return languages.Match(locale)
+			}
+		}
+		if idx := strings.LastIndex(host, "."); idx >= 0 {
+			if locale, ok := normalized[host[idx:]]; ok {
+				return languages.Match(locale)
+			}
+		}
+		return ""
+	}))
+}
+
+// AcceptLanguageStrategy resolves the locale from the Accept-Language header
+// using RFC 4647 matching with q-values
+func AcceptLanguageStrategy() LocaleStrategy {
+	return NamedLocaleStrategy("accept-language", LocaleStrategyFunc(func(ctx Context, languages LanguageSet) string {
+		header := ctx.Header("Accept-Language")
+		if header == "" {
+			return ""
+		}
+		if len(languages.Supported) == 0 {
+			if ranges := i18n.ParseAcceptLanguage(header); len(ranges) > 0 && ranges[0].Tag != "*" {
+				return ranges[0].Tag
+			}
+			return ""
+		}
+		language, _ := i18n.MatchAcceptLanguage(header, languages.Supported)
+		return language
+	}))
+}
+
 // LocaleMiddleware handles language/locale detection
 type LocaleMiddleware struct {
-	config LocaleConfig
+	config     LocaleConfig
+	strategies []LocaleStrategy
 }
 
 // NewLocaleMiddleware creates a new locale middleware
@@ -55,125 +229,153 @@ func NewLocaleMiddleware(config LocaleConfig) *LocaleMiddleware {
 		config.CookieSameSite = http.SameSiteLaxMode
 	}
 
+	strategies := config.Strategies
+	if len(strategies) == 0 {
+		strategies = []LocaleStrategy{
+			PathLocaleStrategy(),
+			QueryLocaleStrategy(),
+			CookieLocaleStrategy(config.CookieName),
+		}
+		if len(config.DomainLocales) > 0 {
+			strategies = append(strategies, DomainLocaleStrategy(config.DomainLocales))
+		}
+		if config.UseAcceptLanguage {
+			strategies = append(strategies, AcceptLanguageStrategy())
+		}
+	}
+
 	return &LocaleMiddleware{
-		config: config,
+		config:     config,
+		strategies: strategies,
 	}
 }
 
 // Handle processes the locale middleware
 func (m *LocaleMiddleware) Handle(ctx Context, next HandlerFunc) error {
 	path := ctx.Path()
-	var locale string
+	languages := m.languagesFor(ctx)
 
 	debug.Locale("processing locale for path=%s", path)
 
-	// 1. Try to extract locale from URL path (e.g., /fr/page)
-	locale = m.extractLocaleFromPath(path)
-	if locale != "" && m.isSupported(locale) {
-		debug.Locale("locale from path: %s", locale)
-		ctx.Set(LocaleKey, locale)
-		m.setCookie(ctx, locale)
-		return next(ctx)
-	}
-
-	// 2. Try to get locale from query parameter
-	localeParam := ctx.Request().URL.Query().Get("sc_lang")
-	if localeParam == "" {
-		localeParam = ctx.Request().URL.Query().Get("locale")
-	}
-	if localeParam != "" && m.isSupported(localeParam) {
-		debug.Locale("locale from query param: %s", localeParam)
-		locale = localeParam
-		ctx.Set(LocaleKey, locale)
-		m.setCookie(ctx, locale)
-		return next(ctx)
-	}
-
-	// 3. Try to get locale from cookie
-	if cookie, err := ctx.Cookie(m.config.CookieName); err == nil && cookie != nil {
-		if m.isSupported(cookie.Value) {
-			debug.Locale("locale from cookie: %s", cookie.Value)
-			locale = cookie.Value
-			ctx.Set(LocaleKey, locale)
-			return next(ctx)
+	// Hide the prefix for the default language
+	if m.config.HideDefaultLocalePrefix {
+		if prefix := localeFromPath(path, languages); prefix != "" && strings.EqualFold(prefix, languages.Default) {
+			target := stripLocalePrefix(path)
+			// Restore the site path prefix the multisite middleware stripped
+			if sitePrefix, _ := ctx.Get(SitePathPrefixKey).(string); sitePrefix != "" {
+				if target == "/" {
+					target = sitePrefix
+				} else {
+					target = sitePrefix + target
+				}
+			}
+			if query := ctx.Request().URL.RawQuery; query != "" {
+				target += "?" + query
+			}
+			debug.Locale("redirecting default locale path %s to %s", path, target)
+			// Remember the choice so the unprefixed URL isn't ambiguous for the visitor
+			m.setCookie(ctx, languages.Default)
+			return ctx.Redirect(http.StatusMovedPermanently, target)
 		}
 	}
 
-	// 4. Try Accept-Language header
-	if m.config.UseAcceptLanguage {
-		acceptLang := ctx.Header("Accept-Language")
-		if acceptLang != "" {
-			locale = m.parseAcceptLanguage(acceptLang)
-			if locale != "" && m.isSupported(locale) {
-				debug.Locale("locale from Accept-Language: %s", locale)
-				ctx.Set(LocaleKey, locale)
-				m.setCookie(ctx, locale)
-				return next(ctx)
+	locale, source := "", "default"
+	if m.config.HideDefaultLocalePrefix && localeFromPath(path, languages) == "" {
+		// An unprefixed path is the default language's URL
+		locale, source = languages.Default, "path"
+		debug.Locale("unprefixed path uses the default locale: %s", locale)
+	} else {
+		// Try each strategy in order
+		for _, strategy := range m.strategies {
+			if locale = strategy.ResolveLocale(ctx, languages); locale != "" {
+				source = localeStrategyName(strategy)
+				debug.Locale("locale resolved: %s (%s)", locale, source)
+				break
 			}
 		}
 	}
 
-	// 5. Fall back to default language
-	locale = m.config.DefaultLanguage
-	debug.Locale("using default locale: %s", locale)
+	// Fall back to default language
+	if locale == "" {
+		locale = languages.Default
+		debug.Locale("using default locale: %s", locale)
+	}
+	metrics.LocaleResolutions.Inc(source)
+
 	ctx.Set(LocaleKey, locale)
-	m.setCookie(ctx, locale)
+
+	// Only refresh the cookie when it doesn't already hold the locale
+	if cookie, err := ctx.Cookie(m.config.CookieName); err != nil || cookie == nil || cookie.Value != locale {
+		m.setCookie(ctx, locale)
+	}
 
 	return next(ctx)
 }
 
-// extractLocaleFromPath extracts locale from URL path
-func (m *LocaleMiddleware) extractLocaleFromPath(path string) string {
-	// Path format: /fr/page or /fr-CA/page
-	parts := strings.Split(strings.Trim(path, "/"), "/")
-	if len(parts) > 0 {
-		firstPart := parts[0]
-		// Check if first part looks like a locale (2 chars or 2-2 chars like fr-CA)
-		if len(firstPart) == 2 || (len(firstPart) == 5 && firstPart[2] == '-') {
-			return firstPart
-		}
+// languagesFor returns the languages for the current site
+func (m *LocaleMiddleware) languagesFor(ctx Context) LanguageSet {
+	languages := LanguageSet{
+		Default:   m.config.DefaultLanguage,
+		Supported: m.config.SupportedLanguages,
 	}
-	return ""
+
+	if m.config.Sites == nil {
+		return languages
+	}
+	siteName, _ := ctx.Get(SiteKey).(string)
+	if siteName == "" {
+		return languages
+	}
+	siteInfo, err := m.config.Sites.GetByName(siteName)
+	if err != nil || siteInfo == nil {
+		return languages
+	}
+
+	if len(siteInfo.Languages) > 0 {
+		languages.Supported = siteInfo.Languages
+	}
+	if siteInfo.Language != "" {
+		languages.Default = siteInfo.Language
+	}
+	return languages
 }
 
-// parseAcceptLanguage parses Accept-Language header
-func (m *LocaleMiddleware) parseAcceptLanguage(header string) string {
-	// Parse Accept-Language header (e.g., "en-US,en;q=0.9,fr;q=0.8")
-	languages := strings.SplitSeq(header, ",")
-	for lang := range languages {
-		// Remove quality value if present
-		parts := strings.Split(lang, ";")
-		language := strings.TrimSpace(parts[0])
+// localeFromPath extracts a supported locale from the first path segment
+func localeFromPath(path string, languages LanguageSet) string {
+	// Path format: /fr/page or /fr-CA/page
+	first, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	if first == "" {
+		return ""
+	}
 
-		// Try exact match first
-		if m.isSupported(language) {
-			return language
-		}
+	if len(languages.Supported) > 0 {
+		return languages.Contains(first)
+	}
 
-		// Try language prefix (en-US -> en)
-		if idx := strings.Index(language, "-"); idx > 0 {
-			prefix := language[:idx]
-			if m.isSupported(prefix) {
-				return prefix
-			}
-		}
+	// Without a supported list, accept anything that looks like a locale (2 chars or 2-2 chars like fr-CA)
+	if len(first) == 2 || (len(first) == 5 && first[2] == '-') {
+		return first
 	}
 	return ""
 }
 
-// isSupported checks if a locale is supported
-func (m *LocaleMiddleware) isSupported(locale string) bool {
-	if len(m.config.SupportedLanguages) == 0 {
-		return true // All languages supported if none specified
-	}
+// stripLocalePrefix removes the first path segment
+func stripLocalePrefix(path string) string {
+	_, rest, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return "/" + rest
+}
 
-	locale = strings.ToLower(locale)
-	for _, supported := range m.config.SupportedLanguages {
-		if strings.ToLower(supported) == locale {
-			return true
-		}
+// requestHost returns the request host without port, preferring X-Forwarded-Host
+func requestHost(ctx Context) string {
+	host := ctx.Request().Host
+	if forwarded := ctx.Header("X-Forwarded-Host"); forwarded != "" {
+		host, _, _ = strings.Cut(forwarded, ",")
+	}
+	host = strings.TrimSpace(host)
+	if idx := strings.LastIndex(host, ":"); idx > 0 && !strings.HasSuffix(host, "]") {
+		host = host[:idx]
 	}
-	return false
+	return host
 }
 
 // setCookie sets the locale cookie
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"github.com/guitarrich/content-sdk-go/debug"
+)
+
+// RequestLoggerConfig contains configuration for request logging
+type RequestLoggerConfig struct {
+	// Logger receives the request logs (default: debug.Logger("http"))
+	Logger *slog.Logger
+
+	// RequestIDHeader is the header the request ID is read from and
+	// returned in (default: "X-Request-ID")
+	RequestIDHeader string
+
+	// Level is the level of completed requests (default: info)
+	Level slog.Level
+}
+
+// RequestLogger logs completed requests with their request ID, site, locale,
+// path and duration. The request ID, method and path are added to the request
+// context (debug.WithAttrs), so every SDK log made while handling the request
+// carries them.
+type RequestLogger struct {
+	config RequestLoggerConfig
+}
+
+// NewRequestLogger creates a new request logging middleware
+func NewRequestLogger(config RequestLoggerConfig) *RequestLogger {
+	if config.Logger == nil {
+		config.Logger = debug.Logger("http")
+	}
+	if config.RequestIDHeader == "" {
+		config.RequestIDHeader = "X-Request-ID"
+	}
+	return &RequestLogger{
+		config: config,
+	}
+}
+
+// Handle processes the request logging middleware
+func (m *RequestLogger) Handle(ctx Context, next HandlerFunc) error {
+	start := time.Now()
+
+	requestID := ctx.Header(m.config.RequestIDHeader)
+	if requestID == "" || len(requestID) > 128 {
+		requestID = generateRequestID()
+	}
+	ctx.Set(RequestIDKey, requestID)
+	ctx.SetHeader(m.config.RequestIDHeader, requestID)
+
+	request := ctx.Request()
+	setRequest(ctx, request.WithContext(debug.WithAttrs(request.Context(),
+		slog.String(debug.RequestIDKey, requestID),
+		slog.String("method", request.Method),
+		slog.String(debug.PathKey, ctx.Path()),
+	)))
+
+	err := next(ctx)
+
+	attrs := []slog.Attr{slog.Duration(debug.DurationKey, time.Since(start))}
+	if site, ok := ctx.Get(SiteKey).(string); ok && site != "" {
+		attrs = append(attrs, slog.String(debug.SiteKey, site))
+	}
+	if locale, ok := ctx.Get(LocaleKey).(string); ok && locale != "" {
+		attrs = append(attrs, slog.String(debug.LocaleKey, locale))
+	}
+	level := m.config.Level
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+		level = slog.LevelError
+	}
+	m.config.Logger.LogAttrs(ctx.Request().Context(), level, "request completed", attrs...)
+
+	return err
+}
+
+// RequestID returns the request ID set by RequestLogger, or ""
+func RequestID(ctx Context) string {
+	requestID, _ := ctx.Get(RequestIDKey).(string)
+	return requestID
+}
+
+// generateRequestID returns a random hex request ID
+func generateRequestID() string {
+	buf := make([]byte, 8)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/guitarrich/content-sdk-go/metrics"
+	"github.com/labstack/echo/v4"
+)
+
+// ErrorResponseMetrics records not found and server error responses per site in
+// metrics.ErrorResponses. Register it before the multisite middleware so it sees
+// the responses of every handler, including errors handlers return.
+type ErrorResponseMetrics struct{}
+
+// NewErrorResponseMetrics creates a new error response metrics middleware
+func NewErrorResponseMetrics() *ErrorResponseMetrics {
+	return &ErrorResponseMetrics{}
+}
+
+// Handle processes the error response metrics middleware
+func (m *ErrorResponseMetrics) Handle(ctx Context, next HandlerFunc) error {
+	recorder := &statusContext{Context: ctx}
+	err := next(recorder)
+
+	status := recorder.responseStatus(err)
+	if status == http.StatusNotFound || status >= http.StatusInternalServerError {
+		site, _ := ctx.Get(SiteKey).(string)
+		metrics.RecordErrorResponse(site, status)
+	}
+	return err
+}
+
+// StatusReporter is implemented by contexts that track the status code written
+// to the response, such as EchoContext
+type StatusReporter interface {
+	// ResponseStatus returns the status code and whether the response was written
+	ResponseStatus() (int, bool)
+}
+
+// statusContext records the status code written through a Context
+type statusContext struct {
+	Context
+	writer *statusWriter
+	status int
+}
+
+// SetRequest replaces the request of the wrapped context, if it implements RequestSetter
+func (c *statusContext) SetRequest(request *http.Request) {
+	setRequest(c.Context, request)
+}
+
+// Response returns the response writer, recording the status code written to it
+func (c *statusContext) Response() http.ResponseWriter {
+	if c.writer == nil {
+		c.writer = &statusWriter{ResponseWriter: c.Context.Response()}
+	}
+	return c.writer
+}
+
+// Redirect performs an HTTP redirect
+func (c *statusContext) Redirect(code int, url string) error {
+	c.status = code
+	return c.Context.Redirect(code, url)
+}
+
+// String sends a string response
+func (c *statusContext) String(code int, s string) error {
+	c.status = code
+	return c.Context.String(code, s)
+}
+
+// JSON sends a JSON response
+func (c *statusContext) JSON(code int, i any) error {
+	c.status = code
+	return c.Context.JSON(code, i)
+}
+
+// NoContent sends a no content response
+func (c *statusContext) NoContent(code int) error {
+	c.status = code
+	return c.Context.NoContent(code)
+}
+
+// responseStatus returns the status of the response. An error the handler
+// returns is written by the framework's error handler after the middleware.
+func (c *statusContext) responseStatus(err error) int {
+	if err != nil {
+		var httpError *echo.HTTPError
+		if errors.As(err, &httpError) {
+			return httpError.Code
+		}
+		return http.StatusInternalServerError
+	}
+	if c.writer != nil && c.writer.status != 0 {
+		return c.writer.status
+	}
+	if c.status != 0 {
+		return c.status
+	}
+	if reporter, ok := c.Context.(StatusReporter); ok {
+		if status, written := reporter.ResponseStatus(); written {
+			return status
+		}
+	}
+	return http.StatusOK
+}
+
+// statusWriter records the status code written to a response
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records and writes the status code
+func (w *statusWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write records an implicit 200 status and writes the body
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -1,7 +1,10 @@
 package middleware
 
 import (
+	"fmt"
 	"net/http"
+
+	"github.com/guitarrich/content-sdk-go/telemetry"
 )
 
 // Context is a framework-agnostic HTTP context abstraction
@@ -49,6 +52,22 @@ type Context interface {
 	NoContent(code int) error
 }
 
+// RequestSetter is implemented by contexts that can replace the HTTP request,
+// such as EchoContext. Middleware use it to pass values and spans to later
+// handlers in the request context; with other contexts they are only available
+// through Get.
+type RequestSetter interface {
+	// SetRequest replaces the HTTP request, e.g. with one carrying a derived context
+	SetRequest(request *http.Request)
+}
+
+// setRequest replaces the request of ctx if it implements RequestSetter
+func setRequest(ctx Context, request *http.Request) {
+	if setter, ok := ctx.(RequestSetter); ok {
+		setter.SetRequest(request)
+	}
+}
+
 // HandlerFunc is a framework-agnostic handler function
 type HandlerFunc func(ctx Context) error
 
@@ -74,13 +93,33 @@ func Chain(middlewares ...Middleware) Middleware {
 			mw := middlewares[i]
 			currentHandler := handler
 			handler = func(c Context) error {
-				return mw.Handle(c, currentHandler)
+				if !telemetry.Enabled() {
+					return mw.Handle(c, currentHandler)
+				}
+				return handleWithSpan(c, mw, currentHandler)
 			}
 		}
 		return handler(ctx)
 	})
 }
 
+// handleWithSpan runs a middleware in a span named after its type. The span
+// is the current span of the request context while the middleware runs.
+func handleWithSpan(ctx Context, mw Middleware, next HandlerFunc) error {
+	request := ctx.Request()
+	parent := telemetry.SpanFromContext(request.Context())
+	spanCtx, span := telemetry.StartSpan(request.Context(), fmt.Sprintf("middleware %T", mw))
+
+	setRequest(ctx, request.WithContext(spanCtx))
+	err := mw.Handle(ctx, next)
+
+	// Restore the parent span, keeping values added to the context by the middleware
+	request = ctx.Request()
+	setRequest(ctx, request.WithContext(telemetry.ContextWithSpan(request.Context(), parent)))
+	telemetry.EndSpan(span, err)
+	return err
+}
+
 // Constants for common context keys
 const (
 	// SiteKey is the context key for site name
@@ -97,4 +136,15 @@ const (
 
 	// PersonalizeVariantKey is the context key for personalization variant ID
 	PersonalizeVariantKey = "personalizeVariant"
+
+	// SitePathPrefixKey is the context key for the site path prefix stripped from the request path
+	SitePathPrefixKey = "sitePathPrefix"
+
+	// EditingContextKey is the context key for the *models.EditingContext. It is
+	// namespaced so application values can't collide with it; read it with
+	// EditingContextFrom.
+	EditingContextKey = "sitecore.editingContext"
+
+	// RequestIDKey is the context key for the request ID
+	RequestIDKey = "requestId"
 )
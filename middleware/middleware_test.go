@@ -1,9 +1,22 @@
 package middleware
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"github.com/guitarrich/content-sdk-go/config"
+	"github.com/guitarrich/content-sdk-go/debug"
+	"github.com/guitarrich/content-sdk-go/metrics"
+	"github.com/guitarrich/content-sdk-go/models"
+	"github.com/guitarrich/content-sdk-go/site"
+	"github.com/labstack/echo/v4"
 )
 
 // MockContext is a simple mock implementation of Context for testing
@@ -29,6 +42,7 @@ func NewMockContext(method, path string) *MockContext {
 }
 
 func (m *MockContext) Request() *http.Request        { return m.request }
+func (m *MockContext) SetRequest(r *http.Request)    { m.request = r }
 func (m *MockContext) Response() http.ResponseWriter { return m.response }
 func (m *MockContext) Path() string                  { return m.path }
 func (m *MockContext) SetPath(path string)           { m.path = path }
@@ -209,3 +223,570 @@ func TestHealthcheckMiddleware_PassThrough(t *testing.T) {
 		t.Error("next handler should be called for non-healthcheck paths")
 	}
 }
+
+func TestMultisiteMiddleware_OnConfigChange(t *testing.T) {
+	mw := NewMultisiteMiddleware(MultisiteConfig{
+		Enabled:     true,
+		Sites:       []models.SiteInfo{{Name: "site1", HostName: "site1.com"}},
+		DefaultSite: models.SiteInfo{Name: "site1"},
+	})
+
+	resolve := func(host string) string {
+		ctx := NewMockContext("GET", "/page")
+		ctx.request.Host = host
+		if err := mw.Handle(ctx, func(ctx Context) error { return nil }); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		site, _ := ctx.Get(SiteKey).(string)
+		return site
+	}
+
+	if site := resolve("site2.com"); site != "site1" {
+		t.Errorf("expected default site 'site1' before reload, got '%s'", site)
+	}
+
+	newConfig := &config.Config{
+		Multisite: config.MultisiteConfig{
+			Enabled: true,
+			Sites: []models.SiteInfo{
+				{Name: "site1", HostName: "site1.com"},
+				{Name: "site2", HostName: "site2.com"},
+			},
+			DefaultSite: models.SiteInfo{Name: "site1"},
+		},
+	}
+	mw.OnConfigChange(nil, newConfig)
+
+	if site := resolve("site2.com"); site != "site2" {
+		t.Errorf("expected 'site2' after reload, got '%s'", site)
+	}
+}
+
+func TestMultisiteMiddleware_PathPrefix(t *testing.T) {
+	mw := NewMultisiteMiddleware(MultisiteConfig{
+		Enabled: true,
+		Sites: []models.SiteInfo{
+			{Name: "main", HostName: "example.com"},
+			{Name: "brand-a", HostName: "example.com:8443/brand-a"},
+		},
+		DefaultSite: models.SiteInfo{Name: "main"},
+	})
+
+	ctx := NewMockContext("GET", "/brand-a/products")
+	ctx.request.Host = "example.com:8443"
+
+	if err := mw.Handle(ctx, func(ctx Context) error { return nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if site := ctx.Get(SiteKey); site != "brand-a" {
+		t.Errorf("expected site 'brand-a', got '%v'", site)
+	}
+	if ctx.Path() != "/products" {
+		t.Errorf("expected stripped path '/products', got '%s'", ctx.Path())
+	}
+	if prefix := ctx.Get(SitePathPrefixKey); prefix != "/brand-a" {
+		t.Errorf("expected path prefix '/brand-a', got '%v'", prefix)
+	}
+	if rewrite := ctx.Get(RewritePathKey); rewrite != "/_site_brand-a/products" {
+		t.Errorf("expected rewrite path '/_site_brand-a/products', got '%v'", rewrite)
+	}
+	if original := ctx.Get(OriginalPathKey); original != "/brand-a/products" {
+		t.Errorf("expected original path '/brand-a/products', got '%v'", original)
+	}
+}
+
+func TestLocaleMiddleware_Strategies(t *testing.T) {
+	mw := NewLocaleMiddleware(LocaleConfig{
+		DefaultLanguage:    "en",
+		SupportedLanguages: []string{"en", "fr", "fr-CA", "de"},
+		UseAcceptLanguage:  true,
+		DomainLocales: map[string]string{
+			"example.de": "de",
+			"fr.":        "fr",
+			".ca":        "fr-CA",
+		},
+	})
+
+	tests := []struct {
+		name     string
+		path     string
+		host     string
+		header   string
+		expected string
+	}{
+		{"path prefix", "/fr/about", "example.com", "", "fr"},
+		{"query fallback chain", "/about?sc_lang=fr-BE", "example.com", "", "fr"},
+		{"exact host", "/about", "example.de", "", "de"},
+		{"subdomain", "/about", "fr.example.com:8080", "", "fr"},
+		{"top-level domain", "/about", "example.ca", "", "fr-CA"},
+		{"accept-language q-values", "/about", "example.com", "es,de;q=0.5,fr;q=0.9", "fr"},
+		{"default", "/about", "example.com", "es", "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := NewMockContext("GET", tt.path)
+			ctx.request.Host = tt.host
+			if tt.header != "" {
+				ctx.request.Header.Set("Accept-Language", tt.header)
+			}
+
+			if err := mw.Handle(ctx, func(ctx Context) error { return nil }); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if locale := ctx.Get(LocaleKey); locale != tt.expected {
+				t.Errorf("expected locale '%s', got '%v'", tt.expected, locale)
+			}
+		})
+	}
+}
+
+func TestLocaleMiddleware_PerSiteLanguages(t *testing.T) {
+	sites := site.NewSiteResolver([]models.SiteInfo{
+		{Name: "global", HostName: "example.com", Language: "en"},
+		{Name: "swiss", HostName: "example.ch", Language: "de", Languages: []string{"de", "fr", "it"}},
+	}, models.SiteInfo{Name: "global"})
+
+	mw := NewLocaleMiddleware(LocaleConfig{
+		DefaultLanguage:    "en",
+		SupportedLanguages: []string{"en", "fr"},
+		Sites:              sites,
+	})
+
+	// "it" is only supported on the swiss site
+	ctx := NewMockContext("GET", "/it/chi-siamo")
+	ctx.Set(SiteKey, "swiss")
+	_ = mw.Handle(ctx, func(ctx Context) error { return nil })
+	if locale := ctx.Get(LocaleKey); locale != "it" {
+		t.Errorf("expected locale 'it', got '%v'", locale)
+	}
+
+	ctx = NewMockContext("GET", "/it/chi-siamo")
+	ctx.Set(SiteKey, "global")
+	_ = mw.Handle(ctx, func(ctx Context) error { return nil })
+	if locale := ctx.Get(LocaleKey); locale != "en" {
+		t.Errorf("expected locale 'en', got '%v'", locale)
+	}
+
+	// The site language is the default
+	ctx = NewMockContext("GET", "/about")
+	ctx.Set(SiteKey, "swiss")
+	_ = mw.Handle(ctx, func(ctx Context) error { return nil })
+	if locale := ctx.Get(LocaleKey); locale != "de" {
+		t.Errorf("expected locale 'de', got '%v'", locale)
+	}
+}
+
+func TestLocaleMiddleware_HideDefaultLocalePrefix(t *testing.T) {
+	mw := NewLocaleMiddleware(LocaleConfig{
+		DefaultLanguage:         "en",
+		SupportedLanguages:      []string{"en", "fr"},
+		HideDefaultLocalePrefix: true,
+	})
+
+	ctx := NewMockContext("GET", "/en/about?x=1")
+	ctx.path = "/en/about"
+	called := false
+	_ = mw.Handle(ctx, func(ctx Context) error {
+		called = true
+		return nil
+	})
+
+	if called {
+		t.Error("expected redirect without calling next")
+	}
+	if ctx.response.Code != http.StatusMovedPermanently {
+		t.Errorf("expected status 301, got %d", ctx.response.Code)
+	}
+	if location := ctx.response.Header().Get("Location"); location != "/about?x=1" {
+		t.Errorf("expected redirect to '/about?x=1', got '%s'", location)
+	}
+
+	// Other locales keep their prefix
+	ctx = NewMockContext("GET", "/fr/about")
+	_ = mw.Handle(ctx, func(ctx Context) error { return nil })
+	if locale := ctx.Get(LocaleKey); locale != "fr" {
+		t.Errorf("expected locale 'fr', got '%v'", locale)
+	}
+}
+
+func TestLocaleMiddleware_HideDefaultLocalePrefix_Cookie(t *testing.T) {
+	mw := NewLocaleMiddleware(LocaleConfig{
+		DefaultLanguage:         "en",
+		SupportedLanguages:      []string{"en", "fr"},
+		UseAcceptLanguage:       true,
+		HideDefaultLocalePrefix: true,
+	})
+
+	// The unprefixed URL is English, whatever the cookie and Accept-Language say
+	ctx := NewMockContext("GET", "/about")
+	ctx.request.AddCookie(&http.Cookie{Name: "sc_locale", Value: "fr"})
+	ctx.request.Header.Set("Accept-Language", "fr")
+	_ = mw.Handle(ctx, func(ctx Context) error { return nil })
+	if locale := ctx.Get(LocaleKey); locale != "en" {
+		t.Errorf("expected locale 'en', got '%v'", locale)
+	}
+
+	// The redirect from the default prefix switches the cookie to the default language
+	ctx = NewMockContext("GET", "/en/about")
+	ctx.request.AddCookie(&http.Cookie{Name: "sc_locale", Value: "fr"})
+	_ = mw.Handle(ctx, func(ctx Context) error { return nil })
+	if ctx.response.Code != http.StatusMovedPermanently {
+		t.Errorf("expected status 301, got %d", ctx.response.Code)
+	}
+	if cookie := ctx.response.Header().Get("Set-Cookie"); !strings.HasPrefix(cookie, "sc_locale=en;") {
+		t.Errorf("expected the cookie to be set to 'en', got '%s'", cookie)
+	}
+
+	// Prefixed paths still resolve their own locale
+	ctx = NewMockContext("GET", "/fr/about")
+	_ = mw.Handle(ctx, func(ctx Context) error { return nil })
+	if locale := ctx.Get(LocaleKey); locale != "fr" {
+		t.Errorf("expected locale 'fr', got '%v'", locale)
+	}
+}
+
+func TestLocaleMiddleware_HideDefaultLocalePrefix_SitePathPrefix(t *testing.T) {
+	multisite := NewMultisiteMiddleware(MultisiteConfig{
+		Enabled: true,
+		Sites: []models.SiteInfo{
+			{Name: "main", HostName: "example.com"},
+			{Name: "brand-a", HostName: "example.com/brand-a"},
+		},
+		DefaultSite: models.SiteInfo{Name: "main"},
+	})
+	locale := NewLocaleMiddleware(LocaleConfig{
+		DefaultLanguage:         "en",
+		SupportedLanguages:      []string{"en", "fr"},
+		HideDefaultLocalePrefix: true,
+	})
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/brand-a/en/about", "/brand-a/about"},
+		{"/brand-a/en", "/brand-a"},
+		{"/en/about", "/about"},
+	}
+	for _, tt := range tests {
+		ctx := NewMockContext("GET", tt.path)
+		ctx.request.Host = "example.com"
+		_ = Chain(multisite, locale).Handle(ctx, func(ctx Context) error { return nil })
+
+		if ctx.response.Code != http.StatusMovedPermanently {
+			t.Errorf("%s: expected status 301, got %d", tt.path, ctx.response.Code)
+		}
+		if location := ctx.response.Header().Get("Location"); location != tt.expected {
+			t.Errorf("%s: expected redirect to '%s', got '%s'", tt.path, tt.expected, location)
+		}
+	}
+}
+
+func TestEditingContextResolver(t *testing.T) {
+	resolver := NewEditingContextResolver(EditingContextConfig{})
+
+	tests := []struct {
+		name        string
+		path        string
+		cookie      string
+		frame       bool
+		mode        models.PageMode
+		previewMode models.PreviewMode
+		source      string
+	}{
+		{"normal", "/about", "", false, models.PageModeNormal, "", ""},
+		{"sc_mode edit", "/about?sc_mode=edit", "", false, models.PageModeEdit, models.PreviewModeEdit, "query"},
+		{"pages metadata", "/api/editing/render?mode=metadata&sc_itemid=abc&sc_lang=en&sc_site=main", "", false, models.PageModeEdit, models.PreviewModeMetadata, "query"},
+		{"mode takes precedence", "/about?mode=preview&sc_mode=edit", "", false, models.PageModePreview, models.PreviewModePreview, "query"},
+		{"preview cookie", "/about", "sc_mode=preview&sc_itemid=abc", false, models.PageModePreview, models.PreviewModePreview, "cookie"},
+		{"sc_mode normal ignores cookie", "/about?sc_mode=normal", "sc_mode=preview", false, models.PageModeNormal, "", ""},
+		{"pages iframe", "/about?sc_itemid=abc", "", true, models.PageModeEdit, models.PreviewModeEdit, "header"},
+		{"item without iframe", "/about?sc_itemid=abc", "", false, models.PageModeNormal, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := NewMockContext("GET", tt.path)
+			if tt.cookie != "" {
+				ctx.request.AddCookie(&http.Cookie{Name: "sc_preview", Value: tt.cookie})
+			}
+			if tt.frame {
+				ctx.request.Header.Set("Sec-Fetch-Dest", "iframe")
+				ctx.request.Header.Set("Referer", "https://pages.sitecorecloud.io/editor?sc_site=main")
+			}
+
+			if err := resolver.Handle(ctx, func(ctx Context) error { return nil }); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			editingContext := EditingContextFrom(ctx)
+			if editingContext.Mode != tt.mode {
+				t.Errorf("expected mode '%s', got '%s'", tt.mode, editingContext.Mode)
+			}
+			if editingContext.PreviewMode != tt.previewMode {
+				t.Errorf("expected preview mode '%s', got '%s'", tt.previewMode, editingContext.PreviewMode)
+			}
+			if editingContext.Source != tt.source {
+				t.Errorf("expected source '%s', got '%s'", tt.source, editingContext.Source)
+			}
+			if GetEditingContext(ctx.Request().Context()) != editingContext {
+				t.Error("expected the request context to carry the same editing context")
+			}
+		})
+	}
+}
+
+func TestEditingContextResolver_Params(t *testing.T) {
+	ctx := NewMockContext("GET", "/api/editing/render?mode=edit&sc_itemid=abc&sc_lang=en&sc_site=main&sc_version=2&sc_layoutKind=shared&sc_variant=a,b&route=/about")
+	// An application value under the plain key doesn't collide
+	ctx.Set("editingContext", "application value")
+
+	editingContext := EditingContextFrom(ctx)
+	previewData := editingContext.PreviewData()
+
+	if previewData.ItemID != "abc" || previewData.Language != "en" || previewData.Site != "main" || previewData.Version != "2" {
+		t.Errorf("unexpected item parameters: %+v", previewData)
+	}
+	if previewData.LayoutKind != models.LayoutKindShared {
+		t.Errorf("expected shared layout, got '%s'", previewData.LayoutKind)
+	}
+	if len(previewData.VariantIds) != 2 || previewData.Route != "/about" {
+		t.Errorf("unexpected variants or route: %+v", previewData)
+	}
+	if !IsEditMode(ctx.Request().Context()) || IsPreviewMode(ctx.Request().Context()) {
+		t.Error("expected edit mode")
+	}
+	if ctx.Get(EditingContextKey) != editingContext {
+		t.Error("expected the editing context to be stored in the middleware context")
+	}
+	if ctx.Get("editingContext") != "application value" {
+		t.Error("expected the application value to be kept")
+	}
+}
+
+func TestEditingContextResolver_PersistPreview(t *testing.T) {
+	resolver := NewEditingContextResolver(EditingContextConfig{PersistPreview: true})
+
+	ctx := NewMockContext("GET", "/about?sc_mode=preview&sc_lang=en")
+	resolver.Handle(ctx, func(ctx Context) error { return nil })
+
+	cookie := ctx.response.Header().Get("Set-Cookie")
+	if !strings.Contains(cookie, "sc_preview=sc_lang=en&sc_mode=preview") {
+		t.Errorf("expected preview cookie, got '%s'", cookie)
+	}
+
+	ctx = NewMockContext("GET", "/about?sc_mode=normal")
+	resolver.Handle(ctx, func(ctx Context) error { return nil })
+
+	cookie = ctx.response.Header().Get("Set-Cookie")
+	if !strings.Contains(cookie, "sc_preview=;") || !strings.Contains(cookie, "Max-Age=0") {
+		t.Errorf("expected preview cookie to be cleared, got '%s'", cookie)
+	}
+}
+
+func TestRequestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	debug.SetHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	defer debug.Configure()
+
+	mw := NewRequestLogger(RequestLoggerConfig{})
+	ctx := NewMockContext("GET", "/about?sc_apikey=my-api-key-value")
+	ctx.request.Header.Set("X-Request-ID", "req-123")
+
+	err := mw.Handle(ctx, func(ctx Context) error {
+		ctx.Set(SiteKey, "main")
+		ctx.Set(LocaleKey, "en")
+		debug.Logger("layout").WarnContext(ctx.Request().Context(), "fetch failed", "secret", "s3cr3t-value")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if RequestID(ctx) != "req-123" || ctx.response.Header().Get("X-Request-ID") != "req-123" {
+		t.Errorf("expected request ID 'req-123', got '%s'", RequestID(ctx))
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log records, got %d: %s", len(lines), buf.String())
+	}
+
+	var warning, completed map[string]any
+	json.Unmarshal([]byte(lines[0]), &warning)
+	json.Unmarshal([]byte(lines[1]), &completed)
+
+	if warning["request_id"] != "req-123" || warning["namespace"] != "content-sdk-go/layout" {
+		t.Errorf("expected request attributes on SDK logs, got %v", warning)
+	}
+	if warning["secret"] != debug.Redacted {
+		t.Errorf("expected secret to be redacted, got %v", warning["secret"])
+	}
+	if completed["site"] != "main" || completed["locale"] != "en" || completed["duration"] == nil {
+		t.Errorf("expected site, locale and duration, got %v", completed)
+	}
+	if strings.Contains(buf.String(), "my-api-key-value") {
+		t.Errorf("expected API key to be redacted from the path: %s", buf.String())
+	}
+}
+
+func TestResolutionSourceMetrics(t *testing.T) {
+	multisite := NewMultisiteMiddleware(MultisiteConfig{
+		Enabled:             true,
+		UseCookieResolution: true,
+		Sites:               []models.SiteInfo{{Name: "main", HostName: "example.com"}},
+		DefaultSite:         models.SiteInfo{Name: "main"},
+	})
+	locale := NewLocaleMiddleware(LocaleConfig{
+		DefaultLanguage:    "en",
+		SupportedLanguages: []string{"en", "fr"},
+	})
+
+	siteHost := metrics.SiteResolutions.Value("host")
+	siteQuery := metrics.SiteResolutions.Value("query")
+	siteDefault := metrics.SiteResolutions.Value("default")
+	localePath := metrics.LocaleResolutions.Value("path")
+	localeDefault := metrics.LocaleResolutions.Value("default")
+
+	ctx := NewMockContext("GET", "/fr/about")
+	ctx.request.Host = "example.com"
+	Chain(multisite, locale).Handle(ctx, func(ctx Context) error { return nil })
+
+	ctx = NewMockContext("GET", "/about?site=main")
+	Chain(multisite, locale).Handle(ctx, func(ctx Context) error { return nil })
+
+	// An unknown host falls back to the default site
+	ctx = NewMockContext("GET", "/en/about")
+	ctx.request.Host = "unknown.example.org"
+	Chain(multisite, locale).Handle(ctx, func(ctx Context) error { return nil })
+	if site := ctx.Get(SiteKey); site != "main" {
+		t.Errorf("expected default site 'main', got '%v'", site)
+	}
+
+	if got := metrics.SiteResolutions.Value("host") - siteHost; got != 1 {
+		t.Errorf("expected 1 host resolution, got %v", got)
+	}
+	if got := metrics.SiteResolutions.Value("query") - siteQuery; got != 1 {
+		t.Errorf("expected 1 query resolution, got %v", got)
+	}
+	if got := metrics.SiteResolutions.Value("default") - siteDefault; got != 1 {
+		t.Errorf("expected 1 default site resolution, got %v", got)
+	}
+	if got := metrics.LocaleResolutions.Value("path") - localePath; got != 2 {
+		t.Errorf("expected 2 path locale resolutions, got %v", got)
+	}
+	if got := metrics.LocaleResolutions.Value("default") - localeDefault; got != 1 {
+		t.Errorf("expected 1 default locale resolution, got %v", got)
+	}
+
+	// The metrics handler exposes the counters
+	ctx = NewMockContext("GET", "/metrics")
+	if err := MetricsHandler(nil)(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if contentType := ctx.response.Header().Get("Content-Type"); contentType != metrics.ContentType {
+		t.Errorf("expected Prometheus content type, got '%s'", contentType)
+	}
+	if body := ctx.response.Body.String(); !strings.Contains(body, `content_sdk_site_resolutions_total{source="host"}`) {
+		t.Errorf("expected site resolution metrics, got:\n%s", body)
+	}
+}
+
+// fixedRedirectsService serves a fixed redirect list
+type fixedRedirectsService struct {
+	site.RedirectsService
+	redirects []models.RedirectInfo
+}
+
+func (s *fixedRedirectsService) FetchRedirects(ctx context.Context, siteName string) ([]models.RedirectInfo, error) {
+	return s.redirects, nil
+}
+
+func TestRedirectsMiddleware_URLService(t *testing.T) {
+	service := &fixedRedirectsService{
+		RedirectsService: site.NewRedirectsService(site.RedirectsServiceConfig{}),
+		redirects: []models.RedirectInfo{
+			{Pattern: "/old", Target: "/new?ref=old", RedirectType: models.Redirect301},
+			{Pattern: "/ancien", Target: "/nouveau", RedirectType: models.Redirect302, Locale: "fr"},
+			{Pattern: "/external", Target: "https://example.org/", RedirectType: models.Redirect301},
+		},
+	}
+	redirects := NewRedirectsMiddleware(RedirectsConfig{
+		RedirectsService: service,
+		Site:             "brand-a",
+		URLService:       site.NewURLService(site.URLServiceConfig{}),
+		Sites:            site.NewSiteResolver([]models.SiteInfo{{Name: "brand-a", Language: "en"}}, models.SiteInfo{}),
+	})
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/old", "/brand-a/new?ref=old"},
+		{"/ancien", "/brand-a/fr/nouveau"},
+		{"/external", "https://example.org/"},
+	}
+	for _, tt := range tests {
+		ctx := NewMockContext("GET", tt.path)
+		ctx.Set(SitePathPrefixKey, "/brand-a")
+		redirects.Handle(ctx, func(ctx Context) error { return nil })
+
+		if location := ctx.response.Header().Get("Location"); location != tt.expected {
+			t.Errorf("%s: expected redirect to %s, got %s", tt.path, tt.expected, location)
+		}
+	}
+}
+
+func TestErrorResponseMetrics(t *testing.T) {
+	multisite := NewMultisiteMiddleware(MultisiteConfig{
+		Enabled:     true,
+		Sites:       []models.SiteInfo{{Name: "errors-site", HostName: "errors.example.com"}},
+		DefaultSite: models.SiteInfo{Name: "errors-site"},
+	})
+	chain := Chain(NewErrorResponseMetrics(), multisite)
+	notFound := metrics.ErrorResponses.Value("errors-site", "404")
+	serverError := metrics.ErrorResponses.Value("errors-site", "500")
+	unavailable := metrics.ErrorResponses.Value("errors-site", "503")
+
+	handlers := []HandlerFunc{
+		func(ctx Context) error { return ctx.String(http.StatusNotFound, "Not found") },
+		func(ctx Context) error {
+			ctx.Response().WriteHeader(http.StatusServiceUnavailable)
+			return nil
+		},
+		func(ctx Context) error { return errors.New("render failed") },
+		func(ctx Context) error { return ctx.String(http.StatusOK, "OK") },
+	}
+	for _, handler := range handlers {
+		ctx := NewMockContext("GET", "/about")
+		ctx.request.Host = "errors.example.com"
+		chain.Handle(ctx, handler)
+	}
+
+	// Echo handlers are counted through the Echo response and HTTP errors
+	e := echo.New()
+	for _, handler := range []echo.HandlerFunc{
+		func(c echo.Context) error { return c.String(http.StatusNotFound, "Not found") },
+		func(c echo.Context) error { return echo.NewHTTPError(http.StatusNotFound) },
+	} {
+		request := httptest.NewRequest(http.MethodGet, "/about", nil)
+		request.Host = "errors.example.com"
+		c := e.NewContext(request, httptest.NewRecorder())
+		AdaptMiddlewareToEcho(chain)(handler)(c)
+	}
+
+	if got := metrics.ErrorResponses.Value("errors-site", "404") - notFound; got != 3 {
+		t.Errorf("expected 3 not found responses, got %v", got)
+	}
+	if got := metrics.ErrorResponses.Value("errors-site", "500") - serverError; got != 1 {
+		t.Errorf("expected 1 server error response, got %v", got)
+	}
+	if got := metrics.ErrorResponses.Value("errors-site", "503") - unavailable; got != 1 {
+		t.Errorf("expected 1 service unavailable response, got %v", got)
+	}
+	if got := metrics.ErrorResponses.Value("errors-site", "200"); got != 0 {
+		t.Errorf("expected successful responses not to be counted, got %v", got)
+	}
+}
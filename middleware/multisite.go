@@ -3,9 +3,12 @@ package middleware
 import (
 	"net/http"
 	"strings"
+	"sync/atomic"
 
 	"github.com/guitarrich/content-sdk-go/client"
+	"github.com/guitarrich/content-sdk-go/config"
 	"github.com/guitarrich/content-sdk-go/debug"
+	"github.com/guitarrich/content-sdk-go/metrics"
 	"github.com/guitarrich/content-sdk-go/models"
 	"github.com/guitarrich/content-sdk-go/site"
 )
@@ -15,6 +18,10 @@ type MultisiteConfig struct {
 	// Sites is the list of available sites
 	Sites []models.SiteInfo
 
+	// Resolver overrides the resolver built from Sites (optional).
+	// Use site.NewDynamicSiteResolver to load the site list from Edge at runtime.
+	Resolver site.SiteResolver
+
 	// DefaultSite is the default site to use if no match is found
 	DefaultSite models.SiteInfo
 
@@ -39,8 +46,15 @@ type MultisiteConfig struct {
 
 // MultisiteMiddleware handles multi-site resolution
 type MultisiteMiddleware struct {
-	config   MultisiteConfig
-	resolver site.SiteResolver
+	config MultisiteConfig
+	state  atomic.Pointer[multisiteState]
+}
+
+// multisiteState is the swappable site table used for resolution
+type multisiteState struct {
+	enabled     bool
+	defaultSite models.SiteInfo
+	resolver    site.SiteResolver
 }
 
 // NewMultisiteMiddleware creates a new multisite middleware
@@ -59,17 +73,59 @@ func NewMultisiteMiddleware(config MultisiteConfig) *MultisiteMiddleware {
 		config.CookieSameSite = http.SameSiteNoneMode
 	}
 
-	resolver := site.NewSiteResolver(config.Sites, config.DefaultSite)
+	resolver := config.Resolver
+	if resolver == nil {
+		resolver = site.NewSiteResolver(config.Sites, config.DefaultSite)
+	}
+
+	m := &MultisiteMiddleware{
+		config: config,
+	}
+	m.state.Store(&multisiteState{
+		enabled:     config.Enabled,
+		defaultSite: config.DefaultSite,
+		resolver:    resolver,
+	})
+
+	return m
+}
+
+// SetSites atomically replaces the site table used for resolution.
+// With a dynamic resolver, the sites become the static overrides merged on top of Edge sites.
+func (m *MultisiteMiddleware) SetSites(sites []models.SiteInfo, defaultSite models.SiteInfo) {
+	state := *m.state.Load()
+	state.defaultSite = defaultSite
+	if dynamic, ok := state.resolver.(*site.DynamicSiteResolver); ok {
+		dynamic.SetOverrides(sites, defaultSite)
+	} else {
+		state.resolver = site.NewSiteResolver(sites, defaultSite)
+	}
+	m.state.Store(&state)
+	debug.Multisite("site table updated with %d sites, default=%s", len(sites), defaultSite.Name)
+}
+
+// OnConfigChange implements config.Subscriber and applies the new multisite settings
+func (m *MultisiteMiddleware) OnConfigChange(old, new *config.Config) {
+	m.SetSites(new.Multisite.Sites, new.Multisite.DefaultSite)
 
-	return &MultisiteMiddleware{
-		config:   config,
-		resolver: resolver,
+	state := *m.state.Load()
+	state.enabled = new.Multisite.Enabled
+	m.state.Store(&state)
+	debug.Multisite("multisite config reloaded with %d sites", len(new.Multisite.Sites))
+}
+
+// Sites returns the sites currently known to the resolver, if it can list them
+func (m *MultisiteMiddleware) Sites() []models.SiteInfo {
+	if lister, ok := m.state.Load().resolver.(interface{ Sites() []models.SiteInfo }); ok {
+		return lister.Sites()
 	}
+	return m.config.Sites
 }
 
 // Handle processes the multisite middleware
 func (m *MultisiteMiddleware) Handle(ctx Context, next HandlerFunc) error {
-	if !m.config.Enabled {
+	state := m.state.Load()
+	if !state.enabled {
 		debug.Multisite("multisite disabled, skipping")
 		return next(ctx)
 	}
@@ -80,45 +136,64 @@ func (m *MultisiteMiddleware) Handle(ctx Context, next HandlerFunc) error {
 
 	debug.Multisite("processing multisite for hostname=%s, path=%s", hostname, path)
 
-	// Determine site name
-	var siteName string
+	// Determine site name and where it came from
+	var siteName, source string
 	var siteInfo *models.SiteInfo
 
 	// Check for site query parameter first (for preview mode)
 	siteParam := ctx.Request().URL.Query().Get("site")
 	if siteParam != "" {
 		debug.Multisite("site from query param: %s", siteParam)
-		siteName = siteParam
-		siteInfo, _ = m.resolver.GetByName(siteName)
+		siteName, source = siteParam, "query"
+		siteInfo, _ = state.resolver.GetByName(siteName)
 	}
 
 	// Check for site cookie (if enabled)
 	if siteName == "" && m.config.UseCookieResolution {
 		if cookie, err := ctx.Cookie(m.config.CookieName); err == nil && cookie != nil {
 			debug.Multisite("site from cookie: %s", cookie.Value)
-			siteName = cookie.Value
-			siteInfo, _ = m.resolver.GetByName(siteName)
+			siteName, source = cookie.Value, "cookie"
+			siteInfo, _ = state.resolver.GetByName(siteName)
 		}
 	}
 
-	// Resolve by hostname
+	// Resolve by hostname (and path prefix when supported)
+	sitePath := path
 	if siteName == "" {
 		debug.Multisite("resolving site by hostname: %s", hostname)
-		siteInfo, _ = m.resolver.GetByHost(hostname)
+		if matcher, ok := state.resolver.(site.HostMatcher); ok {
+			// A nil match falls through to the default site below
+			siteInfo, sitePath = matcher.MatchHostAndPath(hostname, path)
+		} else if pathResolver, ok := state.resolver.(site.PathSiteResolver); ok {
+			siteInfo, sitePath, _ = pathResolver.GetByHostAndPath(hostname, path)
+		} else {
+			siteInfo, _ = state.resolver.GetByHost(m.normalizeHostname(hostname))
+		}
 		if siteInfo != nil {
-			siteName = siteInfo.Name
+			siteName, source = siteInfo.Name, "host"
 		}
+	} else if siteInfo != nil {
+		sitePath, _ = site.StripSitePrefix(*siteInfo, path)
 	}
 
 	// Fallback to default site
 	if siteName == "" {
-		siteName = m.config.DefaultSite.Name
-		siteInfo = &m.config.DefaultSite
+		siteName, source = state.defaultSite.Name, "default"
+		siteInfo = &state.defaultSite
 		debug.Multisite("using default site: %s", siteName)
 	}
 
+	// Strip the site's path prefix so downstream handlers see the site-relative path
+	if sitePath != path {
+		prefix := strings.TrimSuffix(strings.TrimSuffix(path, strings.TrimPrefix(sitePath, "/")), "/")
+		ctx.Set(SitePathPrefixKey, prefix)
+		ctx.SetPath(sitePath)
+		debug.Multisite("stripped site path prefix %s, path=%s", prefix, sitePath)
+	}
+
 	// Store site in context
 	ctx.Set(SiteKey, siteName)
+	metrics.SiteResolutions.Inc(source)
 
 	// Set site cookie
 	ctx.SetCookie(&http.Cookie{
@@ -130,8 +205,8 @@ func (m *MultisiteMiddleware) Handle(ctx Context, next HandlerFunc) error {
 		SameSite: m.config.CookieSameSite,
 	})
 
-	// Rewrite the path to include site prefix
-	rewritePath := client.GetSiteRewrite(path, siteName)
+	// Rewrite the path to include site prefix, without the site's path prefix
+	rewritePath := client.GetSiteRewrite(sitePath, siteName)
 	ctx.Set(RewritePathKey, rewritePath)
 	ctx.Set(OriginalPathKey, path)
 
@@ -140,17 +215,18 @@ func (m *MultisiteMiddleware) Handle(ctx Context, next HandlerFunc) error {
 	return next(ctx)
 }
 
-// getHostname extracts hostname from request
+// getHostname extracts hostname (including port) from request
 func (m *MultisiteMiddleware) getHostname(ctx Context) string {
 	req := ctx.Request()
 
-	// Try X-Forwarded-Host first (for proxies)
+	// Try X-Forwarded-Host first (for proxies); use the first host if several are listed
 	if host := req.Header.Get("X-Forwarded-Host"); host != "" {
-		return m.normalizeHostname(host)
+		first, _, _ := strings.Cut(host, ",")
+		return strings.TrimSpace(first)
 	}
 
 	// Use Host header
-	return m.normalizeHostname(req.Host)
+	return req.Host
 }
 
 // normalizeHostname removes port from hostname
@@ -3,10 +3,15 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"strings"
+	"sync"
 
+	"github.com/guitarrich/content-sdk-go/config"
 	"github.com/guitarrich/content-sdk-go/debug"
+	"github.com/guitarrich/content-sdk-go/metrics"
 	"github.com/guitarrich/content-sdk-go/models"
 	"github.com/guitarrich/content-sdk-go/site"
+	"github.com/guitarrich/content-sdk-go/telemetry"
 )
 
 // RedirectsConfig contains configuration for redirects middleware
@@ -20,11 +25,19 @@ type RedirectsConfig struct {
 	// RefreshInterval is how often to refresh redirects (in seconds)
 	// Set to 0 to disable auto-refresh
 	RefreshInterval int
+
+	// URLService builds the redirect URLs of site-relative targets, adding the
+	// site path prefix and the redirect's locale prefix (optional)
+	URLService site.URLService
+
+	// Sites resolves the default language of the site for URLService (optional)
+	Sites site.SiteResolver
 }
 
 // RedirectsMiddleware handles URL redirects
 type RedirectsMiddleware struct {
 	config    RedirectsConfig
+	mu        sync.RWMutex
 	redirects []models.RedirectInfo
 }
 
@@ -43,16 +56,23 @@ func (m *RedirectsMiddleware) Handle(ctx Context, next HandlerFunc) error {
 	debug.Redirects("checking redirects for path=%s", path)
 
 	// Load redirects if not already loaded
-	if m.redirects == nil {
-		if err := m.loadRedirects(ctx); err != nil {
+	m.mu.RLock()
+	redirects := m.redirects
+	m.mu.RUnlock()
+	telemetry.RecordCacheLookup(ctx.Request().Context(), "redirects", redirects != nil)
+	metrics.RecordCacheLookup("redirects", redirects != nil)
+	if redirects == nil {
+		loaded, err := m.loadRedirects(ctx)
+		if err != nil {
 			debug.Redirects("failed to load redirects: %v", err)
 			// Continue without redirects
 			return next(ctx)
 		}
+		redirects = loaded
 	}
 
 	// Check for matching redirect
-	redirect, err := m.config.RedirectsService.GetRedirect(path, m.redirects)
+	redirect, err := m.config.RedirectsService.GetRedirect(path, redirects)
 	if err != nil {
 		debug.Redirects("error checking redirect: %v", err)
 		return next(ctx)
@@ -64,14 +84,17 @@ func (m *RedirectsMiddleware) Handle(ctx Context, next HandlerFunc) error {
 	}
 
 	debug.Redirects("redirect found: %s -> %s (type=%s)", path, redirect.Target, redirect.RedirectType)
+	siteName, _ := ctx.Get(SiteKey).(string)
+	telemetry.RecordRedirectHit(ctx.Request().Context(), siteName, string(redirect.RedirectType))
+	metrics.Redirects.Inc(string(redirect.RedirectType))
 
 	// Apply redirect based on type
 	switch redirect.RedirectType {
 	case models.Redirect301:
-		return ctx.Redirect(http.StatusMovedPermanently, redirect.Target)
+		return ctx.Redirect(http.StatusMovedPermanently, m.targetURL(ctx, redirect))
 
 	case models.Redirect302:
-		return ctx.Redirect(http.StatusFound, redirect.Target)
+		return ctx.Redirect(http.StatusFound, m.targetURL(ctx, redirect))
 
 	case models.RedirectServerTransfer:
 		// Server transfer: rewrite the path and continue
@@ -82,12 +105,64 @@ func (m *RedirectsMiddleware) Handle(ctx Context, next HandlerFunc) error {
 
 	default:
 		// Unknown redirect type, use 302
-		return ctx.Redirect(http.StatusFound, redirect.Target)
+		return ctx.Redirect(http.StatusFound, m.targetURL(ctx, redirect))
+	}
+}
+
+// targetURL returns the URL to redirect to. Site-relative targets are built
+// with the URL service when one is configured.
+func (m *RedirectsMiddleware) targetURL(ctx Context, redirect *models.RedirectInfo) string {
+	if m.config.URLService == nil || !strings.HasPrefix(redirect.Target, "/") || strings.HasPrefix(redirect.Target, "//") {
+		return redirect.Target
+	}
+
+	siteInfo := models.SiteInfo{Name: m.config.Site}
+	if siteName, ok := ctx.Get(SiteKey).(string); ok && siteName != "" {
+		siteInfo.Name = siteName
+	}
+	if m.config.Sites != nil {
+		if resolved, err := m.config.Sites.GetByName(siteInfo.Name); err == nil && resolved != nil {
+			siteInfo.Language = resolved.Language
+		}
+	}
+	// The path prefix the multisite middleware stripped from the request
+	siteInfo.VirtualFolder, _ = ctx.Get(SitePathPrefixKey).(string)
+
+	path, suffix := redirect.Target, ""
+	if idx := strings.IndexAny(path, "?#"); idx >= 0 {
+		path, suffix = path[:idx], path[idx:]
 	}
+	return m.config.URLService.Path(siteInfo, path, redirect.Locale) + suffix
+}
+
+// Invalidate drops the loaded redirects so they are fetched again on the next request
+func (m *RedirectsMiddleware) Invalidate() {
+	m.mu.Lock()
+	m.redirects = nil
+	m.mu.Unlock()
+	debug.Redirects("redirects invalidated")
+}
+
+// OnConfigChange implements config.Subscriber and reloads redirects after a config change
+func (m *RedirectsMiddleware) OnConfigChange(old, new *config.Config) {
+	m.Invalidate()
+}
+
+// Loaded reports whether redirects are loaded
+func (m *RedirectsMiddleware) Loaded() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.redirects != nil
+}
+
+// Warm loads the redirects of the configured site ahead of the first request
+func (m *RedirectsMiddleware) Warm(ctx context.Context) error {
+	_, err := m.fetchRedirects(ctx, m.config.Site)
+	return err
 }
 
 // loadRedirects loads redirects from the service
-func (m *RedirectsMiddleware) loadRedirects(ctx Context) error {
+func (m *RedirectsMiddleware) loadRedirects(ctx Context) ([]models.RedirectInfo, error) {
 	// Get site from context if available
 	site := m.config.Site
 	if siteFromCtx := ctx.Get(SiteKey); siteFromCtx != nil {
@@ -96,13 +171,23 @@ func (m *RedirectsMiddleware) loadRedirects(ctx Context) error {
 		}
 	}
 
-	// Fetch redirects
-	redirects, err := m.config.RedirectsService.FetchRedirects(context.Background(), site)
+	return m.fetchRedirects(context.Background(), site)
+}
+
+// fetchRedirects fetches and stores the redirects of a site
+func (m *RedirectsMiddleware) fetchRedirects(ctx context.Context, site string) ([]models.RedirectInfo, error) {
+	redirects, err := m.config.RedirectsService.FetchRedirects(ctx, site)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if redirects == nil {
+		// Mark an empty result as loaded
+		redirects = []models.RedirectInfo{}
 	}
 
+	m.mu.Lock()
 	m.redirects = redirects
+	m.mu.Unlock()
 	debug.Redirects("loaded %d redirects for site %s", len(redirects), site)
-	return nil
+	return redirects, nil
 }
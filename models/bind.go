@@ -0,0 +1,482 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// BindError reports the fields that could not be bound by Bind or BindParams
+type BindError struct {
+	Errors []*ValidationError
+}
+
+func (e *BindError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Field + ": " + err.Message
+	}
+	return "bind failed: " + strings.Join(messages, "; ")
+}
+
+// Bind fills the struct pointed to by dst from component fields using `sc` struct tags.
+//
+//	type HeroProps struct {
+//		Title models.TextField   `sc:"Title"`
+//		Image *models.ImageField `sc:"Image,optional"`
+//		Cards []CardProps        `sc:"Cards"`
+//	}
+//
+//	var props HeroProps
+//	err := models.Bind(rendering.Fields, &props)
+//
+// Supported types are the typed fields in this package (by value or pointer),
+// ItemReference, ItemReferenceList, string, bool, integers, floats, time.Time and any.
+// Structs, struct pointers and struct slices are bound from the fields of referenced
+// items, where the tags "@id", "@name", "@displayName" and "@url" bind item properties.
+// Fields without an `sc` tag are ignored. Missing fields are errors unless tagged
+// "optional". All errors are collected and returned as a *BindError.
+func Bind(fields any, dst any) error {
+	target, err := bindTarget(dst)
+	if err != nil {
+		return err
+	}
+	fieldsMap, err := toStringMap(fields)
+	if err != nil {
+		return err
+	}
+
+	b := &binder{}
+	b.bindStruct(target, fieldsMap, nil, "")
+	return b.result()
+}
+
+// BindParams fills the struct pointed to by dst from rendering parameters using `sc` struct tags.
+// Params can be any map with string keys (e.g. map[string]string or *ComponentParams).
+// Supported types are string, bool ("1"/"true"), integers, floats and any.
+func BindParams(params any, dst any) error {
+	target, err := bindTarget(dst)
+	if err != nil {
+		return err
+	}
+	paramsMap, err := toStringMap(params)
+	if err != nil {
+		return err
+	}
+
+	b := &binder{}
+	for _, tf := range taggedFields(target) {
+		value, exists := paramsMap[tf.name]
+		if !exists || value == nil || value == "" {
+			if !tf.optional {
+				b.fail(tf.name, "missing parameter")
+			}
+			continue
+		}
+		b.bindScalar(tf.value, value, tf.name)
+	}
+	return b.result()
+}
+
+// binder collects errors while binding
+type binder struct {
+	errs []*ValidationError
+}
+
+// taggedField is a struct field with an `sc` tag
+type taggedField struct {
+	name     string
+	optional bool
+	value    reflect.Value
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+
+	// fieldExtractors maps typed field types to extractors that validate the field shape
+	fieldExtractors = map[reflect.Type]func(data any) (any, error){
+		reflect.TypeOf(TextField{}): func(data any) (any, error) {
+			return ExtractTextFieldFromMap(data), expectKind(data, "a text", isString)
+		},
+		reflect.TypeOf(RichTextField{}): func(data any) (any, error) {
+			return ExtractRichTextFieldFromMap(data), expectKind(data, "a rich text", isString)
+		},
+		reflect.TypeOf(ImageField{}): func(data any) (any, error) {
+			return ExtractImageFieldFromMap(data), expectKind(data, "an image", isObject)
+		},
+		reflect.TypeOf(LinkField{}): func(data any) (any, error) {
+			return ExtractLinkFieldFromMap(data), expectKind(data, "a link", isObject)
+		},
+		reflect.TypeOf(FileField{}): func(data any) (any, error) {
+			return ExtractFileFieldFromMap(data), expectKind(data, "a file", isObject)
+		},
+		reflect.TypeOf(DateField{}): func(data any) (any, error) {
+			field := ExtractDateFieldFromMap(data)
+			if err := expectKind(data, "a date", isString); err != nil {
+				return field, err
+			}
+			if _, err := field.Time(); field.Value != "" && err != nil {
+				return field, err
+			}
+			return field, nil
+		},
+		reflect.TypeOf(NumberField{}): func(data any) (any, error) {
+			return ExtractNumberFieldFromMap(data), expectKind(data, "a number", isFloat)
+		},
+		reflect.TypeOf(IntegerField{}): func(data any) (any, error) {
+			return ExtractIntegerFieldFromMap(data), expectKind(data, "an integer", isInt)
+		},
+		reflect.TypeOf(CheckboxField{}): func(data any) (any, error) {
+			return ExtractCheckboxFieldFromMap(data), expectKind(data, "a checkbox", isBool)
+		},
+		reflect.TypeOf(ItemReference{}): func(data any) (any, error) {
+			return ExtractItemReferenceFromMap(data), nil
+		},
+		reflect.TypeOf(ItemReferenceList{}): func(data any) (any, error) {
+			return ExtractItemReferenceListFromMap(data), nil
+		},
+	}
+)
+
+// bindStruct binds the tagged fields of a struct; item is set for referenced items
+func (b *binder) bindStruct(v reflect.Value, fields map[string]any, item *ItemReference, prefix string) {
+	for _, tf := range taggedFields(v) {
+		path := prefix + tf.name
+
+		if strings.HasPrefix(tf.name, "@") {
+			b.bindItemProperty(tf.value, tf.name, item, path)
+			continue
+		}
+
+		data, exists := fields[tf.name]
+		if !exists || data == nil {
+			if !tf.optional {
+				b.fail(path, "missing field")
+			}
+			continue
+		}
+
+		b.bindValue(tf.value, data, path)
+	}
+}
+
+// bindValue binds field data to a struct field based on its type
+func (b *binder) bindValue(v reflect.Value, data any, path string) {
+	t := v.Type()
+	isPointer := t.Kind() == reflect.Pointer
+	if isPointer {
+		t = t.Elem()
+	}
+
+	// Typed fields
+	if extract, ok := fieldExtractors[t]; ok {
+		field, err := extract(data)
+		if err != nil {
+			b.fail(path, err.Error())
+			return
+		}
+		value := reflect.ValueOf(field)
+		if isPointer {
+			v.Set(value)
+		} else {
+			v.Set(value.Elem())
+		}
+		return
+	}
+
+	switch {
+	case t.Kind() == reflect.Struct && t != timeType:
+		// Single referenced item
+		reference := ExtractItemReferenceFromMap(data)
+		target := reflect.New(t)
+		if !b.bindReference(target.Elem(), reference, path) {
+			return
+		}
+		if isPointer {
+			v.Set(target)
+		} else {
+			v.Set(target.Elem())
+		}
+	case t.Kind() == reflect.Slice && !isPointer:
+		b.bindList(v, data, path)
+	case isPointer:
+		b.fail(path, fmt.Sprintf("unsupported type %s", v.Type()))
+	default:
+		b.bindScalar(v, data, path)
+	}
+}
+
+// bindList binds a multi-item reference field to a slice of structs or item references
+func (b *binder) bindList(v reflect.Value, data any, path string) {
+	elemType := v.Type().Elem()
+	isPointer := elemType.Kind() == reflect.Pointer
+	if isPointer {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct || elemType == timeType {
+		b.fail(path, fmt.Sprintf("unsupported type %s", v.Type()))
+		return
+	}
+
+	list := ExtractItemReferenceListFromMap(data)
+	slice := reflect.MakeSlice(v.Type(), 0, len(list.Items))
+
+	for i, reference := range list.Items {
+		elem := reflect.New(elemType)
+		if elemType == reflect.TypeOf(ItemReference{}) {
+			elem = reflect.ValueOf(reference)
+		} else if !b.bindReference(elem.Elem(), reference, fmt.Sprintf("%s[%d]", path, i)) {
+			continue
+		}
+
+		if isPointer {
+			slice = reflect.Append(slice, elem)
+		} else {
+			slice = reflect.Append(slice, elem.Elem())
+		}
+	}
+
+	v.Set(slice)
+}
+
+// bindReference binds the fields of a referenced item into a struct
+func (b *binder) bindReference(v reflect.Value, reference *ItemReference, path string) bool {
+	if reference.IsEmpty() {
+		b.fail(path, "expected a referenced item")
+		return false
+	}
+	if !reference.IsExpanded() {
+		b.fail(path, fmt.Sprintf("referenced item %s is not expanded", reference.ID))
+		return false
+	}
+	b.bindStruct(v, reference.Fields, reference, path+".")
+	return true
+}
+
+// bindItemProperty binds a property of the referenced item ("@id", "@name", "@displayName", "@url")
+func (b *binder) bindItemProperty(v reflect.Value, name string, item *ItemReference, path string) {
+	if item == nil {
+		b.fail(path, "item properties can only be bound on referenced items")
+		return
+	}
+	if v.Kind() != reflect.String {
+		b.fail(path, fmt.Sprintf("item property must be bound to a string, got %s", v.Type()))
+		return
+	}
+
+	switch name {
+	case "@id":
+		v.SetString(item.ID)
+	case "@name":
+		v.SetString(item.Name)
+	case "@displayName":
+		v.SetString(item.DisplayName)
+	case "@url":
+		v.SetString(item.URL)
+	default:
+		b.fail(path, "unknown item property")
+	}
+}
+
+// bindScalar binds a field or parameter value to a Go scalar type
+func (b *binder) bindScalar(v reflect.Value, data any, path string) {
+	raw := fieldValue(data)
+
+	switch {
+	case v.Type() == timeType:
+		field := ExtractDateFieldFromMap(data)
+		t, err := field.Time()
+		if err != nil && field.Value != "" {
+			b.fail(path, err.Error())
+			return
+		}
+		v.Set(reflect.ValueOf(t))
+		return
+	case v.Kind() == reflect.Interface:
+		if !reflect.TypeOf(data).AssignableTo(v.Type()) {
+			b.fail(path, fmt.Sprintf("cannot assign %T to %s", data, v.Type()))
+			return
+		}
+		v.Set(reflect.ValueOf(data))
+		return
+	case raw == nil || raw == "":
+		// Empty values leave the zero value
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		if err := expectKind(data, "a text", isString); err != nil {
+			b.fail(path, err.Error())
+			return
+		}
+		str, _ := raw.(string)
+		v.SetString(str)
+	case reflect.Bool:
+		if err := expectKind(data, "a checkbox", isBool); err != nil {
+			b.fail(path, err.Error())
+			return
+		}
+		v.SetBool(toBool(raw))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number, ok := toInt(raw)
+		if !ok || v.OverflowInt(number) {
+			b.fail(path, fmt.Sprintf("expected an integer value, got %v", raw))
+			return
+		}
+		v.SetInt(number)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		number, ok := toInt(raw)
+		if !ok || number < 0 || v.OverflowUint(uint64(number)) {
+			b.fail(path, fmt.Sprintf("expected a non-negative integer value, got %v", raw))
+			return
+		}
+		v.SetUint(uint64(number))
+	case reflect.Float32, reflect.Float64:
+		number, ok := toFloat(raw)
+		if !ok || v.OverflowFloat(number) {
+			b.fail(path, fmt.Sprintf("expected a number value, got %v", raw))
+			return
+		}
+		v.SetFloat(number)
+	default:
+		b.fail(path, fmt.Sprintf("unsupported type %s", v.Type()))
+	}
+}
+
+// fail records a binding error
+func (b *binder) fail(path, message string) {
+	b.errs = append(b.errs, &ValidationError{Field: path, Message: message})
+}
+
+// result returns the collected errors as a *BindError, or nil
+func (b *binder) result() error {
+	if len(b.errs) == 0 {
+		return nil
+	}
+	return &BindError{Errors: b.errs}
+}
+
+// bindTarget validates that dst is a non-nil pointer to a struct
+func bindTarget(dst any) (reflect.Value, error) {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("bind target must be a non-nil pointer to a struct, got %T", dst)
+	}
+	return v.Elem(), nil
+}
+
+// taggedFields returns the exported struct fields with an `sc` tag
+func taggedFields(v reflect.Value) []taggedField {
+	var fields []taggedField
+
+	t := v.Type()
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup("sc")
+		if !ok || tag == "-" || !sf.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		tf := taggedField{name: name, value: v.Field(i)}
+		for option := range strings.SplitSeq(options, ",") {
+			if strings.TrimSpace(option) == "optional" {
+				tf.optional = true
+			}
+		}
+		fields = append(fields, tf)
+	}
+
+	return fields
+}
+
+// toStringMap converts any map with string keys (including named map types and pointers to them)
+func toStringMap(data any) (map[string]any, error) {
+	if data == nil {
+		return map[string]any{}, nil
+	}
+	if m, ok := data.(map[string]any); ok {
+		return m, nil
+	}
+
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return map[string]any{}, nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return nil, fmt.Errorf("fields must be a map with string keys, got %T", data)
+	}
+
+	m := make(map[string]any, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		m[iter.Key().String()] = iter.Value().Interface()
+	}
+	return m, nil
+}
+
+// fieldValue returns the raw value of field data, unwrapping jsonValue/value
+// Maps without a value (e.g. image properties) are returned as is
+func fieldValue(data any) any {
+	fieldMap, ok := data.(map[string]any)
+	if !ok {
+		return data
+	}
+	if _, ok := fieldMap["jsonValue"]; !ok {
+		if _, ok := fieldMap["value"]; !ok {
+			return fieldMap
+		}
+	}
+	value, _ := unwrapFieldValue(fieldMap)
+	return value
+}
+
+// expectKind checks the raw value of field data with check; nil values always pass
+func expectKind(data any, kind string, check func(any) bool) error {
+	value := fieldValue(data)
+	if value == nil || check(value) {
+		return nil
+	}
+	return fmt.Errorf("expected %s value, got %T", kind, value)
+}
+
+func isString(value any) bool {
+	_, ok := value.(string)
+	return ok
+}
+
+func isObject(value any) bool {
+	_, ok := value.(map[string]any)
+	return ok
+}
+
+func isFloat(value any) bool {
+	if value == "" {
+		return true
+	}
+	_, ok := toFloat(value)
+	return ok
+}
+
+func isInt(value any) bool {
+	if value == "" {
+		return true
+	}
+	_, ok := toInt(value)
+	return ok
+}
+
+func isBool(value any) bool {
+	switch v := value.(type) {
+	case bool, float64:
+		return true
+	case string:
+		return v == "" || v == "0" || v == "1" || strings.EqualFold(v, "true") || strings.EqualFold(v, "false")
+	}
+	return false
+}
@@ -0,0 +1,410 @@
+package models_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	layoutservice "github.com/guitarrich/content-sdk-go/layoutService"
+	"github.com/guitarrich/content-sdk-go/models"
+)
+
+// bindErrors returns the messages of a *BindError by field path
+func bindErrors(t *testing.T, err error) map[string]string {
+	t.Helper()
+	var bindErr *models.BindError
+	if !errors.As(err, &bindErr) {
+		t.Fatalf("expected *BindError, got %v", err)
+	}
+	messages := map[string]string{}
+	for _, e := range bindErr.Errors {
+		messages[e.Field] = e.Message
+	}
+	return messages
+}
+
+func TestBind_TypedFields(t *testing.T) {
+	type props struct {
+		Title    models.TextField      `sc:"Title"`
+		Body     *models.RichTextField `sc:"Body"`
+		Image    *models.ImageField    `sc:"Image"`
+		Link     models.LinkField      `sc:"Link"`
+		Date     models.DateField      `sc:"Date"`
+		Price    *models.NumberField   `sc:"Price"`
+		Count    models.IntegerField   `sc:"Count"`
+		Featured models.CheckboxField  `sc:"Featured"`
+		Ignored  string
+	}
+
+	fields := map[string]any{
+		"Title":    map[string]any{"value": "Hello"},
+		"Body":     map[string]any{"jsonValue": map[string]any{"value": "<p>Body</p>"}},
+		"Image":    map[string]any{"value": map[string]any{"src": "/-/media/hero.jpg", "alt": "Hero"}},
+		"Link":     map[string]any{"value": map[string]any{"href": "/about", "linktype": "internal"}},
+		"Date":     map[string]any{"value": "20240101T000000Z"},
+		"Price":    map[string]any{"value": 9.5},
+		"Count":    map[string]any{"value": "3"},
+		"Featured": map[string]any{"value": "1"},
+	}
+
+	var p props
+	if err := models.Bind(fields, &p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if p.Title.Value != "Hello" {
+		t.Errorf("expected title 'Hello', got '%s'", p.Title.Value)
+	}
+	if p.Body == nil || p.Body.Value != "<p>Body</p>" {
+		t.Errorf("expected rich text body, got %+v", p.Body)
+	}
+	if p.Image == nil || p.Image.GetSrc() != "/-/media/hero.jpg" || p.Image.GetAlt() != "Hero" {
+		t.Errorf("expected image, got %+v", p.Image)
+	}
+	if p.Link.GetHref() != "/about" {
+		t.Errorf("expected link '/about', got '%s'", p.Link.GetHref())
+	}
+	if date, err := p.Date.Time(); err != nil || !date.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected 2024-01-01, got %v (%v)", date, err)
+	}
+	if p.Price == nil || p.Price.Float() != 9.5 {
+		t.Errorf("expected price 9.5, got %+v", p.Price)
+	}
+	if p.Count.Int() != 3 {
+		t.Errorf("expected count 3, got %d", p.Count.Int())
+	}
+	if !p.Featured.Value {
+		t.Error("expected featured to be checked")
+	}
+}
+
+func TestBind_Scalars(t *testing.T) {
+	type props struct {
+		Title     string    `sc:"Title"`
+		Enabled   bool      `sc:"Enabled"`
+		Count     int       `sc:"Count"`
+		Size      uint      `sc:"Size"`
+		Ratio     float64   `sc:"Ratio"`
+		Published time.Time `sc:"Published"`
+		Raw       any       `sc:"Raw"`
+	}
+
+	fields := map[string]any{
+		"Title":     map[string]any{"value": "Hello"},
+		"Enabled":   map[string]any{"value": true},
+		"Count":     map[string]any{"jsonValue": map[string]any{"value": 7}},
+		"Size":      "12",
+		"Ratio":     map[string]any{"value": "0.25"},
+		"Published": map[string]any{"value": "2024-06-01T12:00:00Z"},
+		"Raw":       map[string]any{"value": "anything"},
+	}
+
+	var p props
+	if err := models.Bind(fields, &p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if p.Title != "Hello" || !p.Enabled || p.Count != 7 || p.Size != 12 || p.Ratio != 0.25 {
+		t.Errorf("unexpected scalars: %+v", p)
+	}
+	if p.Published.Year() != 2024 || p.Published.Month() != time.June {
+		t.Errorf("expected June 2024, got %v", p.Published)
+	}
+	if !reflect.DeepEqual(p.Raw, fields["Raw"]) {
+		t.Errorf("expected raw field data, got %v", p.Raw)
+	}
+}
+
+func TestBind_OptionalAndMissing(t *testing.T) {
+	type props struct {
+		Title    models.TextField   `sc:"Title"`
+		Subtitle models.TextField   `sc:"Subtitle,optional"`
+		Image    *models.ImageField `sc:"Image,optional"`
+		Link     *models.LinkField  `sc:"Link"`
+		Count    int                `sc:"Count"`
+	}
+
+	var p props
+	err := models.Bind(map[string]any{
+		"Title": map[string]any{"value": "Hello"},
+		"Link":  nil,
+	}, &p)
+
+	messages := bindErrors(t, err)
+	expected := map[string]string{
+		"Link":  "missing field",
+		"Count": "missing field",
+	}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("expected errors %v, got %v", expected, messages)
+	}
+	if !strings.HasPrefix(err.Error(), "bind failed: ") || !strings.Contains(err.Error(), "Link: missing field") {
+		t.Errorf("unexpected error message: %s", err)
+	}
+
+	// Bound fields are kept and optional fields are left as zero values
+	if p.Title.Value != "Hello" {
+		t.Errorf("expected title to be bound despite errors, got '%s'", p.Title.Value)
+	}
+	if p.Subtitle.Value != "" || p.Image != nil {
+		t.Errorf("expected optional fields to be zero, got %+v", p)
+	}
+}
+
+func TestBind_MistypedValues(t *testing.T) {
+	type props struct {
+		Count    models.IntegerField  `sc:"Count"`
+		Title    models.TextField     `sc:"Title"`
+		Price    models.NumberField   `sc:"Price"`
+		Featured models.CheckboxField `sc:"Featured"`
+		Image    models.ImageField    `sc:"Image"`
+		Date     models.DateField     `sc:"Date"`
+		Name     string               `sc:"Name"`
+		Small    int8                 `sc:"Small"`
+		Size     uint8                `sc:"Size"`
+		Negative uint                 `sc:"Negative"`
+	}
+
+	var p props
+	err := models.Bind(map[string]any{
+		"Count":    map[string]any{"value": "three"},
+		"Title":    map[string]any{"value": map[string]any{"text": "Hello"}},
+		"Price":    map[string]any{"value": "cheap"},
+		"Featured": map[string]any{"value": "yes"},
+		"Image":    map[string]any{"value": "/-/media/hero.jpg"},
+		"Date":     map[string]any{"value": "yesterday"},
+		"Name":     map[string]any{"value": 42.0},
+		"Small":    map[string]any{"value": 300},
+		"Size":     map[string]any{"value": "256"},
+		"Negative": map[string]any{"value": -1},
+	}, &p)
+
+	messages := bindErrors(t, err)
+	expected := map[string]string{
+		"Count":    "expected an integer value, got string",
+		"Title":    "expected a text value, got map[string]interface {}",
+		"Price":    "expected a number value, got string",
+		"Featured": "expected a checkbox value, got string",
+		"Image":    "expected an image value, got string",
+		"Name":     "expected a text value, got float64",
+		"Small":    "expected an integer value, got 300",
+		"Size":     "expected a non-negative integer value, got 256",
+		"Negative": "expected a non-negative integer value, got -1",
+	}
+	for field, message := range expected {
+		if messages[field] != message {
+			t.Errorf("%s: expected '%s', got '%s'", field, message, messages[field])
+		}
+	}
+	if _, ok := messages["Date"]; !ok {
+		t.Error("expected an error for an unparseable date")
+	}
+	if len(messages) != len(expected)+1 {
+		t.Errorf("expected %d errors, got %v", len(expected)+1, messages)
+	}
+}
+
+func TestBind_References(t *testing.T) {
+	type author struct {
+		ID   string           `sc:"@id"`
+		URL  string           `sc:"@url"`
+		Name models.TextField `sc:"Name"`
+	}
+	type card struct {
+		Title string `sc:"Title"`
+	}
+	type props struct {
+		Author  author                    `sc:"Author"`
+		Editor  *author                   `sc:"Editor"`
+		Cards   []card                    `sc:"Cards"`
+		Related []*card                   `sc:"Related"`
+		Tags    []models.ItemReference    `sc:"Tags"`
+		Links   models.ItemReferenceList  `sc:"Links"`
+		Parent  *models.ItemReference     `sc:"Parent"`
+		Others  *models.ItemReferenceList `sc:"Others,optional"`
+	}
+
+	fields := map[string]any{
+		"Author": map[string]any{
+			"id":     "{A1}",
+			"url":    "/authors/sam",
+			"fields": map[string]any{"Name": map[string]any{"value": "Sam"}},
+		},
+		// GraphQL shape: url { path } and fields [{ name, jsonValue }]
+		"Editor": map[string]any{"jsonValue": map[string]any{
+			"id":     "{E1}",
+			"url":    map[string]any{"path": "/authors/alex"},
+			"fields": []any{map[string]any{"name": "Name", "jsonValue": map[string]any{"value": "Alex"}}},
+		}},
+		"Cards": []any{
+			map[string]any{"id": "{C1}", "fields": map[string]any{"Title": map[string]any{"value": "One"}}},
+			map[string]any{"id": "{C2}", "fields": map[string]any{"Title": map[string]any{"value": "Two"}}},
+		},
+		"Related": map[string]any{"targetItems": []any{
+			map[string]any{"id": "{R1}", "fields": map[string]any{"Title": map[string]any{"value": "Related"}}},
+		}},
+		"Tags":   "{T1}|{T2}",
+		"Links":  map[string]any{"value": "{L1}"},
+		"Parent": map[string]any{"value": "{P1}"},
+	}
+
+	var p props
+	if err := models.Bind(fields, &p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if p.Author != (author{ID: "{A1}", URL: "/authors/sam", Name: models.TextField{Value: "Sam"}}) {
+		t.Errorf("unexpected author: %+v", p.Author)
+	}
+	if p.Editor == nil || p.Editor.ID != "{E1}" || p.Editor.URL != "/authors/alex" || p.Editor.Name.Value != "Alex" {
+		t.Errorf("unexpected editor: %+v", p.Editor)
+	}
+	if !reflect.DeepEqual(p.Cards, []card{{Title: "One"}, {Title: "Two"}}) {
+		t.Errorf("unexpected cards: %+v", p.Cards)
+	}
+	if len(p.Related) != 1 || p.Related[0].Title != "Related" {
+		t.Errorf("unexpected related cards: %+v", p.Related)
+	}
+	// Item references bind without expansion
+	if len(p.Tags) != 2 || p.Tags[0].ID != "{T1}" || p.Tags[1].ID != "{T2}" {
+		t.Errorf("unexpected tags: %+v", p.Tags)
+	}
+	if !reflect.DeepEqual(p.Links.IDs(), []string{"{L1}"}) {
+		t.Errorf("unexpected links: %+v", p.Links)
+	}
+	if p.Parent == nil || p.Parent.ID != "{P1}" || p.Parent.IsExpanded() {
+		t.Errorf("unexpected parent: %+v", p.Parent)
+	}
+	if p.Others != nil {
+		t.Errorf("expected missing optional list to stay nil, got %+v", p.Others)
+	}
+}
+
+func TestBind_ReferenceErrors(t *testing.T) {
+	type card struct {
+		ID    string `sc:"@id"`
+		Title string `sc:"Title"`
+	}
+	type props struct {
+		Featured card   `sc:"Featured"`
+		Empty    *card  `sc:"Empty"`
+		Cards    []card `sc:"Cards"`
+		ID       string `sc:"@id"`
+	}
+
+	var p props
+	err := models.Bind(map[string]any{
+		"Featured": map[string]any{"value": "{F1}"},
+		"Empty":    map[string]any{"value": ""},
+		"Cards": []any{
+			map[string]any{"id": "{C1}", "fields": map[string]any{"Title": map[string]any{"value": "One"}}},
+			map[string]any{"id": "{C2}"},
+			map[string]any{"id": "{C3}", "fields": map[string]any{}},
+		},
+	}, &p)
+
+	messages := bindErrors(t, err)
+	expected := map[string]string{
+		"Featured":       "referenced item {F1} is not expanded",
+		"Empty":          "expected a referenced item",
+		"Cards[1]":       "referenced item {C2} is not expanded",
+		"Cards[2].Title": "missing field",
+		"@id":            "item properties can only be bound on referenced items",
+	}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("expected errors %v, got %v", expected, messages)
+	}
+
+	// Items that could not be bound are left out of slices
+	if len(p.Cards) != 2 || p.Cards[0] != (card{ID: "{C1}", Title: "One"}) || p.Cards[1].ID != "{C3}" {
+		t.Errorf("unexpected cards: %+v", p.Cards)
+	}
+}
+
+func TestBind_InvalidTarget(t *testing.T) {
+	var notStruct string
+	var nilStruct *struct{}
+
+	for _, dst := range []any{nil, struct{}{}, &notStruct, nilStruct} {
+		if err := models.Bind(map[string]any{}, dst); err == nil {
+			t.Errorf("expected error for target %T", dst)
+		}
+	}
+
+	var p struct {
+		Title string `sc:"Title"`
+	}
+	if err := models.Bind([]string{"Title"}, &p); err == nil {
+		t.Error("expected error for fields that are not a map")
+	}
+}
+
+func TestBindParams(t *testing.T) {
+	type params struct {
+		CSSClass   string  `sc:"CSSClass"`
+		Columns    int     `sc:"Columns"`
+		ShowTitle  bool    `sc:"ShowTitle"`
+		Opacity    float64 `sc:"Opacity,optional"`
+		RenderMode string  `sc:"RenderMode,optional"`
+	}
+
+	t.Run("map[string]string", func(t *testing.T) {
+		var p params
+		err := models.BindParams(map[string]string{
+			"CSSClass":  "hero",
+			"Columns":   "3",
+			"ShowTitle": "1",
+			"Opacity":   "0.5",
+		}, &p)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := params{CSSClass: "hero", Columns: 3, ShowTitle: true, Opacity: 0.5}
+		if p != expected {
+			t.Errorf("expected %+v, got %+v", expected, p)
+		}
+	})
+
+	t.Run("*ComponentParams", func(t *testing.T) {
+		componentParams := layoutservice.ComponentParams{
+			"CSSClass":  "hero",
+			"Columns":   "2",
+			"ShowTitle": "true",
+			"Opacity":   "",
+		}
+		var p params
+		if err := models.BindParams(&componentParams, &p); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := params{CSSClass: "hero", Columns: 2, ShowTitle: true}
+		if p != expected {
+			t.Errorf("expected %+v, got %+v", expected, p)
+		}
+
+		var nilParams *layoutservice.ComponentParams
+		err := models.BindParams(nilParams, &p)
+		messages := bindErrors(t, err)
+		if len(messages) != 3 || messages["Columns"] != "missing parameter" {
+			t.Errorf("expected 3 missing parameters, got %v", messages)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var p params
+		err := models.BindParams(map[string]string{
+			"CSSClass":  "hero",
+			"Columns":   "many",
+			"ShowTitle": "yes",
+		}, &p)
+		messages := bindErrors(t, err)
+		expected := map[string]string{
+			"Columns":   "expected an integer value, got many",
+			"ShowTitle": "expected a checkbox value, got string",
+		}
+		if !reflect.DeepEqual(messages, expected) {
+			t.Errorf("expected errors %v, got %v", expected, messages)
+		}
+	})
+}
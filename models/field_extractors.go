@@ -2,6 +2,8 @@ package models
 
 import (
 	"encoding/json"
+	"strconv"
+	"strings"
 
 	"github.com/guitarrich/content-sdk-go/debug"
 )
@@ -256,6 +258,18 @@ func ExtractLinkFieldFromMap(fieldData any) *LinkField {
 				field.Value.Class = class
 				field.Class = class
 			}
+			if linkType, ok := value["linktype"].(string); ok {
+				field.Value.LinkType = linkType
+				field.LinkType = linkType
+			}
+			if anchor, ok := value["anchor"].(string); ok {
+				field.Value.Anchor = anchor
+				field.Anchor = anchor
+			}
+			if queryString, ok := value["querystring"].(string); ok {
+				field.Value.QueryString = queryString
+				field.QueryString = queryString
+			}
 		}
 	} else {
 		// Try direct properties (fallback)
@@ -274,6 +288,15 @@ func ExtractLinkFieldFromMap(fieldData any) *LinkField {
 		if class, ok := fieldValues["class"].(string); ok {
 			field.Class = class
 		}
+		if linkType, ok := fieldValues["linktype"].(string); ok {
+			field.LinkType = linkType
+		}
+		if anchor, ok := fieldValues["anchor"].(string); ok {
+			field.Anchor = anchor
+		}
+		if queryString, ok := fieldValues["querystring"].(string); ok {
+			field.QueryString = queryString
+		}
 	}
 
 	// Extract editable metadata (contains pre-wrapped HTML with chrome)
@@ -291,3 +314,253 @@ func ExtractLinkFieldFromMap(fieldData any) *LinkField {
 
 	return field
 }
+
+// unwrapFieldValue returns the raw value of a field and the map holding its metadata
+// Handles both jsonValue.value and direct value patterns
+func unwrapFieldValue(fieldMap map[string]any) (any, map[string]any) {
+	if jsonValue, ok := fieldMap["jsonValue"].(map[string]any); ok {
+		return jsonValue["value"], jsonValue
+	}
+	return fieldMap["value"], fieldMap
+}
+
+// ExtractDateFieldFromMap extracts a DateField from generic field data
+// Handles both jsonValue.value and direct value patterns
+func ExtractDateFieldFromMap(fieldData any) *DateField {
+	if fieldData == nil {
+		return &DateField{}
+	}
+
+	fieldMap, ok := fieldData.(map[string]any)
+	if !ok {
+		// If it's a string, use it directly
+		if str, ok := fieldData.(string); ok {
+			return &DateField{Value: str}
+		}
+		return &DateField{}
+	}
+
+	field := &DateField{}
+
+	value, container := unwrapFieldValue(fieldMap)
+	if str, ok := value.(string); ok {
+		field.Value = str
+	}
+	field.Metadata = extractMetadata(container)
+
+	// Extract editable metadata (contains pre-wrapped HTML with chrome)
+	if editable, ok := fieldMap["editable"].(string); ok {
+		field.Editable = editable
+	}
+
+	return field
+}
+
+// ExtractNumberFieldFromMap extracts a NumberField from generic field data
+// Accepts numeric and string values; empty or invalid values leave the field empty
+func ExtractNumberFieldFromMap(fieldData any) *NumberField {
+	if fieldData == nil {
+		return &NumberField{}
+	}
+
+	field := &NumberField{}
+
+	fieldMap, ok := fieldData.(map[string]any)
+	if !ok {
+		if number, ok := toFloat(fieldData); ok {
+			field.Value = &number
+		}
+		return field
+	}
+
+	value, container := unwrapFieldValue(fieldMap)
+	if number, ok := toFloat(value); ok {
+		field.Value = &number
+	}
+	field.Metadata = extractMetadata(container)
+
+	// Extract editable metadata (contains pre-wrapped HTML with chrome)
+	if editable, ok := fieldMap["editable"].(string); ok {
+		field.Editable = editable
+	}
+
+	return field
+}
+
+// ExtractIntegerFieldFromMap extracts an IntegerField from generic field data
+// Accepts numeric and string values; empty or invalid values leave the field empty
+func ExtractIntegerFieldFromMap(fieldData any) *IntegerField {
+	if fieldData == nil {
+		return &IntegerField{}
+	}
+
+	field := &IntegerField{}
+
+	fieldMap, ok := fieldData.(map[string]any)
+	if !ok {
+		if number, ok := toInt(fieldData); ok {
+			field.Value = &number
+		}
+		return field
+	}
+
+	value, container := unwrapFieldValue(fieldMap)
+	if number, ok := toInt(value); ok {
+		field.Value = &number
+	}
+	field.Metadata = extractMetadata(container)
+
+	// Extract editable metadata (contains pre-wrapped HTML with chrome)
+	if editable, ok := fieldMap["editable"].(string); ok {
+		field.Editable = editable
+	}
+
+	return field
+}
+
+// ExtractCheckboxFieldFromMap extracts a CheckboxField from generic field data
+// Accepts boolean values as well as "1"/"true" strings
+func ExtractCheckboxFieldFromMap(fieldData any) *CheckboxField {
+	if fieldData == nil {
+		return &CheckboxField{}
+	}
+
+	fieldMap, ok := fieldData.(map[string]any)
+	if !ok {
+		return &CheckboxField{Value: toBool(fieldData)}
+	}
+
+	field := &CheckboxField{}
+
+	value, container := unwrapFieldValue(fieldMap)
+	field.Value = toBool(value)
+	field.Metadata = extractMetadata(container)
+
+	// Extract editable metadata (contains pre-wrapped HTML with chrome)
+	if editable, ok := fieldMap["editable"].(string); ok {
+		field.Editable = editable
+	}
+
+	return field
+}
+
+// ExtractFileFieldFromMap extracts a FileField from generic field data
+// Handles both jsonValue.value and direct property patterns
+func ExtractFileFieldFromMap(fieldData any) *FileField {
+	if fieldData == nil {
+		return &FileField{}
+	}
+
+	fieldMap, ok := fieldData.(map[string]any)
+	if !ok {
+		return &FileField{}
+	}
+
+	field := &FileField{}
+
+	value, container := unwrapFieldValue(fieldMap)
+	valueMap, ok := value.(map[string]any)
+	if !ok {
+		// Direct properties (fallback)
+		valueMap = fieldMap
+	}
+
+	field.Value = &FileFieldValue{}
+	if src, ok := valueMap["src"].(string); ok {
+		field.Value.Src = src
+		field.Src = src // Also set direct property for convenience
+	}
+	if title, ok := valueMap["title"].(string); ok {
+		field.Value.Title = title
+		field.Title = title
+	}
+	if displayName, ok := valueMap["displayName"].(string); ok {
+		field.Value.DisplayName = displayName
+		field.DisplayName = displayName
+	}
+	if description, ok := valueMap["description"].(string); ok {
+		field.Value.Description = description
+	}
+	if extension, ok := valueMap["extension"].(string); ok {
+		field.Value.Extension = extension
+	}
+	if mimeType, ok := valueMap["mimeType"].(string); ok {
+		field.Value.MimeType = mimeType
+	}
+	if size, ok := valueMap["size"]; ok && size != nil {
+		field.Value.Size = fmtValue(size)
+	}
+
+	// Extract editable metadata (contains pre-wrapped HTML with chrome)
+	if editable, ok := fieldMap["editable"].(string); ok {
+		field.Editable = editable
+	}
+
+	// Extract field metadata (only present in editing mode)
+	field.Metadata = extractMetadata(container)
+
+	return field
+}
+
+// toFloat converts a numeric or string value to float64
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// toInt converts a numeric or string value to int64
+func toInt(value any) (int64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return int64(v), v == float64(int64(v))
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	case json.Number:
+		i, err := v.Int64()
+		return i, err == nil
+	case string:
+		i, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		return i, err == nil
+	}
+	return 0, false
+}
+
+// toBool converts a boolean, numeric or string value to bool
+func toBool(value any) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v == "1" || strings.EqualFold(v, "true")
+	}
+	return false
+}
+
+// fmtValue formats a scalar value as a string
+func fmtValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	jsonBytes, _ := json.Marshal(value)
+	return string(jsonBytes)
+}
@@ -27,6 +27,36 @@ func GetLinkField(fields any, fieldName string) *LinkField {
 	return ExtractLinkFieldFromMap(fieldData)
 }
 
+// GetDateField extracts and returns a DateField from fields by name
+func GetDateField(fields any, fieldName string) *DateField {
+	fieldData := GetFieldByName(fields, fieldName)
+	return ExtractDateFieldFromMap(fieldData)
+}
+
+// GetNumberField extracts and returns a NumberField from fields by name
+func GetNumberField(fields any, fieldName string) *NumberField {
+	fieldData := GetFieldByName(fields, fieldName)
+	return ExtractNumberFieldFromMap(fieldData)
+}
+
+// GetIntegerField extracts and returns an IntegerField from fields by name
+func GetIntegerField(fields any, fieldName string) *IntegerField {
+	fieldData := GetFieldByName(fields, fieldName)
+	return ExtractIntegerFieldFromMap(fieldData)
+}
+
+// GetCheckboxField extracts and returns a CheckboxField from fields by name
+func GetCheckboxField(fields any, fieldName string) *CheckboxField {
+	fieldData := GetFieldByName(fields, fieldName)
+	return ExtractCheckboxFieldFromMap(fieldData)
+}
+
+// GetFileField extracts and returns a FileField from fields by name
+func GetFileField(fields any, fieldName string) *FileField {
+	fieldData := GetFieldByName(fields, fieldName)
+	return ExtractFileFieldFromMap(fieldData)
+}
+
 // GetFieldByName extracts a field by name from the fields interface
 func GetFieldByName(fields any, name string) any {
 	if fields == nil {
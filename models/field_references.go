@@ -0,0 +1,259 @@
+package models
+
+import (
+	"strings"
+)
+
+// ItemReference is an item linked from a reference field
+// (Droplink, Droptree, Grouped Droplink, or one entry of a Multilist/Treelist)
+type ItemReference struct {
+	ID          string         `json:"id"`
+	URL         string         `json:"url,omitempty"`
+	Name        string         `json:"name,omitempty"`
+	DisplayName string         `json:"displayName,omitempty"`
+	Fields      map[string]any `json:"fields,omitempty"`
+	Metadata    *FieldMetadata `json:"metadata,omitempty"`
+}
+
+func (r *ItemReference) GetValue() any {
+	return r
+}
+
+func (r *ItemReference) GetEditable() string {
+	return ""
+}
+
+func (r *ItemReference) GetMetadata() *FieldMetadata {
+	return r.Metadata
+}
+
+func (r *ItemReference) IsEmpty() bool {
+	return r.ID == "" && r.Fields == nil
+}
+
+// IsExpanded reports whether the referenced item's fields were included in the response
+func (r *ItemReference) IsExpanded() bool {
+	return r.Fields != nil
+}
+
+// GetField returns the raw data of a field of the referenced item
+func (r *ItemReference) GetField(fieldName string) any {
+	return GetFieldByName(r.Fields, fieldName)
+}
+
+// GetTextField returns a TextField of the referenced item
+func (r *ItemReference) GetTextField(fieldName string) *TextField {
+	return GetTextField(r.Fields, fieldName)
+}
+
+// GetRichTextField returns a RichTextField of the referenced item
+func (r *ItemReference) GetRichTextField(fieldName string) *RichTextField {
+	return GetRichTextField(r.Fields, fieldName)
+}
+
+// GetImageField returns an ImageField of the referenced item
+func (r *ItemReference) GetImageField(fieldName string) *ImageField {
+	return GetImageField(r.Fields, fieldName)
+}
+
+// GetLinkField returns a LinkField of the referenced item
+func (r *ItemReference) GetLinkField(fieldName string) *LinkField {
+	return GetLinkField(r.Fields, fieldName)
+}
+
+// GetDateField returns a DateField of the referenced item
+func (r *ItemReference) GetDateField(fieldName string) *DateField {
+	return GetDateField(r.Fields, fieldName)
+}
+
+// GetCheckboxField returns a CheckboxField of the referenced item
+func (r *ItemReference) GetCheckboxField(fieldName string) *CheckboxField {
+	return GetCheckboxField(r.Fields, fieldName)
+}
+
+// GetItemReference returns a single-item reference field of the referenced item
+func (r *ItemReference) GetItemReference(fieldName string) *ItemReference {
+	return GetItemReference(r.Fields, fieldName)
+}
+
+// GetItemReferenceList returns a multi-item reference field of the referenced item
+func (r *ItemReference) GetItemReferenceList(fieldName string) *ItemReferenceList {
+	return GetItemReferenceList(r.Fields, fieldName)
+}
+
+// ItemReferenceList represents a multi-item reference field (Multilist, Treelist)
+type ItemReferenceList struct {
+	Items    []*ItemReference `json:"items"`
+	Metadata *FieldMetadata   `json:"metadata,omitempty"`
+}
+
+func (l *ItemReferenceList) GetValue() any {
+	return l.Items
+}
+
+func (l *ItemReferenceList) GetEditable() string {
+	return ""
+}
+
+func (l *ItemReferenceList) GetMetadata() *FieldMetadata {
+	return l.Metadata
+}
+
+func (l *ItemReferenceList) IsEmpty() bool {
+	return len(l.Items) == 0
+}
+
+// IDs returns the IDs of the referenced items
+func (l *ItemReferenceList) IDs() []string {
+	ids := make([]string, 0, len(l.Items))
+	for _, item := range l.Items {
+		if item.ID != "" {
+			ids = append(ids, item.ID)
+		}
+	}
+	return ids
+}
+
+// Unexpanded returns the referenced items whose fields were not included in the response
+func (l *ItemReferenceList) Unexpanded() []*ItemReference {
+	var items []*ItemReference
+	for _, item := range l.Items {
+		if !item.IsExpanded() && item.ID != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ExtractItemReferenceFromMap extracts an ItemReference from generic field data
+// Handles expanded items ({id, url, name, fields}), jsonValue/value wrappers
+// and unexpanded ID values ("{GUID}")
+func ExtractItemReferenceFromMap(fieldData any) *ItemReference {
+	switch data := fieldData.(type) {
+	case string:
+		return &ItemReference{ID: strings.TrimSpace(data)}
+	case map[string]any:
+		// Expanded item
+		if _, ok := data["id"]; ok {
+			return itemReferenceFromMap(data)
+		}
+		if _, ok := data["fields"]; ok {
+			return itemReferenceFromMap(data)
+		}
+
+		// GraphQL pattern: { jsonValue: { id, url, name, fields } }
+		if jsonValue, ok := data["jsonValue"].(map[string]any); ok {
+			if _, ok := jsonValue["id"]; ok {
+				return itemReferenceFromMap(jsonValue)
+			}
+		}
+
+		value, container := unwrapFieldValue(data)
+		if value == nil {
+			return &ItemReference{}
+		}
+		reference := ExtractItemReferenceFromMap(value)
+		reference.Metadata = extractMetadata(container)
+		return reference
+	}
+	return &ItemReference{}
+}
+
+// ExtractItemReferenceListFromMap extracts an ItemReferenceList from generic field data
+// Handles arrays of expanded items, jsonValue/value wrappers, GraphQL targetItems
+// and unexpanded pipe-separated ID values ("{GUID}|{GUID}")
+func ExtractItemReferenceListFromMap(fieldData any) *ItemReferenceList {
+	switch data := fieldData.(type) {
+	case []any:
+		list := &ItemReferenceList{}
+		for _, entry := range data {
+			if reference := ExtractItemReferenceFromMap(entry); !reference.IsEmpty() {
+				list.Items = append(list.Items, reference)
+			}
+		}
+		return list
+	case string:
+		list := &ItemReferenceList{}
+		for id := range strings.SplitSeq(data, "|") {
+			if id = strings.TrimSpace(id); id != "" {
+				list.Items = append(list.Items, &ItemReference{ID: id})
+			}
+		}
+		return list
+	case map[string]any:
+		// GraphQL pattern: { targetItems: [...] }
+		if targetItems, ok := data["targetItems"]; ok {
+			list := ExtractItemReferenceListFromMap(targetItems)
+			list.Metadata = extractMetadata(data)
+			return list
+		}
+
+		// GraphQL pattern: { jsonValue: [...] }
+		if jsonValue, ok := data["jsonValue"].([]any); ok {
+			return ExtractItemReferenceListFromMap(jsonValue)
+		}
+
+		value, container := unwrapFieldValue(data)
+		if value == nil {
+			return &ItemReferenceList{}
+		}
+		list := ExtractItemReferenceListFromMap(value)
+		list.Metadata = extractMetadata(container)
+		return list
+	}
+	return &ItemReferenceList{}
+}
+
+// itemReferenceFromMap builds an ItemReference from an expanded item map
+func itemReferenceFromMap(data map[string]any) *ItemReference {
+	reference := &ItemReference{}
+
+	if id, ok := data["id"].(string); ok {
+		reference.ID = id
+	}
+	if name, ok := data["name"].(string); ok {
+		reference.Name = name
+	}
+	if displayName, ok := data["displayName"].(string); ok {
+		reference.DisplayName = displayName
+	}
+
+	// url is a string in layout data and { path } in GraphQL
+	switch url := data["url"].(type) {
+	case string:
+		reference.URL = url
+	case map[string]any:
+		if path, ok := url["path"].(string); ok {
+			reference.URL = path
+		}
+	}
+
+	// fields is a map in layout data and [{ name, jsonValue }] in GraphQL
+	switch fields := data["fields"].(type) {
+	case map[string]any:
+		reference.Fields = fields
+	case []any:
+		reference.Fields = make(map[string]any, len(fields))
+		for _, entry := range fields {
+			if fieldMap, ok := entry.(map[string]any); ok {
+				if name, ok := fieldMap["name"].(string); ok {
+					reference.Fields[name] = fieldMap["jsonValue"]
+				}
+			}
+		}
+	}
+
+	return reference
+}
+
+// GetItemReference extracts and returns an ItemReference from fields by name
+func GetItemReference(fields any, fieldName string) *ItemReference {
+	fieldData := GetFieldByName(fields, fieldName)
+	return ExtractItemReferenceFromMap(fieldData)
+}
+
+// GetItemReferenceList extracts and returns an ItemReferenceList from fields by name
+func GetItemReferenceList(fields any, fieldName string) *ItemReferenceList {
+	fieldData := GetFieldByName(fields, fieldName)
+	return ExtractItemReferenceListFromMap(fieldData)
+}
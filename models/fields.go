@@ -1,5 +1,12 @@
 package models
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // Field represents a generic Sitecore field
 // All field types implement this interface
 type Field interface {
@@ -154,23 +161,29 @@ func (f *ImageField) GetHeight() string {
 
 // LinkField represents a link field (General Link, Internal Link, External Link)
 type LinkField struct {
-	Href     string          `json:"href"`
-	Text     string          `json:"text"`
-	Target   string          `json:"target,omitempty"`
-	Title    string          `json:"title,omitempty"`
-	Class    string          `json:"class,omitempty"`
-	Editable string          `json:"editable,omitempty"`
-	Value    *LinkFieldValue `json:"value,omitempty"`
-	Metadata *FieldMetadata  `json:"metadata,omitempty"`
+	Href        string          `json:"href"`
+	Text        string          `json:"text"`
+	Target      string          `json:"target,omitempty"`
+	Title       string          `json:"title,omitempty"`
+	Class       string          `json:"class,omitempty"`
+	LinkType    string          `json:"linktype,omitempty"`
+	Anchor      string          `json:"anchor,omitempty"`
+	QueryString string          `json:"querystring,omitempty"`
+	Editable    string          `json:"editable,omitempty"`
+	Value       *LinkFieldValue `json:"value,omitempty"`
+	Metadata    *FieldMetadata  `json:"metadata,omitempty"`
 }
 
 // LinkFieldValue contains the nested link value structure
 type LinkFieldValue struct {
-	Href   string `json:"href"`
-	Text   string `json:"text"`
-	Target string `json:"target,omitempty"`
-	Title  string `json:"title,omitempty"`
-	Class  string `json:"class,omitempty"`
+	Href        string `json:"href"`
+	Text        string `json:"text"`
+	Target      string `json:"target,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Class       string `json:"class,omitempty"`
+	LinkType    string `json:"linktype,omitempty"`
+	Anchor      string `json:"anchor,omitempty"`
+	QueryString string `json:"querystring,omitempty"`
 }
 
 func (f *LinkField) GetValue() any {
@@ -186,7 +199,7 @@ func (f *LinkField) GetMetadata() *FieldMetadata {
 }
 
 func (f *LinkField) IsEmpty() bool {
-	return f.Href == "" && (f.Value == nil || f.Value.Href == "")
+	return f.GetHref() == "" && f.GetAnchor() == ""
 }
 
 // GetHref returns the link href, checking both direct and nested value
@@ -243,3 +256,312 @@ func (f *LinkField) GetClass() string {
 	}
 	return ""
 }
+
+// GetLinkType returns the Sitecore link type (internal, external, media, anchor, mailto, javascript).
+// When the type is not set it is inferred from the href.
+func (f *LinkField) GetLinkType() string {
+	if f.LinkType != "" {
+		return f.LinkType
+	}
+	if f.Value != nil && f.Value.LinkType != "" {
+		return f.Value.LinkType
+	}
+
+	href := strings.ToLower(f.GetHref())
+	switch {
+	case strings.HasPrefix(href, "mailto:"):
+		return LinkTypeMailto
+	case strings.HasPrefix(href, "javascript:"):
+		return LinkTypeJavascript
+	case href == "" && f.GetAnchor() != "", strings.HasPrefix(href, "#"):
+		return LinkTypeAnchor
+	case strings.Contains(href, "/-/media/") || strings.Contains(href, "/~/media/"):
+		return LinkTypeMedia
+	case strings.HasPrefix(href, "http://") || strings.HasPrefix(href, "https://") || strings.HasPrefix(href, "//"):
+		return LinkTypeExternal
+	default:
+		return LinkTypeInternal
+	}
+}
+
+// GetAnchor returns the link anchor, checking both direct and nested value
+func (f *LinkField) GetAnchor() string {
+	if f.Anchor != "" {
+		return f.Anchor
+	}
+	if f.Value != nil && f.Value.Anchor != "" {
+		return f.Value.Anchor
+	}
+	return ""
+}
+
+// GetQueryString returns the link query string, checking both direct and nested value
+func (f *LinkField) GetQueryString() string {
+	if f.QueryString != "" {
+		return f.QueryString
+	}
+	if f.Value != nil && f.Value.QueryString != "" {
+		return f.Value.QueryString
+	}
+	return ""
+}
+
+// GetFullHref returns the href with the link's query string and anchor appended
+func (f *LinkField) GetFullHref() string {
+	href := f.GetHref()
+
+	if query := strings.TrimPrefix(f.GetQueryString(), "?"); query != "" && !strings.Contains(href, "?") {
+		href += "?" + query
+	}
+	if anchor := strings.TrimPrefix(f.GetAnchor(), "#"); anchor != "" && !strings.Contains(href, "#") {
+		href += "#" + anchor
+	}
+
+	return href
+}
+
+// Sitecore General Link types
+const (
+	LinkTypeInternal   = "internal"
+	LinkTypeExternal   = "external"
+	LinkTypeMedia      = "media"
+	LinkTypeAnchor     = "anchor"
+	LinkTypeMailto     = "mailto"
+	LinkTypeJavascript = "javascript"
+)
+
+// DateField represents a Date or Datetime field.
+// Sitecore stores dates in UTC; Date fields are stored at midnight UTC.
+type DateField struct {
+	Value    string         `json:"value"`
+	Editable string         `json:"editable,omitempty"`
+	Metadata *FieldMetadata `json:"metadata,omitempty"`
+}
+
+// sitecoreDateLayouts are the date formats Sitecore returns
+var sitecoreDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"20060102T150405Z",
+	"20060102T150405",
+	"2006-01-02",
+}
+
+func (f *DateField) GetValue() any {
+	return f.Value
+}
+
+func (f *DateField) GetEditable() string {
+	return f.Editable
+}
+
+func (f *DateField) GetMetadata() *FieldMetadata {
+	return f.Metadata
+}
+
+func (f *DateField) IsEmpty() bool {
+	t, err := f.Time()
+	return err != nil || t.IsZero()
+}
+
+// Time parses the field value as a UTC time
+func (f *DateField) Time() (time.Time, error) {
+	for _, layout := range sitecoreDateLayouts {
+		if t, err := time.Parse(layout, f.Value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date value: %q", f.Value)
+}
+
+// Format formats the date in the given location (UTC if nil).
+// Use a nil location for Date fields to avoid shifting the day.
+func (f *DateField) Format(layout string, loc *time.Location) string {
+	t, err := f.Time()
+	if err != nil || t.IsZero() {
+		return ""
+	}
+	if loc != nil {
+		t = t.In(loc)
+	}
+	return t.Format(layout)
+}
+
+// NumberField represents a Number field
+type NumberField struct {
+	Value    *float64       `json:"value"`
+	Editable string         `json:"editable,omitempty"`
+	Metadata *FieldMetadata `json:"metadata,omitempty"`
+}
+
+func (f *NumberField) GetValue() any {
+	if f.Value == nil {
+		return nil
+	}
+	return *f.Value
+}
+
+func (f *NumberField) GetEditable() string {
+	return f.Editable
+}
+
+func (f *NumberField) GetMetadata() *FieldMetadata {
+	return f.Metadata
+}
+
+func (f *NumberField) IsEmpty() bool {
+	return f.Value == nil
+}
+
+// Float returns the number, or 0 if the field is empty
+func (f *NumberField) Float() float64 {
+	if f.Value == nil {
+		return 0
+	}
+	return *f.Value
+}
+
+// Format formats the number with the given number of decimals (-1 for the shortest representation)
+func (f *NumberField) Format(precision int) string {
+	if f.Value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f.Value, 'f', precision, 64)
+}
+
+// IntegerField represents an Integer field
+type IntegerField struct {
+	Value    *int64         `json:"value"`
+	Editable string         `json:"editable,omitempty"`
+	Metadata *FieldMetadata `json:"metadata,omitempty"`
+}
+
+func (f *IntegerField) GetValue() any {
+	if f.Value == nil {
+		return nil
+	}
+	return *f.Value
+}
+
+func (f *IntegerField) GetEditable() string {
+	return f.Editable
+}
+
+func (f *IntegerField) GetMetadata() *FieldMetadata {
+	return f.Metadata
+}
+
+func (f *IntegerField) IsEmpty() bool {
+	return f.Value == nil
+}
+
+// Int returns the integer, or 0 if the field is empty
+func (f *IntegerField) Int() int64 {
+	if f.Value == nil {
+		return 0
+	}
+	return *f.Value
+}
+
+// CheckboxField represents a Checkbox field
+type CheckboxField struct {
+	Value    bool           `json:"value"`
+	Editable string         `json:"editable,omitempty"`
+	Metadata *FieldMetadata `json:"metadata,omitempty"`
+}
+
+func (f *CheckboxField) GetValue() any {
+	return f.Value
+}
+
+func (f *CheckboxField) GetEditable() string {
+	return f.Editable
+}
+
+func (f *CheckboxField) GetMetadata() *FieldMetadata {
+	return f.Metadata
+}
+
+// IsEmpty always returns false; an unchecked checkbox is a value
+func (f *CheckboxField) IsEmpty() bool {
+	return false
+}
+
+// FileField represents a File field
+type FileField struct {
+	Src         string          `json:"src"`
+	Title       string          `json:"title,omitempty"`
+	DisplayName string          `json:"displayName,omitempty"`
+	Editable    string          `json:"editable,omitempty"`
+	Value       *FileFieldValue `json:"value,omitempty"`
+	Metadata    *FieldMetadata  `json:"metadata,omitempty"`
+}
+
+// FileFieldValue contains the nested file value structure
+type FileFieldValue struct {
+	Src         string `json:"src"`
+	Title       string `json:"title,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	Description string `json:"description,omitempty"`
+	Extension   string `json:"extension,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+	Size        string `json:"size,omitempty"`
+}
+
+func (f *FileField) GetValue() any {
+	return f
+}
+
+func (f *FileField) GetEditable() string {
+	return f.Editable
+}
+
+func (f *FileField) GetMetadata() *FieldMetadata {
+	return f.Metadata
+}
+
+func (f *FileField) IsEmpty() bool {
+	return f.GetSrc() == ""
+}
+
+// GetSrc returns the file source URL, checking both direct and nested value
+func (f *FileField) GetSrc() string {
+	if f.Src != "" {
+		return f.Src
+	}
+	if f.Value != nil && f.Value.Src != "" {
+		return f.Value.Src
+	}
+	return ""
+}
+
+// GetTitle returns the file title, falling back to the display name
+func (f *FileField) GetTitle() string {
+	if f.Title != "" {
+		return f.Title
+	}
+	if f.Value != nil && f.Value.Title != "" {
+		return f.Value.Title
+	}
+	if f.DisplayName != "" {
+		return f.DisplayName
+	}
+	if f.Value != nil {
+		return f.Value.DisplayName
+	}
+	return ""
+}
+
+// GetURL returns the file URL; relative media URLs are resolved against mediaHost
+func (f *FileField) GetURL(mediaHost string) string {
+	return ResolveMediaURL(f.GetSrc(), mediaHost)
+}
+
+// ResolveMediaURL resolves a relative media URL (e.g. /-/media/file.pdf) against a media host.
+// Absolute URLs and an empty host leave src unchanged.
+func ResolveMediaURL(src, mediaHost string) string {
+	if src == "" || mediaHost == "" || !strings.HasPrefix(src, "/") || strings.HasPrefix(src, "//") {
+		return src
+	}
+	return strings.TrimSuffix(mediaHost, "/") + src
+}
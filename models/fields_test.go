@@ -0,0 +1,331 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestExtractDateFieldFromMap(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     any
+		expected time.Time
+		empty    bool
+	}{
+		{"value", map[string]any{"value": "2024-01-15T09:30:00Z"}, time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC), false},
+		{"jsonValue", map[string]any{"jsonValue": map[string]any{"value": "2024-01-15T09:30:00+02:00"}}, time.Date(2024, 1, 15, 7, 30, 0, 0, time.UTC), false},
+		{"bare string", "2024-01-15", time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), false},
+		{"compact", map[string]any{"value": "20240101T000000Z"}, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"compact without zone", map[string]any{"value": "20240101T123000"}, time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC), false},
+		{"no zone", map[string]any{"value": "2024-01-15T09:30:00"}, time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC), false},
+		{"empty string", map[string]any{"value": ""}, time.Time{}, true},
+		{"zero date", map[string]any{"value": "00010101T000000Z"}, time.Time{}, true},
+		{"nil", nil, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := ExtractDateFieldFromMap(tt.data)
+			if field.IsEmpty() != tt.empty {
+				t.Errorf("expected IsEmpty %v, got %v", tt.empty, field.IsEmpty())
+			}
+			if tt.empty {
+				return
+			}
+			value, err := field.Time()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !value.Equal(tt.expected) || value.Location() != time.UTC {
+				t.Errorf("expected %v, got %v", tt.expected, value)
+			}
+		})
+	}
+
+	field := &DateField{Value: "not a date"}
+	if _, err := field.Time(); err == nil {
+		t.Error("expected error for an invalid date")
+	}
+	if !field.IsEmpty() || field.Format("2006-01-02", nil) != "" {
+		t.Error("expected invalid date to be empty")
+	}
+
+	// Date fields are stored at midnight UTC and keep their day without a location
+	field = &DateField{Value: "20240115T000000Z"}
+	if formatted := field.Format("2006-01-02", nil); formatted != "2024-01-15" {
+		t.Errorf("expected '2024-01-15', got '%s'", formatted)
+	}
+	if formatted := field.Format("2006-01-02", time.FixedZone("PST", -8*60*60)); formatted != "2024-01-14" {
+		t.Errorf("expected '2024-01-14' in PST, got '%s'", formatted)
+	}
+}
+
+func TestExtractNumberFieldFromMap(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     any
+		expected any
+	}{
+		{"value", map[string]any{"value": 12.5}, 12.5},
+		{"jsonValue", map[string]any{"jsonValue": map[string]any{"value": 3.0}}, 3.0},
+		{"string value", map[string]any{"value": " 4.25 "}, 4.25},
+		{"bare number", 7.0, 7.0},
+		{"bare string", "-1.5", -1.5},
+		{"empty string", map[string]any{"value": ""}, nil},
+		{"invalid", map[string]any{"value": "abc"}, nil},
+		{"nil", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := ExtractNumberFieldFromMap(tt.data)
+			if value := field.GetValue(); value != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, value)
+			}
+			if field.IsEmpty() != (tt.expected == nil) {
+				t.Errorf("expected IsEmpty %v, got %v", tt.expected == nil, field.IsEmpty())
+			}
+		})
+	}
+
+	value := 1234.5678
+	field := &NumberField{Value: &value}
+	if formatted := field.Format(2); formatted != "1234.57" {
+		t.Errorf("expected '1234.57', got '%s'", formatted)
+	}
+	if formatted := field.Format(-1); formatted != "1234.5678" {
+		t.Errorf("expected '1234.5678', got '%s'", formatted)
+	}
+	if (&NumberField{}).Format(2) != "" || (&NumberField{}).Float() != 0 {
+		t.Error("expected empty number to format as '' and return 0")
+	}
+}
+
+func TestExtractIntegerFieldFromMap(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     any
+		expected any
+	}{
+		{"value", map[string]any{"value": 42.0}, int64(42)},
+		{"jsonValue", map[string]any{"jsonValue": map[string]any{"value": "7"}}, int64(7)},
+		{"bare number", 3.0, int64(3)},
+		{"bare string", " -12 ", int64(-12)},
+		{"fraction", map[string]any{"value": 1.5}, nil},
+		{"decimal string", map[string]any{"value": "1.5"}, nil},
+		{"empty string", map[string]any{"value": ""}, nil},
+		{"nil", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := ExtractIntegerFieldFromMap(tt.data)
+			if value := field.GetValue(); value != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, value)
+			}
+			if field.IsEmpty() != (tt.expected == nil) {
+				t.Errorf("expected IsEmpty %v, got %v", tt.expected == nil, field.IsEmpty())
+			}
+		})
+	}
+}
+
+func TestExtractCheckboxFieldFromMap(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     any
+		expected bool
+	}{
+		{"true", map[string]any{"value": true}, true},
+		{"false", map[string]any{"value": false}, false},
+		{"string 1", map[string]any{"value": "1"}, true},
+		{"string true", map[string]any{"value": "True"}, true},
+		{"string 0", map[string]any{"value": "0"}, false},
+		{"empty string", map[string]any{"value": ""}, false},
+		{"jsonValue", map[string]any{"jsonValue": map[string]any{"value": "1"}}, true},
+		{"bare bool", true, true},
+		{"bare string", "1", true},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := ExtractCheckboxFieldFromMap(tt.data)
+			if field.Value != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, field.Value)
+			}
+			if field.IsEmpty() {
+				t.Error("expected checkbox never to be empty")
+			}
+		})
+	}
+}
+
+func TestExtractFileFieldFromMap(t *testing.T) {
+	tests := []struct {
+		name string
+		data any
+	}{
+		{"value", map[string]any{"value": map[string]any{"src": "/-/media/guide.pdf", "title": "Guide", "size": 2048.0}}},
+		{"jsonValue", map[string]any{"jsonValue": map[string]any{"value": map[string]any{"src": "/-/media/guide.pdf", "title": "Guide", "size": "2048"}}}},
+		{"direct properties", map[string]any{"src": "/-/media/guide.pdf", "title": "Guide", "size": 2048.0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := ExtractFileFieldFromMap(tt.data)
+			if field.GetSrc() != "/-/media/guide.pdf" || field.GetTitle() != "Guide" {
+				t.Errorf("unexpected file: %+v", field)
+			}
+			if field.Value.Size != "2048" {
+				t.Errorf("expected size '2048', got '%s'", field.Value.Size)
+			}
+			if field.IsEmpty() {
+				t.Error("expected file not to be empty")
+			}
+		})
+	}
+
+	for _, data := range []any{nil, "guide.pdf", map[string]any{"value": ""}} {
+		if field := ExtractFileFieldFromMap(data); !field.IsEmpty() {
+			t.Errorf("expected %v to be an empty file, got %+v", data, field)
+		}
+	}
+
+	field := &FileField{Src: "/-/media/guide.pdf"}
+	if url := field.GetURL("https://cdn.example.com"); url != "https://cdn.example.com/-/media/guide.pdf" {
+		t.Errorf("expected media host URL, got '%s'", url)
+	}
+}
+
+func TestCoercions(t *testing.T) {
+	floats := []struct {
+		value    any
+		expected float64
+		ok       bool
+	}{
+		{1.5, 1.5, true},
+		{3, 3, true},
+		{int64(4), 4, true},
+		{json.Number("2.5"), 2.5, true},
+		{" 6.5 ", 6.5, true},
+		{"", 0, false},
+		{"abc", 0, false},
+		{true, 0, false},
+		{nil, 0, false},
+	}
+	for _, tt := range floats {
+		if value, ok := toFloat(tt.value); value != tt.expected || ok != tt.ok {
+			t.Errorf("toFloat(%#v): expected %v/%v, got %v/%v", tt.value, tt.expected, tt.ok, value, ok)
+		}
+	}
+
+	ints := []struct {
+		value    any
+		expected int64
+		ok       bool
+	}{
+		{2.0, 2, true},
+		{2.5, 2, false},
+		{3, 3, true},
+		{int64(4), 4, true},
+		{json.Number("5"), 5, true},
+		{json.Number("5.5"), 0, false},
+		{" 6 ", 6, true},
+		{"6.0", 0, false},
+		{"", 0, false},
+		{false, 0, false},
+	}
+	for _, tt := range ints {
+		if value, ok := toInt(tt.value); value != tt.expected || ok != tt.ok {
+			t.Errorf("toInt(%#v): expected %v/%v, got %v/%v", tt.value, tt.expected, tt.ok, value, ok)
+		}
+	}
+
+	bools := []struct {
+		value    any
+		expected bool
+	}{
+		{true, true},
+		{false, false},
+		{1.0, true},
+		{0.0, false},
+		{"1", true},
+		{"true", true},
+		{"TRUE", true},
+		{"0", false},
+		{"yes", false},
+		{"", false},
+		{nil, false},
+	}
+	for _, tt := range bools {
+		if value := toBool(tt.value); value != tt.expected {
+			t.Errorf("toBool(%#v): expected %v, got %v", tt.value, tt.expected, value)
+		}
+	}
+}
+
+func TestLinkField_GetLinkType(t *testing.T) {
+	tests := []struct {
+		name     string
+		field    *LinkField
+		expected string
+	}{
+		{"explicit", &LinkField{Href: "https://example.com", LinkType: "internal"}, LinkTypeInternal},
+		{"explicit nested", &LinkField{Value: &LinkFieldValue{Href: "/about", LinkType: "media"}}, LinkTypeMedia},
+		{"internal", &LinkField{Href: "/about"}, LinkTypeInternal},
+		{"external", &LinkField{Href: "https://www.example.com"}, LinkTypeExternal},
+		{"protocol relative", &LinkField{Href: "//cdn.example.com/script.js"}, LinkTypeExternal},
+		{"media", &LinkField{Href: "/-/media/brochure.pdf"}, LinkTypeMedia},
+		{"legacy media", &LinkField{Href: "https://cm.example.com/~/media/brochure.pdf"}, LinkTypeMedia},
+		{"mailto", &LinkField{Href: "MAILTO:info@example.com"}, LinkTypeMailto},
+		{"javascript", &LinkField{Href: "javascript:void(0)"}, LinkTypeJavascript},
+		{"anchor href", &LinkField{Href: "#contact"}, LinkTypeAnchor},
+		{"anchor only", &LinkField{Anchor: "contact"}, LinkTypeAnchor},
+		{"empty", &LinkField{}, LinkTypeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if linkType := tt.field.GetLinkType(); linkType != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, linkType)
+			}
+		})
+	}
+}
+
+func TestLinkField_GetFullHref(t *testing.T) {
+	tests := []struct {
+		name     string
+		field    *LinkField
+		expected string
+	}{
+		{"href only", &LinkField{Href: "/about"}, "/about"},
+		{"query string", &LinkField{Href: "/search", QueryString: "?q=go"}, "/search?q=go"},
+		{"anchor", &LinkField{Href: "/about", Anchor: "#team"}, "/about#team"},
+		{"query string and anchor", &LinkField{Value: &LinkFieldValue{Href: "/about", QueryString: "a=1", Anchor: "team"}}, "/about?a=1#team"},
+		{"href already has query and anchor", &LinkField{Href: "/about?b=2#top", QueryString: "a=1", Anchor: "team"}, "/about?b=2#top"},
+		{"anchor without href", &LinkField{Anchor: "team"}, "#team"},
+		{"empty", &LinkField{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if href := tt.field.GetFullHref(); href != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, href)
+			}
+		})
+	}
+
+	// Extracted links read the type, query string and anchor from the nested value
+	field := ExtractLinkFieldFromMap(map[string]any{"jsonValue": map[string]any{"value": map[string]any{
+		"href":        "/contact",
+		"linktype":    "internal",
+		"querystring": "ref=nav",
+		"anchor":      "form",
+	}}})
+	if field.GetLinkType() != LinkTypeInternal || field.GetFullHref() != "/contact?ref=nav#form" {
+		t.Errorf("unexpected extracted link: %s %s", field.GetLinkType(), field.GetFullHref())
+	}
+}
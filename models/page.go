@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // Page represents a complete page from Sitecore with all associated data
 // Note: LayoutData uses interface{} to avoid import cycles
 // Cast to *layoutService.LayoutServiceData when using
@@ -24,6 +26,10 @@ type Page struct {
 	Language string `json:"language,omitempty"`
 	Site     string `json:"site,omitempty"`
 	ItemID   string `json:"itemId,omitempty"`
+
+	// RequestedLanguage is the language that was requested. It differs from
+	// Language when the page was served in a fallback language.
+	RequestedLanguage string `json:"requestedLanguage,omitempty"`
 }
 
 // DictionaryPhrases maps dictionary keys to their translated values
@@ -48,6 +54,7 @@ type HTMLLink struct {
 	Sizes       string `json:"sizes,omitempty"`
 	Media       string `json:"media,omitempty"`
 	CrossOrigin string `json:"crossOrigin,omitempty"`
+	Hreflang    string `json:"hreflang,omitempty"`
 }
 
 // PageOptions contains options for fetching a page
@@ -93,6 +100,71 @@ type EditingContext struct {
 
 	// QueryParams contains the Sitecore query parameters
 	QueryParams map[string]string `json:"queryParams,omitempty"`
+
+	// PreviewMode is the Pages render mode (edit, preview or metadata)
+	PreviewMode PreviewMode `json:"previewMode,omitempty"`
+
+	// Item being edited or previewed
+	ItemID     string     `json:"itemId,omitempty"`
+	Language   string     `json:"language,omitempty"`
+	Site       string     `json:"site,omitempty"`
+	Version    string     `json:"version,omitempty"`
+	LayoutKind LayoutKind `json:"layoutKind,omitempty"`
+	VariantIds []string   `json:"variantIds,omitempty"`
+	Route      string     `json:"route,omitempty"`
+
+	// Source is where the mode was detected (query, cookie or header)
+	Source string `json:"source,omitempty"`
+
+	// InPagesFrame indicates the request comes from the Sitecore Pages iframe
+	InPagesFrame bool `json:"inPagesFrame,omitempty"`
+}
+
+// NewEditingContext creates an EditingContext for preview data
+func NewEditingContext(previewData PreviewData) *EditingContext {
+	editingContext := &EditingContext{
+		IsEditing:   previewData.IsEditMode(),
+		IsPreview:   previewData.Mode == PreviewModePreview,
+		Mode:        PageModeEdit,
+		PreviewMode: previewData.Mode,
+		ItemID:      previewData.ItemID,
+		Language:    previewData.Language,
+		Site:        previewData.Site,
+		Version:     previewData.Version,
+		LayoutKind:  previewData.LayoutKind,
+		VariantIds:  previewData.VariantIds,
+		Route:       previewData.Route,
+		QueryParams: map[string]string{
+			"sc_itemid":     previewData.ItemID,
+			"sc_lang":       previewData.Language,
+			"sc_site":       previewData.Site,
+			"sc_layoutKind": string(previewData.LayoutKind),
+			"mode":          string(previewData.Mode),
+		},
+	}
+	if previewData.Mode == PreviewModePreview {
+		editingContext.Mode = PageModePreview
+	}
+	return editingContext
+}
+
+// IsMetadataMode checks if the page is edited in metadata mode
+func (ec *EditingContext) IsMetadataMode() bool {
+	return ec.PreviewMode == PreviewModeMetadata
+}
+
+// PreviewData returns the preview data for fetching the edited item
+func (ec *EditingContext) PreviewData() PreviewData {
+	return PreviewData{
+		ItemID:     ec.ItemID,
+		Language:   ec.Language,
+		Site:       ec.Site,
+		Version:    ec.Version,
+		Mode:       ec.PreviewMode,
+		LayoutKind: ec.LayoutKind,
+		VariantIds: ec.VariantIds,
+		Route:      ec.Route,
+	}
 }
 
 // StaticPath represents a path for static site generation
@@ -105,4 +177,7 @@ type StaticPath struct {
 
 	// Path segments (e.g., ["about", "team"])
 	Path []string `json:"path"`
+
+	// LastModified is when the route item was last updated (zero if unknown)
+	LastModified time.Time `json:"lastModified,omitzero"`
 }
@@ -5,12 +5,20 @@ type SiteInfo struct {
 	// Name is the site name in Sitecore
 	Name string `json:"name"`
 
-	// HostName is the primary hostname for the site
+	// HostName is the hostname for the site. Multiple hostnames can be
+	// separated by "|", and each may include a port and a path prefix
+	// (e.g. "example.com|www.example.com:8080/brand-a").
 	HostName string `json:"hostName"`
 
+	// VirtualFolder is the path prefix the site is mounted under (e.g. "/brand-a")
+	VirtualFolder string `json:"virtualFolder,omitempty"`
+
 	// Language is the default language for the site
 	Language string `json:"language"`
 
+	// Languages is the list of languages the site supports (optional)
+	Languages []string `json:"languages,omitempty"`
+
 	// RootPath is the content root path in Sitecore
 	RootPath string `json:"rootPath,omitempty"`
 
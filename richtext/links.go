@@ -0,0 +1,64 @@
+package richtext
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/guitarrich/content-sdk-go/graphql"
+)
+
+// LinkResolver resolves the URL of an item linked from rich text.
+// It returns "" when the item doesn't exist.
+type LinkResolver interface {
+	ResolveItemURL(ctx context.Context, itemID, site, language string) (string, error)
+}
+
+// LinkResolverFunc is a function type that implements LinkResolver
+type LinkResolverFunc func(ctx context.Context, itemID, site, language string) (string, error)
+
+// ResolveItemURL implements the LinkResolver interface
+func (f LinkResolverFunc) ResolveItemURL(ctx context.Context, itemID, site, language string) (string, error) {
+	return f(ctx, itemID, site, language)
+}
+
+// GraphQLLinkResolver resolves item URLs from Experience Edge
+type GraphQLLinkResolver struct {
+	client graphql.Client
+}
+
+// NewGraphQLLinkResolver creates a new link resolver backed by a GraphQL client
+func NewGraphQLLinkResolver(client graphql.Client) *GraphQLLinkResolver {
+	return &GraphQLLinkResolver{
+		client: client,
+	}
+}
+
+// ResolveItemURL returns the item's URL path in the given language.
+// Edge resolves the URL against the site the item belongs to, so site is not sent.
+func (r *GraphQLLinkResolver) ResolveItemURL(ctx context.Context, itemID, site, language string) (string, error) {
+	query := `
+		query ItemUrlQuery($id: String!, $language: String!) {
+			item(path: $id, language: $language) {
+				url {
+					path
+				}
+			}
+		}
+	`
+
+	data, err := r.client.Request(ctx, query, map[string]any{
+		"id":       itemID,
+		"language": language,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve item URL: %w", err)
+	}
+
+	item, ok := data["item"].(map[string]any)
+	if !ok {
+		return "", nil
+	}
+	itemURL, _ := item["url"].(map[string]any)
+	path, _ := itemURL["path"].(string)
+	return path, nil
+}
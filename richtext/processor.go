@@ -0,0 +1,315 @@
+// Package richtext processes rich text field values before rendering: internal
+// links and media links are rewritten to friendly URLs and the HTML is passed
+// through an allow-list sanitizer.
+package richtext
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/guitarrich/content-sdk-go/debug"
+	"github.com/guitarrich/content-sdk-go/media"
+	"github.com/guitarrich/content-sdk-go/models"
+	"github.com/guitarrich/content-sdk-go/site"
+	"golang.org/x/net/html"
+)
+
+// Hook modifies an element after sanitization
+type Hook func(node *html.Node)
+
+// Config contains configuration for the rich text processor
+type Config struct {
+	// LinkResolver resolves ~/link.aspx?_id= links to item URLs (optional).
+	// Without a resolver, internal links are left unchanged.
+	LinkResolver LinkResolver
+
+	// MediaAPI builds media URLs for -/media/ and ~/media/ links (optional).
+	// Without a MediaAPI, media links are normalized to relative /-/media/ URLs.
+	MediaAPI *media.MediaAPI
+
+	// Policy is the sanitizer allow-list (default: DefaultPolicy())
+	Policy *Policy
+
+	// LocalePrefix prefixes resolved item URLs with the language,
+	// except for Options.DefaultLanguage (e.g. /fr/about)
+	LocalePrefix bool
+
+	// URLService adds the site path prefix and locale prefix to resolved item
+	// URLs (optional). It replaces LocalePrefix.
+	URLService site.URLService
+
+	// Hooks are run on every element of the sanitized output
+	Hooks []Hook
+}
+
+// Options contains the per-request settings for processing rich text
+type Options struct {
+	// Site is the current site name
+	Site string
+
+	// Language is the current language
+	Language string
+
+	// DefaultLanguage is the site's default language, used with Config.LocalePrefix
+	DefaultLanguage string
+
+	// PathPrefix is prepended to resolved item URLs (e.g. a site's virtual folder)
+	PathPrefix string
+}
+
+// Processor rewrites links in and sanitizes rich text
+type Processor struct {
+	config     Config
+	policy     *Policy
+	media      *media.MediaAPI
+	urlService site.URLService
+}
+
+// NewProcessor creates a new rich text processor
+func NewProcessor(config Config) *Processor {
+	policy := config.Policy
+	if policy == nil {
+		policy = DefaultPolicy()
+	}
+	mediaAPI := config.MediaAPI
+	if mediaAPI == nil {
+		mediaAPI = media.NewMediaAPI("")
+	}
+	urlService := config.URLService
+	if urlService == nil {
+		localePrefix := site.LocalePrefixNever
+		if config.LocalePrefix {
+			localePrefix = site.LocalePrefixExceptDefault
+		}
+		urlService = site.NewURLService(site.URLServiceConfig{LocalePrefix: localePrefix})
+	}
+
+	return &Processor{
+		config:     config,
+		policy:     policy,
+		media:      mediaAPI,
+		urlService: urlService,
+	}
+}
+
+// Process rewrites internal and media links in rich text and sanitizes the result.
+// Links that cannot be resolved are left unchanged.
+func (p *Processor) Process(ctx context.Context, content string, opts Options) (string, error) {
+	if content == "" {
+		return "", nil
+	}
+
+	nodes, err := parseFragment(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse rich text: %w", err)
+	}
+
+	resolved := make(map[string]string)
+	for _, node := range nodes {
+		walkElements(node, func(element *html.Node) {
+			p.rewriteLinks(ctx, element, opts, resolved)
+		})
+	}
+
+	nodes = p.policy.sanitizeNodes(nodes)
+
+	if len(p.config.Hooks) > 0 {
+		for _, node := range nodes {
+			walkElements(node, func(element *html.Node) {
+				for _, hook := range p.config.Hooks {
+					hook(element)
+				}
+			})
+		}
+	}
+
+	return renderNodes(nodes)
+}
+
+// rewriteLinks rewrites the internal and media links of an element
+func (p *Processor) rewriteLinks(ctx context.Context, element *html.Node, opts Options, resolved map[string]string) {
+	for i, attr := range element.Attr {
+		key := strings.ToLower(attr.Key)
+		if key != "href" && key != "src" {
+			continue
+		}
+
+		value := strings.TrimSpace(attr.Val)
+		switch {
+		case isDynamicLink(value):
+			if target, ok := p.resolveDynamicLink(ctx, value, opts, resolved); ok {
+				element.Attr[i].Val = target
+			}
+		case isMediaLink(value):
+			element.Attr[i].Val = p.media.GetMediaURL(strings.TrimPrefix(value, "/"), nil)
+		}
+	}
+}
+
+// resolveDynamicLink resolves a ~/link.aspx?_id= link to a friendly URL
+func (p *Processor) resolveDynamicLink(ctx context.Context, link string, opts Options, resolved map[string]string) (string, bool) {
+	if p.config.LinkResolver == nil {
+		return "", false
+	}
+
+	link, fragment, _ := strings.Cut(link, "#")
+	_, rawQuery, _ := strings.Cut(link, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		debug.RichText("invalid internal link %s: %v", link, err)
+		return "", false
+	}
+	id := formatItemID(query.Get("_id"))
+	if id == "" {
+		return "", false
+	}
+
+	target, ok := resolved[id]
+	if !ok {
+		target, err = p.config.LinkResolver.ResolveItemURL(ctx, id, opts.Site, opts.Language)
+		if err != nil {
+			debug.RichText("failed to resolve internal link %s: %v", id, err)
+			return "", false
+		}
+		target = p.localizeURL(target, opts)
+		resolved[id] = target
+	}
+	if target == "" {
+		debug.RichText("internal link %s not found", id)
+		return "", false
+	}
+
+	if fragment != "" {
+		target += "#" + fragment
+	}
+	return target, true
+}
+
+// localizeURL adds the site path prefix and locale prefix to a resolved item path
+func (p *Processor) localizeURL(path string, opts Options) string {
+	if !strings.HasPrefix(path, "/") {
+		// Empty or absolute URL
+		return path
+	}
+
+	return p.urlService.Path(models.SiteInfo{
+		Name:          opts.Site,
+		Language:      opts.DefaultLanguage,
+		VirtualFolder: opts.PathPrefix,
+	}, path, opts.Language)
+}
+
+// isDynamicLink reports whether a URL is a Sitecore internal link (~/link.aspx?_id=...)
+func isDynamicLink(value string) bool {
+	lower := strings.ToLower(strings.TrimPrefix(value, "/"))
+	return strings.HasPrefix(lower, "~/link.aspx?")
+}
+
+// isMediaLink reports whether a URL is a relative Sitecore media link
+func isMediaLink(value string) bool {
+	lower := strings.ToLower(strings.TrimPrefix(value, "/"))
+	return strings.HasPrefix(lower, "-/media/") ||
+		strings.HasPrefix(lower, "-/jssmedia/") ||
+		strings.HasPrefix(lower, "~/media/")
+}
+
+// formatItemID formats an item ID as {XXXXXXXX-XXXX-XXXX-XXXX-XXXXXXXXXXXX}.
+// Link IDs are usually written without braces and dashes.
+func formatItemID(id string) string {
+	hex := strings.ToUpper(strings.NewReplacer("{", "", "}", "", "-", "").Replace(id))
+	if len(hex) != 32 {
+		return ""
+	}
+	for _, r := range hex {
+		if (r < '0' || r > '9') && (r < 'A' || r > 'F') {
+			return ""
+		}
+	}
+	return fmt.Sprintf("{%s-%s-%s-%s-%s}", hex[0:8], hex[8:12], hex[12:16], hex[16:20], hex[20:32])
+}
+
+// walkElements calls fn for node and every element below it
+func walkElements(node *html.Node, fn func(element *html.Node)) {
+	if node.Type == html.ElementNode {
+		fn(node)
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		walkElements(child, fn)
+	}
+}
+
+// NoopenerHook adds rel="noopener" to links that open in a new browsing context
+func NoopenerHook() Hook {
+	return func(node *html.Node) {
+		if node.Data != "a" || !strings.EqualFold(getAttr(node, "target"), "_blank") {
+			return
+		}
+		rel := getAttr(node, "rel")
+		for token := range strings.FieldsSeq(rel) {
+			if strings.EqualFold(token, "noopener") {
+				return
+			}
+		}
+		setAttr(node, "rel", strings.TrimSpace(rel+" noopener"))
+	}
+}
+
+// LazyImagesHook adds loading="lazy" and decoding="async" to images that don't set them
+func LazyImagesHook() Hook {
+	return func(node *html.Node) {
+		if node.Data != "img" {
+			return
+		}
+		if getAttr(node, "loading") == "" {
+			setAttr(node, "loading", "lazy")
+		}
+		if getAttr(node, "decoding") == "" {
+			setAttr(node, "decoding", "async")
+		}
+	}
+}
+
+// getAttr returns the value of an attribute, or ""
+func getAttr(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if strings.EqualFold(attr.Key, key) {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// setAttr sets the value of an attribute, adding it if missing
+func setAttr(node *html.Node, key, value string) {
+	for i, attr := range node.Attr {
+		if strings.EqualFold(attr.Key, key) {
+			node.Attr[i].Val = value
+			return
+		}
+	}
+	node.Attr = append(node.Attr, html.Attribute{Key: key, Val: value})
+}
+
+type contextKey struct{}
+
+type contextValue struct {
+	processor *Processor
+	opts      Options
+}
+
+// NewContext returns a context carrying a processor and request options.
+// Rich text components rendered with this context are processed.
+func NewContext(ctx context.Context, processor *Processor, opts Options) context.Context {
+	return context.WithValue(ctx, contextKey{}, contextValue{processor: processor, opts: opts})
+}
+
+// FromContext returns the processor and options stored by NewContext
+func FromContext(ctx context.Context) (*Processor, Options, bool) {
+	value, ok := ctx.Value(contextKey{}).(contextValue)
+	if !ok || value.processor == nil {
+		return nil, Options{}, false
+	}
+	return value.processor, value.opts, true
+}
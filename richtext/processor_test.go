@@ -0,0 +1,132 @@
+package richtext
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/guitarrich/content-sdk-go/media"
+)
+
+func TestProcessor_Process_InternalLinks(t *testing.T) {
+	var calls int
+	resolver := LinkResolverFunc(func(ctx context.Context, itemID, site, language string) (string, error) {
+		calls++
+		if site != "mysite" || language != "fr" {
+			t.Errorf("unexpected site/language: %s/%s", site, language)
+		}
+		switch itemID {
+		case "{110D559F-DEA5-42EA-9C1C-8A5DF7E70EF9}":
+			return "/about", nil
+		}
+		return "", nil
+	})
+
+	processor := NewProcessor(Config{
+		LinkResolver: resolver,
+		LocalePrefix: true,
+	})
+
+	input := `<a href="~/link.aspx?_id=110D559FDEA542EA9C1C8A5DF7E70EF9&amp;_z=z">About</a>` +
+		`<a href="/~/link.aspx?_id=110D559FDEA542EA9C1C8A5DF7E70EF9&amp;_z=z#team">Team</a>` +
+		`<a href="~/link.aspx?_id=00000000000000000000000000000000&amp;_z=z">Missing</a>`
+
+	result, err := processor.Process(context.Background(), input, Options{
+		Site:            "mysite",
+		Language:        "fr",
+		DefaultLanguage: "en",
+		PathPrefix:      "/emea",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `<a href="/emea/fr/about">About</a>` +
+		`<a href="/emea/fr/about#team">Team</a>` +
+		`<a href="~/link.aspx?_id=00000000000000000000000000000000&amp;_z=z">Missing</a>`
+	if result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+	if calls != 2 {
+		t.Errorf("expected each item to be resolved once, got %d calls", calls)
+	}
+}
+
+func TestProcessor_Process_DefaultLanguage(t *testing.T) {
+	processor := NewProcessor(Config{
+		LinkResolver: LinkResolverFunc(func(ctx context.Context, itemID, site, language string) (string, error) {
+			return "/", nil
+		}),
+		LocalePrefix: true,
+	})
+
+	result, _ := processor.Process(context.Background(),
+		`<a href="~/link.aspx?_id=110D559FDEA542EA9C1C8A5DF7E70EF9">Home</a>`,
+		Options{Language: "en", DefaultLanguage: "en"})
+	if expected := `<a href="/">Home</a>`; result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
+func TestProcessor_Process_MediaLinks(t *testing.T) {
+	processor := NewProcessor(Config{
+		MediaAPI: media.NewMediaAPI("https://cdn.example.com"),
+	})
+
+	input := `<img src="-/media/images/hero.jpg" alt="Hero">` +
+		`<a href="~/media/110D559FDEA542EA9C1C8A5DF7E70EF9.ashx">PDF</a>` +
+		`<img src="/-/jssmedia/logo.png">`
+
+	result, err := processor.Process(context.Background(), input, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `<img src="https://cdn.example.com/-/media/images/hero.jpg" alt="Hero"/>` +
+		`<a href="https://cdn.example.com/-/media/110D559FDEA542EA9C1C8A5DF7E70EF9.ashx">PDF</a>` +
+		`<img src="https://cdn.example.com/-/jssmedia/logo.png"/>`
+	if result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
+func TestProcessor_Process_Hooks(t *testing.T) {
+	processor := NewProcessor(Config{
+		Hooks: []Hook{NoopenerHook(), LazyImagesHook()},
+	})
+
+	input := `<a href="https://example.com" target="_blank" rel="external">x</a>` +
+		`<a href="/about">y</a>` +
+		`<img src="/a.jpg" loading="eager"><script>alert(1)</script>`
+
+	result, err := processor.Process(context.Background(), input, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `<a href="https://example.com" target="_blank" rel="external noopener">x</a>` +
+		`<a href="/about">y</a>` +
+		`<img src="/a.jpg" loading="eager" decoding="async"/>`
+	if result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if _, _, ok := FromContext(context.Background()); ok {
+		t.Error("expected no processor in empty context")
+	}
+
+	processor := NewProcessor(Config{})
+	ctx := NewContext(context.Background(), processor, Options{Language: "de"})
+
+	got, opts, ok := FromContext(ctx)
+	if !ok || got != processor || opts.Language != "de" {
+		t.Errorf("unexpected context values: %v %v %v", got, opts, ok)
+	}
+
+	result, _ := got.Process(ctx, `<p onclick="x()">Hi</p>`, opts)
+	if strings.Contains(result, "onclick") {
+		t.Errorf("expected sanitized output, got %q", result)
+	}
+}
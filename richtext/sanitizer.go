@@ -0,0 +1,247 @@
+package richtext
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Policy is an allow-list of the elements, attributes and URL schemes kept in rich text.
+// Elements that are not allowed are removed but their content is kept, unless they
+// are listed in DropContent.
+type Policy struct {
+	// Elements maps allowed element names to the attributes allowed on them
+	Elements map[string][]string
+
+	// GlobalAttributes are allowed on every allowed element
+	GlobalAttributes []string
+
+	// AllowDataAttributes allows data-* attributes on every allowed element
+	AllowDataAttributes bool
+
+	// URLSchemes are the schemes allowed in URL attributes (href, src, ...).
+	// Relative URLs are always allowed.
+	URLSchemes []string
+
+	// DropContent lists elements that are removed together with their content
+	DropContent []string
+}
+
+// urlAttributes are the attributes whose values are checked against Policy.URLSchemes
+var urlAttributes = map[string]bool{
+	"href":       true,
+	"src":        true,
+	"cite":       true,
+	"action":     true,
+	"formaction": true,
+	"poster":     true,
+	"background": true,
+	"longdesc":   true,
+}
+
+// DefaultPolicy returns a policy suitable for content authored in the Sitecore rich text editor
+func DefaultPolicy() *Policy {
+	return &Policy{
+		Elements: map[string][]string{
+			"a":          {"href", "target", "rel", "name", "hreflang"},
+			"abbr":       nil,
+			"b":          nil,
+			"blockquote": {"cite"},
+			"br":         nil,
+			"caption":    nil,
+			"cite":       nil,
+			"code":       nil,
+			"col":        {"span", "width"},
+			"colgroup":   {"span", "width"},
+			"dd":         nil,
+			"del":        {"cite", "datetime"},
+			"div":        nil,
+			"dl":         nil,
+			"dt":         nil,
+			"em":         nil,
+			"figcaption": nil,
+			"figure":     nil,
+			"h1":         nil,
+			"h2":         nil,
+			"h3":         nil,
+			"h4":         nil,
+			"h5":         nil,
+			"h6":         nil,
+			"hr":         nil,
+			"i":          nil,
+			"img":        {"src", "alt", "width", "height", "loading", "decoding"},
+			"ins":        {"cite", "datetime"},
+			"li":         {"value"},
+			"mark":       nil,
+			"ol":         {"start", "reversed", "type"},
+			"p":          nil,
+			"pre":        nil,
+			"q":          {"cite"},
+			"s":          nil,
+			"small":      nil,
+			"span":       nil,
+			"strong":     nil,
+			"sub":        nil,
+			"sup":        nil,
+			"table":      {"summary"},
+			"tbody":      nil,
+			"td":         {"colspan", "rowspan", "headers"},
+			"tfoot":      nil,
+			"th":         {"colspan", "rowspan", "headers", "scope"},
+			"thead":      nil,
+			"tr":         nil,
+			"u":          nil,
+			"ul":         nil,
+		},
+		GlobalAttributes: []string{"class", "id", "title", "lang", "dir"},
+		URLSchemes:       []string{"http", "https", "mailto", "tel"},
+		DropContent:      []string{"script", "style", "iframe", "object", "embed", "noscript", "template", "svg", "math"},
+	}
+}
+
+// Sanitize parses an HTML fragment and returns it with everything outside the policy removed
+func (p *Policy) Sanitize(content string) (string, error) {
+	nodes, err := parseFragment(content)
+	if err != nil {
+		return "", err
+	}
+	return renderNodes(p.sanitizeNodes(nodes))
+}
+
+// sanitizeNodes applies the policy to a list of sibling nodes
+func (p *Policy) sanitizeNodes(nodes []*html.Node) []*html.Node {
+	var result []*html.Node
+	for _, node := range nodes {
+		result = append(result, p.sanitizeNode(node)...)
+	}
+	return result
+}
+
+// sanitizeNode returns the nodes that replace node: the node itself, its sanitized
+// children when the element is unwrapped, or nothing when it is dropped
+func (p *Policy) sanitizeNode(node *html.Node) []*html.Node {
+	switch node.Type {
+	case html.TextNode:
+		return []*html.Node{node}
+	case html.ElementNode:
+	default:
+		// Comments, doctypes and anything else are removed
+		return nil
+	}
+
+	name := strings.ToLower(node.Data)
+	for _, dropped := range p.DropContent {
+		if strings.EqualFold(dropped, name) {
+			return nil
+		}
+	}
+
+	children := p.sanitizeNodes(detachChildren(node))
+
+	allowed, ok := p.Elements[name]
+	if !ok {
+		// Unwrap the element, keeping its content
+		return children
+	}
+
+	node.Attr = p.sanitizeAttributes(node.Attr, allowed)
+	for _, child := range children {
+		node.AppendChild(child)
+	}
+	return []*html.Node{node}
+}
+
+// sanitizeAttributes keeps the allowed attributes with safe values
+func (p *Policy) sanitizeAttributes(attrs []html.Attribute, allowed []string) []html.Attribute {
+	var result []html.Attribute
+	for _, attr := range attrs {
+		key := strings.ToLower(attr.Key)
+		if attr.Namespace != "" || strings.HasPrefix(key, "on") {
+			continue
+		}
+		if !p.attributeAllowed(key, allowed) {
+			continue
+		}
+		if urlAttributes[key] && !p.urlAllowed(attr.Val) {
+			continue
+		}
+		attr.Key = key
+		result = append(result, attr)
+	}
+	return result
+}
+
+// attributeAllowed reports whether an attribute is allowed on an element
+func (p *Policy) attributeAllowed(key string, allowed []string) bool {
+	if p.AllowDataAttributes && strings.HasPrefix(key, "data-") {
+		return true
+	}
+	for _, attr := range allowed {
+		if strings.EqualFold(attr, key) {
+			return true
+		}
+	}
+	for _, attr := range p.GlobalAttributes {
+		if strings.EqualFold(attr, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// urlAllowed reports whether a URL is relative or uses an allowed scheme
+func (p *Policy) urlAllowed(value string) bool {
+	// Browsers ignore whitespace and control characters inside schemes ("java\tscript:")
+	cleaned := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, value)
+
+	scheme, _, ok := strings.Cut(cleaned, ":")
+	if !ok || strings.ContainsAny(scheme, "/?#") {
+		// Relative URL
+		return true
+	}
+	for _, allowed := range p.URLSchemes {
+		if strings.EqualFold(allowed, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseFragment parses an HTML fragment in the context of a <body> element
+func parseFragment(content string) ([]*html.Node, error) {
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(content), body)
+	if err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// renderNodes renders a list of nodes back to HTML
+func renderNodes(nodes []*html.Node) (string, error) {
+	var builder strings.Builder
+	for _, node := range nodes {
+		if err := html.Render(&builder, node); err != nil {
+			return "", err
+		}
+	}
+	return builder.String(), nil
+}
+
+// detachChildren removes and returns the children of a node
+func detachChildren(node *html.Node) []*html.Node {
+	var children []*html.Node
+	for child := node.FirstChild; child != nil; {
+		next := child.NextSibling
+		node.RemoveChild(child)
+		children = append(children, child)
+		child = next
+	}
+	return children
+}
@@ -0,0 +1,80 @@
+package richtext
+
+import "testing"
+
+func TestPolicy_Sanitize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "keeps allowed markup",
+			input:    `<p class="intro">Hello <strong>world</strong></p>`,
+			expected: `<p class="intro">Hello <strong>world</strong></p>`,
+		},
+		{
+			name:     "drops script with content",
+			input:    `<p>Hi</p><script>alert(1)</script>`,
+			expected: `<p>Hi</p>`,
+		},
+		{
+			name:     "unwraps disallowed elements",
+			input:    `<font color="red">Red <em>text</em></font>`,
+			expected: `Red <em>text</em>`,
+		},
+		{
+			name:     "removes event handlers and unknown attributes",
+			input:    `<img src="/a.jpg" onerror="alert(1)" style="x" alt="A">`,
+			expected: `<img src="/a.jpg" alt="A"/>`,
+		},
+		{
+			name:     "removes javascript urls",
+			input:    `<a href="java	script:alert(1)">x</a><a href="JAVASCRIPT:alert(1)">y</a>`,
+			expected: `<a>x</a><a>y</a>`,
+		},
+		{
+			name:     "keeps relative and allowed scheme urls",
+			input:    `<a href="/about?a=1:2">a</a><a href="mailto:a@b.c">b</a><a href="https://example.com">c</a>`,
+			expected: `<a href="/about?a=1:2">a</a><a href="mailto:a@b.c">b</a><a href="https://example.com">c</a>`,
+		},
+		{
+			name:     "removes comments",
+			input:    `<p>a<!-- secret -->b</p>`,
+			expected: `<p>ab</p>`,
+		},
+		{
+			name:     "escapes text",
+			input:    `<p>&lt;script&gt;</p>`,
+			expected: `<p>&lt;script&gt;</p>`,
+		},
+	}
+
+	policy := DefaultPolicy()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := policy.Sanitize(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestPolicy_Sanitize_Custom(t *testing.T) {
+	policy := &Policy{
+		Elements:            map[string][]string{"div": nil},
+		AllowDataAttributes: true,
+	}
+
+	result, err := policy.Sanitize(`<div data-id="1" class="x"><p>text</p></div>`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `<div data-id="1">text</div>`; result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
@@ -0,0 +1,599 @@
+package seo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/guitarrich/content-sdk-go/debug"
+	layoutservice "github.com/guitarrich/content-sdk-go/layoutService"
+	"github.com/guitarrich/content-sdk-go/models"
+	"github.com/guitarrich/content-sdk-go/site"
+)
+
+// HeadFields maps head properties to route field names. Each property lists
+// candidate fields, and the first one with a value is used.
+type HeadFields struct {
+	Title         []string
+	Description   []string
+	Keywords      []string
+	Image         []string
+	OGTitle       []string
+	OGDescription []string
+	NoIndex       []string
+	NoFollow      []string
+	Author        []string
+	DatePublished []string
+}
+
+// DefaultHeadFields are the conventional route field names
+var DefaultHeadFields = HeadFields{
+	Title:         []string{"pageTitle", "metadataTitle", "Title"},
+	Description:   []string{"metadataDescription", "MetaDescription", "Description"},
+	Keywords:      []string{"metadataKeywords", "MetaKeywords"},
+	Image:         []string{"ogImage", "OpenGraphImageUrl", "thumbnailImage"},
+	OGTitle:       []string{"ogTitle", "OpenGraphTitle"},
+	OGDescription: []string{"ogDescription", "OpenGraphDescription"},
+	NoIndex:       []string{"noIndex", "NoIndex"},
+	NoFollow:      []string{"noFollow", "NoFollow"},
+	Author:        []string{"author", "Author"},
+	DatePublished: []string{"datePublished", "publishDate", "ArticleDate"},
+}
+
+// HeadConfig contains configuration for the head builder
+type HeadConfig struct {
+	// Fields maps head properties to route fields; empty properties use DefaultHeadFields
+	Fields HeadFields
+
+	// SiteName is the og:site_name
+	SiteName string
+
+	// TitleFormat formats the <title>, e.g. "%s | Example" (default: the page title)
+	TitleFormat string
+
+	// BaseURL is the public origin, used for the default canonical URL, og:url,
+	// breadcrumbs and relative image URLs (e.g. "https://www.example.com").
+	// It replaces the host name of HeadOptions.Site.
+	BaseURL string
+
+	// URLService builds the canonical, breadcrumb and hreflang URLs of
+	// HeadOptions.Site (default: site.NewURLService with the default locale prefix)
+	URLService site.URLService
+
+	// DefaultImage is used when the route has no image (optional)
+	DefaultImage string
+
+	// TwitterSite is the site's Twitter handle, e.g. "@example" (optional)
+	TwitterSite string
+
+	// Organization is added as JSON-LD to every page and used as article publisher (optional)
+	Organization *Organization
+
+	// ArticleTemplates are the route template names rendered as articles,
+	// with og:type article and Article JSON-LD
+	ArticleTemplates []string
+}
+
+// HeadOptions contains the page-specific inputs of a head
+type HeadOptions struct {
+	// Site is the page's site. With a site, URLs include its path prefix and
+	// locale prefix, and the hreflang links are fetched through the URL service.
+	Site *models.SiteInfo
+
+	// Canonical is the absolute canonical URL (default: the page's URL)
+	Canonical string
+
+	// Alternates are the hreflang links of the page's language versions
+	// (default: fetched for Site)
+	Alternates []AlternateLink
+
+	// Breadcrumbs are the page's ancestors and the page itself (default:
+	// derived from the page path when Site or BaseURL is set)
+	Breadcrumbs []Breadcrumb
+}
+
+// Head is the SEO head content of a page
+type Head struct {
+	// Title is the <title>
+	Title string
+
+	// Description is the meta description
+	Description string
+
+	// Keywords is the meta keywords
+	Keywords string
+
+	// Robots is the meta robots value, e.g. "noindex, nofollow"; empty when the page is indexable
+	Robots string
+
+	// Canonical is the canonical URL
+	Canonical string
+
+	// Alternates are the hreflang links
+	Alternates []AlternateLink
+
+	// OpenGraph are the og: meta tags
+	OpenGraph OpenGraph
+
+	// Twitter are the twitter: meta tags
+	Twitter TwitterCard
+
+	// JSONLD are the structured data objects, each written to its own script
+	JSONLD []any
+}
+
+// AlternateLink is a language version of a page
+type AlternateLink struct {
+	// Hreflang is the language, e.g. "en-US" or "x-default"
+	Hreflang string
+
+	// Href is the absolute URL of the version
+	Href string
+}
+
+// OpenGraph contains the Open Graph properties of a page
+type OpenGraph struct {
+	Type        string
+	Title       string
+	Description string
+	URL         string
+	Image       string
+	ImageAlt    string
+	SiteName    string
+	Locale      string
+}
+
+// TwitterCard contains the Twitter card properties of a page
+type TwitterCard struct {
+	Card        string
+	Site        string
+	Title       string
+	Description string
+	Image       string
+}
+
+// Links returns the canonical and hreflang links, e.g. for models.Page.HeadLinks
+func (h *Head) Links() []models.HTMLLink {
+	var links []models.HTMLLink
+	if h.Canonical != "" {
+		links = append(links, models.HTMLLink{Rel: "canonical", Href: h.Canonical})
+	}
+	for _, alternate := range h.Alternates {
+		links = append(links, models.HTMLLink{Rel: "alternate", Href: alternate.Href, Hreflang: alternate.Hreflang})
+	}
+	return links
+}
+
+// HeadBuilder builds SEO head content from route fields
+type HeadBuilder struct {
+	config     HeadConfig
+	urlService site.URLService
+}
+
+// NewHeadBuilder creates a new head builder
+func NewHeadBuilder(config HeadConfig) *HeadBuilder {
+	defaults := DefaultHeadFields
+	fields := &config.Fields
+	for _, mapping := range []struct {
+		field    *[]string
+		fallback []string
+	}{
+		{&fields.Title, defaults.Title},
+		{&fields.Description, defaults.Description},
+		{&fields.Keywords, defaults.Keywords},
+		{&fields.Image, defaults.Image},
+		{&fields.OGTitle, defaults.OGTitle},
+		{&fields.OGDescription, defaults.OGDescription},
+		{&fields.NoIndex, defaults.NoIndex},
+		{&fields.NoFollow, defaults.NoFollow},
+		{&fields.Author, defaults.Author},
+		{&fields.DatePublished, defaults.DatePublished},
+	} {
+		if len(*mapping.field) == 0 {
+			*mapping.field = mapping.fallback
+		}
+	}
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+	urlService := config.URLService
+	if urlService == nil {
+		urlService = site.NewURLService(site.URLServiceConfig{})
+	}
+
+	return &HeadBuilder{config: config, urlService: urlService}
+}
+
+// Build builds the head of a page
+// It is BuildContext with a background context.
+func (b *HeadBuilder) Build(page *models.Page, options HeadOptions) *Head {
+	return b.BuildContext(context.Background(), page, options)
+}
+
+// BuildContext builds the head of a page. Failing to fetch the hreflang
+// links is logged and leaves them out.
+func (b *HeadBuilder) BuildContext(ctx context.Context, page *models.Page, options HeadOptions) *Head {
+	route := pageRoute(page)
+	var fields map[string]any
+	title := ""
+	if route != nil {
+		fields = route.Fields
+		title = route.Name
+		if route.DisplayName != nil && *route.DisplayName != "" {
+			title = *route.DisplayName
+		}
+	}
+	if value := b.text(fields, b.config.Fields.Title); value != "" {
+		title = value
+	}
+
+	head := &Head{
+		Title:       title,
+		Description: b.text(fields, b.config.Fields.Description),
+		Keywords:    b.text(fields, b.config.Fields.Keywords),
+		Robots:      b.robots(fields),
+		Canonical:   options.Canonical,
+		Alternates:  options.Alternates,
+	}
+	if b.config.TitleFormat != "" && title != "" {
+		head.Title = fmt.Sprintf(b.config.TitleFormat, title)
+	}
+	if head.Canonical == "" && page != nil {
+		head.Canonical = b.routeURL(page, options, pagePath(page))
+	}
+	if head.Alternates == nil {
+		head.Alternates = b.alternates(ctx, page, route, options)
+	}
+
+	image, imageAlt := b.image(fields)
+	isArticle := route != nil && route.TemplateName != nil && containsFold(b.config.ArticleTemplates, *route.TemplateName)
+
+	head.OpenGraph = OpenGraph{
+		Type:        "website",
+		Title:       firstNonEmpty(b.text(fields, b.config.Fields.OGTitle), title),
+		Description: firstNonEmpty(b.text(fields, b.config.Fields.OGDescription), head.Description),
+		URL:         head.Canonical,
+		Image:       image,
+		ImageAlt:    imageAlt,
+		SiteName:    b.config.SiteName,
+	}
+	if page != nil && page.Language != "" {
+		head.OpenGraph.Locale = strings.ReplaceAll(page.Language, "-", "_")
+	}
+	if isArticle {
+		head.OpenGraph.Type = "article"
+	}
+
+	head.Twitter = TwitterCard{
+		Card:        "summary",
+		Site:        b.config.TwitterSite,
+		Title:       head.OpenGraph.Title,
+		Description: head.OpenGraph.Description,
+		Image:       image,
+	}
+	if image != "" {
+		head.Twitter.Card = "summary_large_image"
+	}
+
+	// Structured data
+	if b.config.Organization != nil {
+		head.JSONLD = append(head.JSONLD, b.config.Organization)
+	}
+	breadcrumbs := options.Breadcrumbs
+	if breadcrumbs == nil {
+		breadcrumbs = b.breadcrumbs(page, options, title)
+	}
+	if len(breadcrumbs) > 1 {
+		head.JSONLD = append(head.JSONLD, BreadcrumbList(breadcrumbs))
+	}
+	if isArticle {
+		head.JSONLD = append(head.JSONLD, &Article{
+			Headline:      title,
+			Description:   head.OpenGraph.Description,
+			Image:         image,
+			Author:        b.text(fields, b.config.Fields.Author),
+			DatePublished: b.date(fields, b.config.Fields.DatePublished),
+			URL:           head.Canonical,
+			Publisher:     b.config.Organization,
+		})
+	}
+
+	return head
+}
+
+// text returns the first non-empty text field
+func (b *HeadBuilder) text(fields map[string]any, names []string) string {
+	for _, name := range names {
+		if value := strings.TrimSpace(models.GetTextField(fields, name).Value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// date returns the first date field as an ISO 8601 date time
+func (b *HeadBuilder) date(fields map[string]any, names []string) string {
+	for _, name := range names {
+		field := models.GetDateField(fields, name)
+		if t, err := field.Time(); err == nil && !t.IsZero() {
+			return t.Format("2006-01-02T15:04:05Z07:00")
+		}
+	}
+	return ""
+}
+
+// image returns the absolute URL and alt text of the first image field, or the default image
+func (b *HeadBuilder) image(fields map[string]any) (string, string) {
+	for _, name := range b.config.Fields.Image {
+		fieldData := models.GetFieldByName(fields, name)
+		if fieldData == nil {
+			continue
+		}
+		image := models.ExtractImageFieldFromMap(fieldData)
+		if src := image.GetSrc(); src != "" {
+			return b.absolute(src), image.GetAlt()
+		}
+		// Text fields holding an image URL
+		if src := strings.TrimSpace(models.ExtractTextFieldFromMap(fieldData).Value); src != "" {
+			return b.absolute(src), ""
+		}
+	}
+	if b.config.DefaultImage != "" {
+		return b.absolute(b.config.DefaultImage), ""
+	}
+	return "", ""
+}
+
+// robots returns the meta robots value of the noindex and nofollow fields
+func (b *HeadBuilder) robots(fields map[string]any) string {
+	var directives []string
+	if b.checked(fields, b.config.Fields.NoIndex) {
+		directives = append(directives, "noindex")
+	}
+	if b.checked(fields, b.config.Fields.NoFollow) {
+		directives = append(directives, "nofollow")
+	}
+	return strings.Join(directives, ", ")
+}
+
+// checked reports whether any of the checkbox fields is checked
+func (b *HeadBuilder) checked(fields map[string]any, names []string) bool {
+	for _, name := range names {
+		if fieldData := models.GetFieldByName(fields, name); fieldData != nil && models.ExtractCheckboxFieldFromMap(fieldData).Value {
+			return true
+		}
+	}
+	return false
+}
+
+// breadcrumbs derives breadcrumbs from the page path, naming ancestors after their path segments
+func (b *HeadBuilder) breadcrumbs(page *models.Page, options HeadOptions, title string) []Breadcrumb {
+	if page == nil {
+		return nil
+	}
+	homeURL := b.routeURL(page, options, "/")
+	segments := strings.FieldsFunc(pagePath(page), func(r rune) bool { return r == '/' })
+	if homeURL == "" || len(segments) == 0 {
+		return nil
+	}
+
+	home := firstNonEmpty(b.config.SiteName, "Home")
+	breadcrumbs := []Breadcrumb{{Name: home, URL: homeURL}}
+	path := ""
+	for i, segment := range segments {
+		path += "/" + segment
+		name := segmentName(segment)
+		if i == len(segments)-1 && title != "" {
+			name = title
+		}
+		breadcrumbs = append(breadcrumbs, Breadcrumb{Name: name, URL: b.routeURL(page, options, path)})
+	}
+	return breadcrumbs
+}
+
+// routeURL returns the public URL of a route path in the page's language, or
+// "" without Site and BaseURL
+func (b *HeadBuilder) routeURL(page *models.Page, options HeadOptions, path string) string {
+	if options.Site == nil {
+		if b.config.BaseURL == "" {
+			return ""
+		}
+		return b.urlService.URL(models.SiteInfo{HostName: b.config.BaseURL}, path, "")
+	}
+	return b.urlService.URL(b.site(options), path, pageLanguage(page))
+}
+
+// alternates fetches the hreflang links of the page's item
+func (b *HeadBuilder) alternates(ctx context.Context, page *models.Page, route *layoutservice.RouteData, options HeadOptions) []AlternateLink {
+	if page == nil || options.Site == nil {
+		return nil
+	}
+	itemID := page.ItemID
+	if itemID == "" && route != nil && route.ItemID != nil {
+		itemID = *route.ItemID
+	}
+
+	urls, err := b.urlService.Alternates(ctx, b.site(options), itemID, pagePath(page), pageLanguage(page))
+	if err != nil {
+		debug.Common("failed to fetch hreflang alternates of %s: %v", page.Path, err)
+		return nil
+	}
+	var alternates []AlternateLink
+	for _, alternate := range urls {
+		alternates = append(alternates, AlternateLink{Hreflang: alternate.Language, Href: alternate.URL})
+	}
+	return alternates
+}
+
+// site returns the page's site with BaseURL as host name
+func (b *HeadBuilder) site(options HeadOptions) models.SiteInfo {
+	siteInfo := *options.Site
+	if b.config.BaseURL != "" {
+		siteInfo.HostName = b.config.BaseURL
+	}
+	return siteInfo
+}
+
+// absolute resolves a root-relative URL against the base URL
+func (b *HeadBuilder) absolute(link string) string {
+	if strings.HasPrefix(link, "/") && !strings.HasPrefix(link, "//") && b.config.BaseURL != "" {
+		return b.config.BaseURL + link
+	}
+	return link
+}
+
+// pageRoute returns the route of a page's layout data
+func pageRoute(page *models.Page) *layoutservice.RouteData {
+	if page == nil {
+		return nil
+	}
+	layoutData, ok := page.LayoutData.(*layoutservice.LayoutServiceData)
+	if !ok || layoutData == nil {
+		return nil
+	}
+	return layoutData.Sitecore.Route
+}
+
+// pageLanguage returns the language the page was requested in
+func pageLanguage(page *models.Page) string {
+	return firstNonEmpty(page.RequestedLanguage, page.Language)
+}
+
+// pagePath returns the normalized path of a page
+func pagePath(page *models.Page) string {
+	return "/" + strings.Trim(page.Path, "/")
+}
+
+// segmentName turns a path segment into a name ("about-us" becomes "About Us")
+func segmentName(segment string) string {
+	if unescaped, err := url.PathUnescape(segment); err == nil {
+		segment = unescaped
+	}
+	words := strings.FieldsFunc(segment, func(r rune) bool { return r == '-' || r == '_' })
+	for i, word := range words {
+		first, size := utf8.DecodeRuneInString(word)
+		words[i] = string(unicode.ToUpper(first)) + word[size:]
+	}
+	return strings.Join(words, " ")
+}
+
+// firstNonEmpty returns the first non-empty value
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaContext is the JSON-LD context of schema.org types
+const schemaContext = "https://schema.org"
+
+// Organization is the schema.org Organization of the site
+type Organization struct {
+	Name   string
+	URL    string
+	Logo   string
+	SameAs []string
+}
+
+// MarshalJSON encodes the organization as JSON-LD
+func (o *Organization) MarshalJSON() ([]byte, error) {
+	data := o.jsonLD()
+	data["@context"] = schemaContext
+	return json.Marshal(data)
+}
+
+// jsonLD returns the organization's properties
+func (o *Organization) jsonLD() map[string]any {
+	data := map[string]any{"@type": "Organization", "name": o.Name}
+	if o.URL != "" {
+		data["url"] = o.URL
+	}
+	if o.Logo != "" {
+		data["logo"] = o.Logo
+	}
+	if len(o.SameAs) > 0 {
+		data["sameAs"] = o.SameAs
+	}
+	return data
+}
+
+// Breadcrumb is an item of a breadcrumb trail
+type Breadcrumb struct {
+	Name string
+	URL  string
+}
+
+// BreadcrumbList is the schema.org BreadcrumbList of a page
+type BreadcrumbList []Breadcrumb
+
+// MarshalJSON encodes the breadcrumbs as JSON-LD
+func (l BreadcrumbList) MarshalJSON() ([]byte, error) {
+	items := make([]map[string]any, len(l))
+	for i, breadcrumb := range l {
+		items[i] = map[string]any{
+			"@type":    "ListItem",
+			"position": i + 1,
+			"name":     breadcrumb.Name,
+			"item":     breadcrumb.URL,
+		}
+	}
+	return json.Marshal(map[string]any{
+		"@context":        schemaContext,
+		"@type":           "BreadcrumbList",
+		"itemListElement": items,
+	})
+}
+
+// Article is the schema.org Article of a page
+type Article struct {
+	Headline      string
+	Description   string
+	Image         string
+	Author        string
+	DatePublished string
+	DateModified  string
+	URL           string
+	Publisher     *Organization
+}
+
+// MarshalJSON encodes the article as JSON-LD
+func (a *Article) MarshalJSON() ([]byte, error) {
+	data := map[string]any{
+		"@context": schemaContext,
+		"@type":    "Article",
+		"headline": a.Headline,
+	}
+	for key, value := range map[string]string{
+		"description":   a.Description,
+		"image":         a.Image,
+		"datePublished": a.DatePublished,
+		"dateModified":  a.DateModified,
+		"url":           a.URL,
+	} {
+		if value != "" {
+			data[key] = value
+		}
+	}
+	if a.Author != "" {
+		data["author"] = map[string]any{"@type": "Person", "name": a.Author}
+	}
+	if a.Publisher != nil {
+		data["publisher"] = a.Publisher.jsonLD()
+	}
+	return json.Marshal(data)
+}
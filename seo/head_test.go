@@ -0,0 +1,250 @@
+package seo_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	layoutservice "github.com/guitarrich/content-sdk-go/layoutService"
+	"github.com/guitarrich/content-sdk-go/models"
+	"github.com/guitarrich/content-sdk-go/seo"
+	"github.com/guitarrich/content-sdk-go/site"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPage creates a page with a route decoded from JSON
+func newPage(t *testing.T, path, route string) *models.Page {
+	t.Helper()
+	var layoutData layoutservice.LayoutServiceData
+	require.NoError(t, json.Unmarshal([]byte(`{"sitecore": {"route": `+route+`}}`), &layoutData))
+	return &models.Page{Path: path, Language: "en", LayoutData: &layoutData}
+}
+
+// jsonLD encodes the structured data of a head by @type
+func jsonLD(t *testing.T, head *seo.Head) map[string]map[string]any {
+	t.Helper()
+	objects := map[string]map[string]any{}
+	for _, data := range head.JSONLD {
+		encoded, err := json.Marshal(data)
+		require.NoError(t, err)
+		var object map[string]any
+		require.NoError(t, json.Unmarshal(encoded, &object))
+		objects[object["@type"].(string)] = object
+	}
+	return objects
+}
+
+func TestHeadBuilder_FieldFallbackOrder(t *testing.T) {
+	builder := seo.NewHeadBuilder(seo.HeadConfig{})
+
+	tests := []struct {
+		name     string
+		route    string
+		expected string
+	}{
+		{"first candidate", `{"name": "about", "fields": {"pageTitle": {"value": "Page Title"}, "Title": {"value": "Title"}}}`, "Page Title"},
+		{"empty candidates are skipped", `{"name": "about", "fields": {"pageTitle": {"value": " "}, "Title": {"value": "Title"}}}`, "Title"},
+		{"jsonValue shape", `{"name": "about", "fields": {"metadataTitle": {"jsonValue": {"value": "Metadata"}}}}`, "Metadata"},
+		{"display name", `{"name": "about", "displayName": "About Us", "fields": {}}`, "About Us"},
+		{"item name", `{"name": "about"}`, "about"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			head := builder.Build(newPage(t, "/about", tt.route), seo.HeadOptions{})
+			assert.Equal(t, tt.expected, head.Title)
+		})
+	}
+}
+
+func TestHeadBuilder_CustomFields(t *testing.T) {
+	builder := seo.NewHeadBuilder(seo.HeadConfig{Fields: seo.HeadFields{
+		Title:       []string{"headline"},
+		Description: []string{"teaser"},
+	}})
+	page := newPage(t, "/news", `{"name": "news", "fields": {
+		"headline": {"value": "Headline"},
+		"pageTitle": {"value": "Ignored"},
+		"teaser": {"value": "Teaser"},
+		"metadataKeywords": {"value": "news, launches"}
+	}}`)
+
+	head := builder.Build(page, seo.HeadOptions{})
+	assert.Equal(t, "Headline", head.Title)
+	assert.Equal(t, "Teaser", head.Description)
+	// Unmapped properties keep the default candidates
+	assert.Equal(t, "news, launches", head.Keywords)
+	assert.Equal(t, "Headline", head.OpenGraph.Title)
+	assert.Equal(t, "Teaser", head.Twitter.Description)
+}
+
+func TestHeadBuilder_Robots(t *testing.T) {
+	builder := seo.NewHeadBuilder(seo.HeadConfig{})
+
+	tests := []struct {
+		name     string
+		fields   string
+		expected string
+	}{
+		{"indexable", `{}`, ""},
+		{"noindex", `{"noIndex": {"value": true}}`, "noindex"},
+		{"nofollow as string", `{"NoFollow": {"value": "1"}}`, "nofollow"},
+		{"both", `{"noIndex": {"value": "true"}, "noFollow": {"value": true}}`, "noindex, nofollow"},
+		{"unchecked", `{"noIndex": {"value": false}, "noFollow": {"value": ""}}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			head := builder.Build(newPage(t, "/", `{"name": "home", "fields": `+tt.fields+`}`), seo.HeadOptions{})
+			assert.Equal(t, tt.expected, head.Robots)
+		})
+	}
+}
+
+func TestHeadBuilder_TitleFormat(t *testing.T) {
+	builder := seo.NewHeadBuilder(seo.HeadConfig{TitleFormat: "%s | Example"})
+
+	head := builder.Build(newPage(t, "/about", `{"name": "about", "fields": {"pageTitle": {"value": "About"}}}`), seo.HeadOptions{})
+	assert.Equal(t, "About | Example", head.Title)
+	// Social titles use the unformatted title
+	assert.Equal(t, "About", head.OpenGraph.Title)
+	assert.Equal(t, "About", head.Twitter.Title)
+}
+
+func TestHeadBuilder_OpenGraphAndTwitter(t *testing.T) {
+	builder := seo.NewHeadBuilder(seo.HeadConfig{
+		SiteName:    "Example",
+		BaseURL:     "https://www.example.com/",
+		TwitterSite: "@example",
+	})
+	page := newPage(t, "/about", `{"name": "about", "fields": {
+		"pageTitle": {"value": "About"},
+		"ogTitle": {"value": "About Example"},
+		"metadataDescription": {"value": "Who we are"},
+		"ogImage": {"value": {"src": "/-/media/team.jpg", "alt": "Team"}}
+	}}`)
+	page.Language = "en-GB"
+
+	head := builder.Build(page, seo.HeadOptions{})
+	assert.Equal(t, "https://www.example.com/about", head.Canonical)
+	assert.Equal(t, seo.OpenGraph{
+		Type:        "website",
+		Title:       "About Example",
+		Description: "Who we are",
+		URL:         "https://www.example.com/about",
+		Image:       "https://www.example.com/-/media/team.jpg",
+		ImageAlt:    "Team",
+		SiteName:    "Example",
+		Locale:      "en_GB",
+	}, head.OpenGraph)
+	assert.Equal(t, seo.TwitterCard{
+		Card:        "summary_large_image",
+		Site:        "@example",
+		Title:       "About Example",
+		Description: "Who we are",
+		Image:       "https://www.example.com/-/media/team.jpg",
+	}, head.Twitter)
+
+	// Without an image the card is a summary
+	head = builder.Build(newPage(t, "/", `{"name": "home"}`), seo.HeadOptions{})
+	assert.Equal(t, "summary", head.Twitter.Card)
+	assert.Empty(t, head.OpenGraph.Image)
+}
+
+func TestHeadBuilder_Article(t *testing.T) {
+	organization := &seo.Organization{Name: "Example", URL: "https://www.example.com"}
+	builder := seo.NewHeadBuilder(seo.HeadConfig{
+		BaseURL:          "https://www.example.com",
+		Organization:     organization,
+		ArticleTemplates: []string{"Article Page"},
+	})
+	page := newPage(t, "/news/launch", `{"name": "launch", "templateName": "article page", "fields": {
+		"pageTitle": {"value": "Launch"},
+		"author": {"value": "Sam Writer"},
+		"datePublished": {"value": "20240115T093000Z"}
+	}}`)
+
+	head := builder.Build(page, seo.HeadOptions{})
+	assert.Equal(t, "article", head.OpenGraph.Type)
+
+	objects := jsonLD(t, head)
+	assert.Contains(t, objects, "Organization")
+	article := objects["Article"]
+	require.NotNil(t, article)
+	assert.Equal(t, "https://schema.org", article["@context"])
+	assert.Equal(t, "Launch", article["headline"])
+	assert.Equal(t, "2024-01-15T09:30:00Z", article["datePublished"])
+	assert.Equal(t, map[string]any{"@type": "Person", "name": "Sam Writer"}, article["author"])
+	assert.Equal(t, "Example", article["publisher"].(map[string]any)["name"])
+
+	// Other templates are websites without Article data
+	head = builder.Build(newPage(t, "/news", `{"name": "news", "templateName": "Landing Page"}`), seo.HeadOptions{})
+	assert.Equal(t, "website", head.OpenGraph.Type)
+	assert.NotContains(t, jsonLD(t, head), "Article")
+}
+
+func TestHeadBuilder_Breadcrumbs(t *testing.T) {
+	builder := seo.NewHeadBuilder(seo.HeadConfig{SiteName: "Beispiel", BaseURL: "https://www.example.de"})
+	page := newPage(t, "/über-uns/kontakt", `{"name": "kontakt", "displayName": "Kontakt"}`)
+
+	breadcrumbs := jsonLD(t, builder.Build(page, seo.HeadOptions{}))["BreadcrumbList"]
+	require.NotNil(t, breadcrumbs)
+	items := breadcrumbs["itemListElement"].([]any)
+	require.Len(t, items, 3)
+
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = item.(map[string]any)["name"].(string)
+	}
+	assert.Equal(t, []string{"Beispiel", "Über Uns", "Kontakt"}, names)
+	assert.Equal(t, "https://www.example.de/über-uns", items[1].(map[string]any)["item"])
+	assert.Equal(t, float64(2), items[1].(map[string]any)["position"])
+
+	// Explicit breadcrumbs replace the derived ones; the home page has none
+	head := builder.Build(page, seo.HeadOptions{Breadcrumbs: []seo.Breadcrumb{{Name: "A", URL: "/a"}, {Name: "B", URL: "/b"}}})
+	assert.Len(t, jsonLD(t, head)["BreadcrumbList"]["itemListElement"], 2)
+	assert.NotContains(t, jsonLD(t, builder.Build(newPage(t, "/", `{"name": "home"}`), seo.HeadOptions{})), "BreadcrumbList")
+}
+
+// languagesClient answers ItemLanguagesQuery and records the variables
+type languagesClient struct {
+	variables map[string]any
+}
+
+func (c *languagesClient) Request(ctx context.Context, query string, variables map[string]any) (map[string]any, error) {
+	c.variables = variables
+	return map[string]any{"item": map[string]any{"languages": []any{
+		map[string]any{"language": map[string]any{"name": "en"}},
+		map[string]any{"language": map[string]any{"name": "fr-CA"}},
+	}}}, nil
+}
+
+func TestHeadBuilder_Alternates(t *testing.T) {
+	client := &languagesClient{}
+	builder := seo.NewHeadBuilder(seo.HeadConfig{
+		URLService: site.NewURLService(site.URLServiceConfig{GraphQLClient: client}),
+	})
+	page := newPage(t, "/about", `{"name": "about", "itemId": "{A1B2}"}`)
+	page.Language, page.RequestedLanguage = "fr", "fr-CA"
+	info := &models.SiteInfo{Name: "main", HostName: "www.example.com", Language: "en"}
+
+	head := builder.BuildContext(context.Background(), page, seo.HeadOptions{Site: info})
+	assert.Equal(t, map[string]any{"path": "{A1B2}", "language": "fr-CA"}, client.variables)
+	assert.Equal(t, []seo.AlternateLink{
+		{Hreflang: "en", Href: "https://www.example.com/about"},
+		{Hreflang: "fr-CA", Href: "https://www.example.com/fr-CA/about"},
+	}, head.Alternates)
+}
+
+func TestHead_Links(t *testing.T) {
+	head := &seo.Head{
+		Canonical:  "https://www.example.com/about",
+		Alternates: []seo.AlternateLink{{Hreflang: "fr", Href: "https://www.example.com/fr/about"}},
+	}
+
+	assert.Equal(t, []models.HTMLLink{
+		{Rel: "canonical", Href: "https://www.example.com/about"},
+		{Rel: "alternate", Href: "https://www.example.com/fr/about", Hreflang: "fr"},
+	}, head.Links())
+}
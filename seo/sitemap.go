@@ -4,11 +4,14 @@ import (
 	"context"
 	"encoding/xml"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/guitarrich/content-sdk-go/debug"
 	"github.com/guitarrich/content-sdk-go/graphql"
+	"github.com/guitarrich/content-sdk-go/i18n"
 	"github.com/guitarrich/content-sdk-go/models"
+	"github.com/guitarrich/content-sdk-go/site"
 )
 
 // SitemapXmlService generates XML sitemaps
@@ -20,20 +23,45 @@ type SitemapXmlService interface {
 // SitemapXmlServiceConfig contains configuration for sitemap service
 type SitemapXmlServiceConfig struct {
 	GraphQLClient graphql.Client
-	BaseURL       string
+
+	// BaseURL is the public origin of the sitemap URLs. It replaces the host
+	// name of sites resolved through Sites.
+	BaseURL string
+
+	// URLService builds the sitemap URLs (default: site.NewURLService with the
+	// default locale prefix, as HeadBuilder uses). Pass the head builder's
+	// URLService so sitemap and canonical URLs match.
+	URLService site.URLService
+
+	// Sites resolves the host name, path prefix and default language of the sites (optional)
+	Sites site.SiteResolver
+
+	// LanguageFallback includes routes that only exist in a fallback language (optional)
+	LanguageFallback *i18n.LanguageFallback
 }
 
 // sitemapXmlServiceImpl is the default implementation
 type sitemapXmlServiceImpl struct {
-	graphQLClient graphql.Client
-	baseURL       string
+	graphQLClient    graphql.Client
+	baseURL          string
+	urlService       site.URLService
+	sites            site.SiteResolver
+	languageFallback *i18n.LanguageFallback
 }
 
 // NewSitemapXmlService creates a new sitemap service
 func NewSitemapXmlService(config SitemapXmlServiceConfig) SitemapXmlService {
+	urlService := config.URLService
+	if urlService == nil {
+		urlService = site.NewURLService(site.URLServiceConfig{})
+	}
+
 	return &sitemapXmlServiceImpl{
-		graphQLClient: config.GraphQLClient,
-		baseURL:       config.BaseURL,
+		graphQLClient:    config.GraphQLClient,
+		baseURL:          strings.TrimSuffix(config.BaseURL, "/"),
+		urlService:       urlService,
+		sites:            config.Sites,
+		languageFallback: config.LanguageFallback,
 	}
 }
 
@@ -48,23 +76,40 @@ func (s *sitemapXmlServiceImpl) FetchSitemap(
 	allEntries := []models.SitemapEntry{}
 
 	// Fetch routes for each site/language combination
-	for _, site := range sites {
+	for _, siteName := range sites {
+		siteInfo := s.siteInfo(siteName)
 		for _, language := range languages {
-			query := s.getSitemapQuery(site, language)
-
-			result, err := s.graphQLClient.Request(ctx, query, nil)
-			if err != nil {
-				debug.Sitemap("error fetching sitemap for site=%s, language=%s: %v", site, language, err)
-				continue
+			// Routes missing in the language are served from its fallback languages
+			chain := s.languageFallback.Chain(siteName, language)
+			if len(chain) == 0 {
+				chain = []string{language}
 			}
 
-			entries, err := s.parseSitemapResponse(result, site, language)
-			if err != nil {
-				debug.Sitemap("error parsing sitemap for site=%s, language=%s: %v", site, language, err)
-				continue
-			}
+			seen := make(map[string]bool)
+			for _, fetchLanguage := range chain {
+				query := s.getSitemapQuery(siteName, fetchLanguage)
+
+				result, err := s.graphQLClient.Request(ctx, query, nil)
+				if err != nil {
+					debug.Sitemap("error fetching sitemap for site=%s, language=%s: %v", siteName, fetchLanguage, err)
+					continue
+				}
 
-			allEntries = append(allEntries, entries...)
+				// Fallback routes are listed under the requested language's URLs
+				entries, err := s.parseSitemapResponse(result, siteInfo, language)
+				if err != nil {
+					debug.Sitemap("error parsing sitemap for site=%s, language=%s: %v", siteName, fetchLanguage, err)
+					continue
+				}
+
+				for _, entry := range entries {
+					if seen[entry.Loc] {
+						continue
+					}
+					seen[entry.Loc] = true
+					allEntries = append(allEntries, entry)
+				}
+			}
 		}
 	}
 
@@ -72,6 +117,20 @@ func (s *sitemapXmlServiceImpl) FetchSitemap(
 	return allEntries, nil
 }
 
+// siteInfo returns the site the sitemap URLs are built for
+func (s *sitemapXmlServiceImpl) siteInfo(siteName string) models.SiteInfo {
+	siteInfo := models.SiteInfo{Name: siteName}
+	if s.sites != nil {
+		if resolved, err := s.sites.GetByName(siteName); err == nil && resolved != nil {
+			siteInfo = *resolved
+		}
+	}
+	if s.baseURL != "" {
+		siteInfo.HostName = s.baseURL
+	}
+	return siteInfo
+}
+
 // GenerateSitemapXML generates XML sitemap from entries
 func (s *sitemapXmlServiceImpl) GenerateSitemapXML(entries []models.SitemapEntry) (string, error) {
 	// Create URL set
@@ -120,7 +179,8 @@ func (s *sitemapXmlServiceImpl) getSitemapQuery(siteName, language string) strin
 // parseSitemapResponse parses the sitemap response
 func (s *sitemapXmlServiceImpl) parseSitemapResponse(
 	data map[string]any,
-	siteName, language string,
+	info models.SiteInfo,
+	language string,
 ) ([]models.SitemapEntry, error) {
 	entries := []models.SitemapEntry{}
 
@@ -151,7 +211,7 @@ func (s *sitemapXmlServiceImpl) parseSitemapResponse(
 		}
 
 		// Build full URL
-		loc := s.baseURL + path
+		loc := s.urlService.URL(info, path, language)
 
 		// Get last modified date
 		lastMod := ""
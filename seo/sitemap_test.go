@@ -0,0 +1,43 @@
+package seo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guitarrich/content-sdk-go/models"
+	"github.com/guitarrich/content-sdk-go/seo"
+	"github.com/guitarrich/content-sdk-go/site"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// routesClient answers SitemapQuery with a single route
+type routesClient struct{}
+
+func (routesClient) Request(ctx context.Context, query string, variables map[string]any) (map[string]any, error) {
+	return map[string]any{"site": map[string]any{"siteInfo": map[string]any{"routes": []any{
+		map[string]any{"path": "/about", "lastModified": "2024-02-01"},
+	}}}}, nil
+}
+
+func TestSitemap_MatchesCanonicalURLs(t *testing.T) {
+	info := models.SiteInfo{Name: "main", HostName: "www.example.com", Language: "en"}
+	sitemap := seo.NewSitemapXmlService(seo.SitemapXmlServiceConfig{
+		GraphQLClient: routesClient{},
+		Sites:         site.NewSiteResolver([]models.SiteInfo{info}, info),
+	})
+	entries, err := sitemap.FetchSitemap(context.Background(), []string{"main"}, []string{"en", "fr"})
+	require.NoError(t, err)
+
+	builder := seo.NewHeadBuilder(seo.HeadConfig{})
+	var canonicals []string
+	for _, language := range []string{"en", "fr"} {
+		page := newPage(t, "/about", `{"name": "about"}`)
+		page.Language = language
+		canonicals = append(canonicals, builder.Build(page, seo.HeadOptions{Site: &info}).Canonical)
+	}
+
+	require.Len(t, entries, 2)
+	assert.Equal(t, []string{"https://www.example.com/about", "https://www.example.com/fr/about"}, canonicals)
+	assert.Equal(t, canonicals, []string{entries[0].Loc, entries[1].Loc})
+}
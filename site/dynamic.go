@@ -0,0 +1,288 @@
+package site
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/guitarrich/content-sdk-go/debug"
+	"github.com/guitarrich/content-sdk-go/models"
+)
+
+// DynamicSiteResolverConfig contains configuration for the dynamic site resolver
+type DynamicSiteResolverConfig struct {
+	// SiteInfoService fetches the site list from Edge
+	SiteInfoService SiteInfoService
+
+	// StaticSites are statically configured sites merged on top of the Edge sites.
+	// A static site with the same name as an Edge site overrides its non-empty fields.
+	StaticSites []models.SiteInfo
+
+	// DefaultSite is the site returned when no site matches
+	DefaultSite models.SiteInfo
+
+	// RefreshInterval is how often the site list is refreshed (default: 5m)
+	RefreshInterval time.Duration
+
+	// Timeout is the timeout for a single refresh (default: 30s)
+	Timeout time.Duration
+}
+
+// DynamicSiteResolver resolves sites from a site list fetched from Edge at runtime.
+// The underlying resolver is rebuilt and swapped atomically on every refresh.
+type DynamicSiteResolver struct {
+	config      DynamicSiteResolverConfig
+	current     atomic.Pointer[dynamicSiteTable]
+	mu          sync.Mutex
+	edgeSites   []models.SiteInfo
+	lastRefresh time.Time
+	lastErr     error
+	stop        context.CancelFunc
+	done        chan struct{}
+}
+
+// dynamicSiteTable is an immutable snapshot of the merged site list
+type dynamicSiteTable struct {
+	sites    []models.SiteInfo
+	resolver *siteResolverImpl
+}
+
+// SiteTableStatus describes the current state of a dynamic site table
+type SiteTableStatus struct {
+	// Sites is the merged site list
+	Sites []models.SiteInfo `json:"sites"`
+
+	// DefaultSite is the fallback site
+	DefaultSite models.SiteInfo `json:"defaultSite"`
+
+	// LastRefresh is the time of the last successful refresh
+	LastRefresh time.Time `json:"lastRefresh,omitempty"`
+
+	// LastError is the error from the last failed refresh, if any
+	LastError string `json:"lastError,omitempty"`
+}
+
+// NewDynamicSiteResolver creates a new dynamic site resolver.
+// Until the first refresh, only the static sites are resolvable.
+func NewDynamicSiteResolver(config DynamicSiteResolverConfig) *DynamicSiteResolver {
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = 5 * time.Minute
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+
+	r := &DynamicSiteResolver{
+		config: config,
+	}
+	r.rebuild()
+
+	return r
+}
+
+// GetByHost resolves a site by hostname
+func (r *DynamicSiteResolver) GetByHost(hostname string) (*models.SiteInfo, error) {
+	return r.current.Load().resolver.GetByHost(hostname)
+}
+
+// GetByHostAndPath resolves a site by hostname and path
+func (r *DynamicSiteResolver) GetByHostAndPath(hostname, path string) (*models.SiteInfo, string, error) {
+	return r.current.Load().resolver.GetByHostAndPath(hostname, path)
+}
+
+// MatchHostAndPath returns the most specific site for a hostname and path, or nil
+func (r *DynamicSiteResolver) MatchHostAndPath(hostname, path string) (*models.SiteInfo, string) {
+	return r.current.Load().resolver.MatchHostAndPath(hostname, path)
+}
+
+// GetByName resolves a site by name
+func (r *DynamicSiteResolver) GetByName(name string) (*models.SiteInfo, error) {
+	return r.current.Load().resolver.GetByName(name)
+}
+
+// Sites returns the current merged site list
+func (r *DynamicSiteResolver) Sites() []models.SiteInfo {
+	sites := r.current.Load().sites
+	result := make([]models.SiteInfo, len(sites))
+	copy(result, sites)
+	return result
+}
+
+// Status returns the current site table and refresh state
+func (r *DynamicSiteResolver) Status() SiteTableStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	status := SiteTableStatus{
+		Sites:       r.Sites(),
+		DefaultSite: r.config.DefaultSite,
+		LastRefresh: r.lastRefresh,
+	}
+	if r.lastErr != nil {
+		status.LastError = r.lastErr.Error()
+	}
+	return status
+}
+
+// Refresh fetches the site list from Edge and swaps in the rebuilt resolver.
+// On failure the previous site table stays active.
+func (r *DynamicSiteResolver) Refresh(ctx context.Context) error {
+	if r.config.SiteInfoService == nil {
+		return fmt.Errorf("site info service is not configured")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.config.Timeout)
+	defer cancel()
+
+	sites, err := r.config.SiteInfoService.FetchSites(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err == nil && len(sites) == 0 && len(r.edgeSites) > 0 {
+		err = fmt.Errorf("no sites returned from Edge, keeping previous site table")
+	}
+	if err != nil {
+		r.lastErr = err
+		debug.Multisite("failed to refresh sites from Edge: %v", err)
+		return fmt.Errorf("failed to refresh sites: %w", err)
+	}
+
+	r.edgeSites = sites
+	r.lastRefresh = time.Now()
+	r.lastErr = nil
+	r.rebuildLocked()
+
+	debug.Multisite("site table refreshed with %d Edge sites", len(sites))
+	return nil
+}
+
+// SetOverrides replaces the static sites and default site and rebuilds the resolver
+func (r *DynamicSiteResolver) SetOverrides(sites []models.SiteInfo, defaultSite models.SiteInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.config.StaticSites = sites
+	r.config.DefaultSite = defaultSite
+	r.rebuildLocked()
+}
+
+// Start performs the initial refresh and then refreshes on RefreshInterval
+// until ctx is cancelled or Stop is called. The initial refresh error is returned,
+// but refreshing continues in the background regardless.
+func (r *DynamicSiteResolver) Start(ctx context.Context) error {
+	err := r.Refresh(ctx)
+
+	ctx, cancel := context.WithCancel(ctx)
+	r.mu.Lock()
+	if r.stop != nil {
+		r.mu.Unlock()
+		cancel()
+		return err
+	}
+	r.stop = cancel
+	r.done = make(chan struct{})
+	done := r.done
+	r.mu.Unlock()
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(r.config.RefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = r.Refresh(ctx)
+			}
+		}
+	}()
+
+	return err
+}
+
+// Stop stops the background refresh and waits for it to exit
+func (r *DynamicSiteResolver) Stop() {
+	r.mu.Lock()
+	stop, done := r.stop, r.done
+	r.stop, r.done = nil, nil
+	r.mu.Unlock()
+
+	if stop != nil {
+		stop()
+		<-done
+	}
+}
+
+// rebuild rebuilds the resolver from the current Edge and static sites
+func (r *DynamicSiteResolver) rebuild() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rebuildLocked()
+}
+
+// rebuildLocked rebuilds the resolver; r.mu must be held
+func (r *DynamicSiteResolver) rebuildLocked() {
+	sites := MergeSites(r.edgeSites, r.config.StaticSites)
+	r.current.Store(&dynamicSiteTable{
+		sites:    sites,
+		resolver: NewSiteResolver(sites, r.config.DefaultSite).(*siteResolverImpl),
+	})
+}
+
+// MergeSites merges override sites on top of base sites.
+// Sites are matched by name (case-insensitive); non-empty override fields win.
+// Override sites without a matching base site are appended.
+func MergeSites(base []models.SiteInfo, overrides []models.SiteInfo) []models.SiteInfo {
+	merged := make([]models.SiteInfo, 0, len(base)+len(overrides))
+	index := make(map[string]int, len(base))
+
+	for _, site := range base {
+		key := strings.ToLower(site.Name)
+		if i, exists := index[key]; exists {
+			merged[i] = site
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, site)
+	}
+
+	for _, override := range overrides {
+		key := strings.ToLower(override.Name)
+		i, exists := index[key]
+		if !exists {
+			index[key] = len(merged)
+			merged = append(merged, override)
+			continue
+		}
+
+		site := merged[i]
+		if override.HostName != "" {
+			site.HostName = override.HostName
+		}
+		if override.VirtualFolder != "" {
+			site.VirtualFolder = override.VirtualFolder
+		}
+		if override.Language != "" {
+			site.Language = override.Language
+		}
+		if len(override.Languages) > 0 {
+			site.Languages = override.Languages
+		}
+		if override.RootPath != "" {
+			site.RootPath = override.RootPath
+		}
+		if override.Database != "" {
+			site.Database = override.Database
+		}
+		merged[i] = site
+	}
+
+	return merged
+}
@@ -0,0 +1,126 @@
+package site
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/guitarrich/content-sdk-go/models"
+)
+
+// mockSiteInfoService returns a fixed site list
+type mockSiteInfoService struct {
+	sites []models.SiteInfo
+	err   error
+}
+
+func (m *mockSiteInfoService) FetchSiteInfo(ctx context.Context, siteName string) (*models.SiteInfo, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockSiteInfoService) FetchSites(ctx context.Context) ([]models.SiteInfo, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.sites, nil
+}
+
+func TestDynamicSiteResolver_Refresh(t *testing.T) {
+	service := &mockSiteInfoService{
+		sites: []models.SiteInfo{
+			{Name: "brand-a", HostName: "a.example.com", Language: "en"},
+			{Name: "brand-b", HostName: "b.example.com", Language: "fr"},
+		},
+	}
+
+	resolver := NewDynamicSiteResolver(DynamicSiteResolverConfig{
+		SiteInfoService: service,
+		DefaultSite:     models.SiteInfo{Name: "default"},
+	})
+
+	// Before the first refresh, unknown hosts resolve to the default site
+	siteInfo, _ := resolver.GetByHost("b.example.com")
+	if siteInfo.Name != "default" {
+		t.Errorf("expected 'default' before refresh, got '%s'", siteInfo.Name)
+	}
+
+	if err := resolver.Refresh(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	siteInfo, _ = resolver.GetByHost("b.example.com")
+	if siteInfo.Name != "brand-b" {
+		t.Errorf("expected 'brand-b' after refresh, got '%s'", siteInfo.Name)
+	}
+
+	if len(resolver.Sites()) != 2 {
+		t.Errorf("expected 2 sites, got %d", len(resolver.Sites()))
+	}
+}
+
+func TestDynamicSiteResolver_StaticOverrides(t *testing.T) {
+	service := &mockSiteInfoService{
+		sites: []models.SiteInfo{
+			{Name: "brand-a", HostName: "a.example.com", Language: "en"},
+		},
+	}
+
+	resolver := NewDynamicSiteResolver(DynamicSiteResolverConfig{
+		SiteInfoService: service,
+		StaticSites: []models.SiteInfo{
+			{Name: "Brand-A", HostName: "www.brand-a.com"},
+			{Name: "local", HostName: "localhost"},
+		},
+	})
+
+	if err := resolver.Refresh(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	siteInfo, _ := resolver.GetByName("brand-a")
+	if siteInfo.HostName != "www.brand-a.com" {
+		t.Errorf("expected overridden hostname, got '%s'", siteInfo.HostName)
+	}
+	if siteInfo.Language != "en" {
+		t.Errorf("expected language from Edge to be kept, got '%s'", siteInfo.Language)
+	}
+
+	if _, err := resolver.GetByName("local"); err != nil {
+		t.Errorf("expected static-only site to be resolvable: %v", err)
+	}
+}
+
+func TestDynamicSiteResolver_RefreshErrorKeepsTable(t *testing.T) {
+	service := &mockSiteInfoService{
+		sites: []models.SiteInfo{{Name: "brand-a", HostName: "a.example.com"}},
+	}
+
+	resolver := NewDynamicSiteResolver(DynamicSiteResolverConfig{SiteInfoService: service})
+	if err := resolver.Refresh(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	service.err = errors.New("edge unavailable")
+	if err := resolver.Refresh(context.Background()); err == nil {
+		t.Error("expected refresh error")
+	}
+
+	if _, err := resolver.GetByName("brand-a"); err != nil {
+		t.Errorf("expected previous site table to stay active: %v", err)
+	}
+
+	status := resolver.Status()
+	if status.LastError == "" {
+		t.Error("expected last error in status")
+	}
+
+	// An empty response must not wipe a populated table
+	service.err = nil
+	service.sites = nil
+	if err := resolver.Refresh(context.Background()); err == nil {
+		t.Error("expected error for empty site list")
+	}
+	if len(resolver.Sites()) != 1 {
+		t.Errorf("expected 1 site to be kept, got %d", len(resolver.Sites()))
+	}
+}
@@ -2,6 +2,7 @@ package site
 
 import (
 	"fmt"
+	"net"
 	"strings"
 
 	"github.com/guitarrich/content-sdk-go/models"
@@ -13,47 +14,110 @@ type SiteResolver interface {
 	GetByName(name string) (*models.SiteInfo, error)
 }
 
+// PathSiteResolver resolves sites by hostname and request path.
+// It supports sites mounted under a path prefix (virtual folder).
+type PathSiteResolver interface {
+	// GetByHostAndPath returns the best matching site and the path with the
+	// site's path prefix removed
+	GetByHostAndPath(hostname, path string) (*models.SiteInfo, string, error)
+}
+
+// HostMatcher resolves sites by hostname and path without falling back to
+// the default site, so callers can tell a match from the fallback
+type HostMatcher interface {
+	// MatchHostAndPath returns the best matching site and the path with the
+	// site's path prefix removed, or nil when no site matches
+	MatchHostAndPath(hostname, path string) (*models.SiteInfo, string)
+}
+
 // siteResolverImpl is the default implementation
 type siteResolverImpl struct {
 	sites       []models.SiteInfo
 	defaultSite models.SiteInfo
+	patterns    [][]hostPattern
+}
+
+// hostPattern is a single parsed entry of a site's host list
+type hostPattern struct {
+	// host is the lowercase hostname, "*.example.com" or "*"
+	host string
+
+	// port is the port to match, empty to match any port
+	port string
+
+	// prefix is the path prefix the site is mounted under, empty for the root
+	prefix string
 }
 
+// Specificity ranks for host matches
+const (
+	hostRankAny = iota + 1
+	hostRankWildcard
+	hostRankExact
+)
+
 // NewSiteResolver creates a new site resolver
 func NewSiteResolver(sites []models.SiteInfo, defaultSite models.SiteInfo) SiteResolver {
+	patterns := make([][]hostPattern, len(sites))
+	for i, site := range sites {
+		patterns[i] = parseHostPatterns(site)
+	}
+
 	return &siteResolverImpl{
 		sites:       sites,
 		defaultSite: defaultSite,
+		patterns:    patterns,
 	}
 }
 
-// GetByHost resolves a site by hostname
+// GetByHost resolves a site by hostname.
+// Only sites mounted at the root path are considered.
 func (r *siteResolverImpl) GetByHost(hostname string) (*models.SiteInfo, error) {
-	// Normalize hostname (remove port if present)
-	host := hostname
-	if idx := strings.Index(hostname, ":"); idx > 0 {
-		host = hostname[:idx]
+	site, _, err := r.GetByHostAndPath(hostname, "")
+	return site, err
+}
+
+// GetByHostAndPath resolves a site by hostname and path.
+// When several sites match, the most specific wins: a longer path prefix first,
+// then an exact host over a wildcard (longer wildcard suffixes first) over "*",
+// then an explicit port over any port. Ties go to the first configured site.
+func (r *siteResolverImpl) GetByHostAndPath(hostname, path string) (*models.SiteInfo, string, error) {
+	site, sitePath := r.MatchHostAndPath(hostname, path)
+	if site == nil {
+		// Return default site if no match found
+		return &r.defaultSite, path, nil
 	}
+	return site, sitePath, nil
+}
 
-	// Convert to lowercase for case-insensitive matching
-	host = strings.ToLower(host)
+// MatchHostAndPath returns the most specific site for a hostname and path, or nil
+func (r *siteResolverImpl) MatchHostAndPath(hostname, path string) (*models.SiteInfo, string) {
+	host, port := splitHostPort(hostname)
 
-	// Try exact match first
-	for _, site := range r.sites {
-		if strings.ToLower(site.HostName) == host {
-			return &site, nil
+	best := -1
+	var bestScore matchScore
+	var bestPrefix string
+
+	for i, patterns := range r.patterns {
+		for _, pattern := range patterns {
+			score, ok := pattern.match(host, port, path)
+			if !ok {
+				continue
+			}
+			if best == -1 || score.greaterThan(bestScore) {
+				best = i
+				bestScore = score
+				bestPrefix = pattern.prefix
+			}
 		}
 	}
 
-	// Try wildcard matching (e.g., *.example.com)
-	for _, site := range r.sites {
-		if matchesWildcard(site.HostName, host) {
-			return &site, nil
-		}
+	if best == -1 {
+		return nil, path
 	}
 
-	// Return default site if no match found
-	return &r.defaultSite, nil
+	site := r.sites[best]
+	return &site, stripPathPrefix(path, bestPrefix)
 }
 
 // GetByName resolves a site by name
@@ -75,6 +139,165 @@ func (r *siteResolverImpl) GetByName(name string) (*models.SiteInfo, error) {
 	return nil, fmt.Errorf("site not found: %s", name)
 }
 
+// matchScore orders matches by specificity
+type matchScore struct {
+	prefixLen   int
+	hostRank    int
+	wildcardLen int
+	hasPort     bool
+}
+
+// greaterThan reports whether s is more specific than other
+func (s matchScore) greaterThan(other matchScore) bool {
+	if s.prefixLen != other.prefixLen {
+		return s.prefixLen > other.prefixLen
+	}
+	if s.hostRank != other.hostRank {
+		return s.hostRank > other.hostRank
+	}
+	if s.wildcardLen != other.wildcardLen {
+		return s.wildcardLen > other.wildcardLen
+	}
+	return s.hasPort && !other.hasPort
+}
+
+// match checks the pattern against a request host, port and path
+func (p hostPattern) match(host, port, path string) (matchScore, bool) {
+	score := matchScore{}
+
+	switch {
+	case p.host == "*":
+		score.hostRank = hostRankAny
+	case strings.HasPrefix(p.host, "*."):
+		if !matchesWildcard(p.host, host) {
+			return score, false
+		}
+		score.hostRank = hostRankWildcard
+		score.wildcardLen = len(p.host)
+	default:
+		if p.host != host {
+			return score, false
+		}
+		score.hostRank = hostRankExact
+	}
+
+	if p.port != "" {
+		if p.port != port {
+			return score, false
+		}
+		score.hasPort = true
+	}
+
+	if p.prefix != "" {
+		if !hasPathPrefix(path, p.prefix) {
+			return score, false
+		}
+		score.prefixLen = len(p.prefix)
+	}
+
+	return score, true
+}
+
+// parseHostPatterns parses a site's pipe-separated host list.
+// Entries may include a port and a path prefix, e.g. "example.com:8080/brand-a".
+// Entries without a path use the site's virtual folder as prefix.
+func parseHostPatterns(site models.SiteInfo) []hostPattern {
+	var patterns []hostPattern
+
+	for entry := range strings.SplitSeq(site.HostName, "|") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+
+		// Strip scheme if present
+		if _, after, ok := strings.Cut(entry, "://"); ok {
+			entry = after
+		}
+
+		prefix := normalizePathPrefix(site.VirtualFolder)
+		if idx := strings.Index(entry, "/"); idx >= 0 {
+			prefix = normalizePathPrefix(entry[idx:])
+			entry = entry[:idx]
+		}
+
+		host, port := splitHostPort(entry)
+		patterns = append(patterns, hostPattern{
+			host:   host,
+			port:   port,
+			prefix: prefix,
+		})
+	}
+
+	return patterns
+}
+
+// PathPrefixes returns the path prefixes a site is mounted under
+func PathPrefixes(site models.SiteInfo) []string {
+	var prefixes []string
+	for _, pattern := range parseHostPatterns(site) {
+		if pattern.prefix != "" {
+			prefixes = append(prefixes, pattern.prefix)
+		}
+	}
+	if len(prefixes) == 0 {
+		if prefix := normalizePathPrefix(site.VirtualFolder); prefix != "" {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
+
+// StripSitePrefix removes the longest of the site's path prefixes from path.
+// It returns the stripped path and the prefix that was removed.
+func StripSitePrefix(site models.SiteInfo, path string) (string, string) {
+	matched := ""
+	for _, prefix := range PathPrefixes(site) {
+		if hasPathPrefix(path, prefix) && len(prefix) > len(matched) {
+			matched = prefix
+		}
+	}
+	return stripPathPrefix(path, matched), matched
+}
+
+// normalizePathPrefix normalizes a path prefix to "/segment" form, "" for the root
+func normalizePathPrefix(prefix string) string {
+	prefix = strings.Trim(strings.TrimSpace(prefix), "/")
+	if prefix == "" {
+		return ""
+	}
+	return "/" + strings.ToLower(prefix)
+}
+
+// hasPathPrefix checks whether path starts with prefix on a segment boundary
+func hasPathPrefix(path, prefix string) bool {
+	if len(path) < len(prefix) || !strings.EqualFold(path[:len(prefix)], prefix) {
+		return false
+	}
+	return len(path) == len(prefix) || path[len(prefix)] == '/'
+}
+
+// stripPathPrefix removes prefix from path, keeping a leading slash
+func stripPathPrefix(path, prefix string) string {
+	if prefix == "" || !hasPathPrefix(path, prefix) {
+		return path
+	}
+	stripped := path[len(prefix):]
+	if stripped == "" {
+		return "/"
+	}
+	return stripped
+}
+
+// splitHostPort splits a host into lowercase hostname and port (empty if absent)
+func splitHostPort(hostname string) (string, string) {
+	hostname = strings.ToLower(strings.TrimSpace(hostname))
+	if host, port, err := net.SplitHostPort(hostname); err == nil {
+		return strings.Trim(host, "[]"), port
+	}
+	return strings.Trim(hostname, "[]"), ""
+}
+
 // matchesWildcard checks if a hostname matches a wildcard pattern
 // Supports patterns like *.example.com
 func matchesWildcard(pattern, hostname string) bool {
@@ -0,0 +1,85 @@
+package site
+
+import (
+	"testing"
+
+	"github.com/guitarrich/content-sdk-go/models"
+)
+
+func TestSiteResolver_GetByHostAndPath(t *testing.T) {
+	resolver := NewSiteResolver([]models.SiteInfo{
+		{Name: "main", HostName: "example.com|www.example.com"},
+		{Name: "brand-a", HostName: "example.com/brand-a"},
+		{Name: "brand-b", HostName: "example.com", VirtualFolder: "/brand-b"},
+		{Name: "wildcard", HostName: "*.example.com"},
+		{Name: "deep-wildcard", HostName: "*.shop.example.com"},
+		{Name: "preview", HostName: "example.com:8080"},
+		{Name: "catchall", HostName: "*"},
+	}, models.SiteInfo{Name: "default"}).(PathSiteResolver)
+
+	tests := []struct {
+		name         string
+		host         string
+		path         string
+		expectedSite string
+		expectedPath string
+	}{
+		{"exact host", "example.com", "/about", "main", "/about"},
+		{"pipe-separated host", "www.example.com", "/about", "main", "/about"},
+		{"host is case-insensitive", "WWW.Example.com", "/", "main", "/"},
+		{"path prefix in host name", "example.com", "/brand-a/products", "brand-a", "/products"},
+		{"path prefix root", "example.com", "/brand-a", "brand-a", "/"},
+		{"path prefix is case-insensitive", "example.com", "/Brand-A/x", "brand-a", "/x"},
+		{"virtual folder", "example.com", "/brand-b/about", "brand-b", "/about"},
+		{"prefix must match a whole segment", "example.com", "/brand-abc", "main", "/brand-abc"},
+		{"wildcard", "blog.example.com", "/", "wildcard", "/"},
+		{"longer wildcard wins", "uk.shop.example.com", "/", "deep-wildcard", "/"},
+		{"explicit port wins", "example.com:8080", "/", "preview", "/"},
+		{"other port falls back to portless", "example.com:9090", "/", "main", "/"},
+		{"any host", "other.org", "/", "catchall", "/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			site, path, err := resolver.GetByHostAndPath(tt.host, tt.path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if site.Name != tt.expectedSite {
+				t.Errorf("expected site '%s', got '%s'", tt.expectedSite, site.Name)
+			}
+			if path != tt.expectedPath {
+				t.Errorf("expected path '%s', got '%s'", tt.expectedPath, path)
+			}
+		})
+	}
+}
+
+func TestSiteResolver_GetByHost_Default(t *testing.T) {
+	resolver := NewSiteResolver([]models.SiteInfo{
+		{Name: "brand-a", HostName: "example.com/brand-a"},
+	}, models.SiteInfo{Name: "default"})
+
+	// Sites mounted under a prefix do not match host-only lookups
+	site, err := resolver.GetByHost("example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if site.Name != "default" {
+		t.Errorf("expected 'default', got '%s'", site.Name)
+	}
+}
+
+func TestStripSitePrefix(t *testing.T) {
+	siteInfo := models.SiteInfo{Name: "brand-a", HostName: "example.com/brand-a|brand-a.com"}
+
+	path, prefix := StripSitePrefix(siteInfo, "/brand-a/about")
+	if path != "/about" || prefix != "/brand-a" {
+		t.Errorf("expected '/about' and '/brand-a', got '%s' and '%s'", path, prefix)
+	}
+
+	path, prefix = StripSitePrefix(siteInfo, "/about")
+	if path != "/about" || prefix != "" {
+		t.Errorf("expected unchanged path, got '%s' and '%s'", path, prefix)
+	}
+}
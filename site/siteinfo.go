@@ -156,10 +156,22 @@ func (s *siteInfoServiceImpl) mapToSiteInfo(data map[string]any) *models.SiteInf
 		siteInfo.HostName = hostName
 	}
 
+	if virtualFolder, ok := data["virtualFolder"].(string); ok {
+		siteInfo.VirtualFolder = virtualFolder
+	}
+
 	if language, ok := data["language"].(string); ok {
 		siteInfo.Language = language
 	}
 
+	if languages, ok := data["languages"].([]any); ok {
+		for _, language := range languages {
+			if languageStr, ok := language.(string); ok && languageStr != "" {
+				siteInfo.Languages = append(siteInfo.Languages, languageStr)
+			}
+		}
+	}
+
 	if rootPath, ok := data["rootPath"].(string); ok {
 		siteInfo.RootPath = rootPath
 	}
@@ -0,0 +1,285 @@
+package site
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/guitarrich/content-sdk-go/debug"
+	"github.com/guitarrich/content-sdk-go/graphql"
+	"github.com/guitarrich/content-sdk-go/models"
+)
+
+// LocalePrefix is the strategy for prefixing URLs with the language
+type LocalePrefix int
+
+const (
+	// LocalePrefixExceptDefault prefixes all languages except the site's default language (/about, /fr/about)
+	LocalePrefixExceptDefault LocalePrefix = iota
+
+	// LocalePrefixAlways prefixes all languages (/en/about, /fr/about)
+	LocalePrefixAlways
+
+	// LocalePrefixNever never prefixes the language, e.g. for sites with one language per host
+	LocalePrefixNever
+)
+
+// XDefault is the hreflang value of the language-neutral alternate
+const XDefault = "x-default"
+
+// AlternateURL is a language version of a page
+type AlternateURL struct {
+	// Language is the hreflang value, e.g. "fr-CA" or "x-default"
+	Language string
+
+	// URL is the public URL of the version
+	URL string
+}
+
+// URLService builds public URLs of routes
+type URLService interface {
+	// Path returns the site-relative route path with the site path prefix and
+	// locale prefix (e.g. "/brand-a/fr/about"). An empty language adds no locale prefix.
+	Path(site models.SiteInfo, routePath, language string) string
+
+	// URL returns the absolute URL of a route on the site's first host name.
+	// Sites without a concrete host name (e.g. "*") get the path.
+	URL(site models.SiteInfo, routePath, language string) string
+
+	// Alternates fetches the languages an item has versions in and returns the
+	// URLs of the route in each of them, for hreflang links. The item is queried
+	// in language, the page's own language (default: the site's language).
+	Alternates(ctx context.Context, site models.SiteInfo, itemID, routePath, language string) ([]AlternateURL, error)
+}
+
+// URLServiceConfig contains configuration for the URL service
+type URLServiceConfig struct {
+	// GraphQLClient fetches an item's language versions for Alternates (optional)
+	GraphQLClient graphql.Client
+
+	// LocalePrefix is the locale prefix strategy (default: LocalePrefixExceptDefault)
+	LocalePrefix LocalePrefix
+
+	// Scheme is used for host names without scheme (default: https)
+	Scheme string
+
+	// XDefault adds an x-default alternate pointing to the site's default language
+	XDefault bool
+}
+
+// urlServiceImpl is the default implementation
+type urlServiceImpl struct {
+	config URLServiceConfig
+}
+
+// NewURLService creates a new URL service
+func NewURLService(config URLServiceConfig) URLService {
+	if config.Scheme == "" {
+		config.Scheme = "https"
+	}
+
+	return &urlServiceImpl{config: config}
+}
+
+// Path returns the route path with the site path prefix and locale prefix
+func (s *urlServiceImpl) Path(site models.SiteInfo, routePath, language string) string {
+	_, sitePrefix := siteOrigin(site, s.config.Scheme)
+	path := "/" + strings.Trim(routePath, "/")
+
+	if locale := s.localePrefix(site, language); locale != "" {
+		path = joinPath("/"+locale, path)
+	}
+	return joinPath(sitePrefix, path)
+}
+
+// URL returns the absolute URL of a route
+func (s *urlServiceImpl) URL(site models.SiteInfo, routePath, language string) string {
+	origin, _ := siteOrigin(site, s.config.Scheme)
+	return origin + s.Path(site, routePath, language)
+}
+
+// Alternates returns the URLs of the item's language versions
+func (s *urlServiceImpl) Alternates(ctx context.Context, site models.SiteInfo, itemID, routePath, language string) ([]AlternateURL, error) {
+	if s.config.GraphQLClient == nil || itemID == "" {
+		return nil, nil
+	}
+	debug.Multisite("fetching language versions of %s for site %s", itemID, site.Name)
+
+	if language == "" {
+		language = site.Language
+	}
+	if language == "" {
+		language = "en"
+	}
+	result, err := s.config.GraphQLClient.Request(ctx, itemLanguagesQuery, map[string]any{
+		"path":     itemID,
+		"language": language,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch item languages: %w", err)
+	}
+
+	languages := parseItemLanguagesResponse(result)
+	if len(site.Languages) > 0 {
+		languages = supportedLanguages(languages, site.Languages)
+	}
+	sort.Strings(languages)
+
+	alternates := make([]AlternateURL, 0, len(languages)+1)
+	hasDefault := false
+	for _, language := range languages {
+		alternates = append(alternates, AlternateURL{Language: language, URL: s.URL(site, routePath, language)})
+		hasDefault = hasDefault || strings.EqualFold(language, site.Language)
+	}
+	if s.config.XDefault && hasDefault {
+		alternates = append(alternates, AlternateURL{Language: XDefault, URL: s.URL(site, routePath, site.Language)})
+	}
+	return alternates, nil
+}
+
+// localePrefix returns the locale path segment of a language, or "".
+// Sites without a language have "en" as their default language.
+func (s *urlServiceImpl) localePrefix(site models.SiteInfo, language string) string {
+	defaultLanguage := site.Language
+	if defaultLanguage == "" {
+		defaultLanguage = "en"
+	}
+
+	switch {
+	case language == "" || s.config.LocalePrefix == LocalePrefixNever:
+		return ""
+	case s.config.LocalePrefix == LocalePrefixExceptDefault && strings.EqualFold(language, defaultLanguage):
+		return ""
+	default:
+		return language
+	}
+}
+
+// itemLanguagesQuery is the GraphQL query for an item's language versions
+const itemLanguagesQuery = `
+	query ItemLanguagesQuery($path: String!, $language: String!) {
+		item(path: $path, language: $language) {
+			languages {
+				language {
+					name
+				}
+			}
+		}
+	}
+`
+
+// parseItemLanguagesResponse returns the language names of an item's versions
+func parseItemLanguagesResponse(data map[string]any) []string {
+	item, ok := data["item"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	versions, ok := item["languages"].([]any)
+	if !ok {
+		return nil
+	}
+
+	var languages []string
+	for _, version := range versions {
+		versionMap, ok := version.(map[string]any)
+		if !ok {
+			continue
+		}
+		language, ok := versionMap["language"].(map[string]any)
+		if !ok {
+			continue
+		}
+		if name, ok := language["name"].(string); ok && name != "" {
+			languages = append(languages, name)
+		}
+	}
+	return languages
+}
+
+// supportedLanguages returns the languages that are in supported (case-insensitive)
+func supportedLanguages(languages, supported []string) []string {
+	var result []string
+	for _, language := range languages {
+		for _, s := range supported {
+			if strings.EqualFold(language, s) {
+				result = append(result, language)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// siteOrigin returns the origin ("https://example.com") of a site's first
+// concrete host name and the path prefix the site is mounted under. Host
+// entries may include a scheme, a port and a path prefix; entries without a
+// path use the site's virtual folder.
+func siteOrigin(site models.SiteInfo, scheme string) (string, string) {
+	virtualFolder := strings.Trim(strings.TrimSpace(site.VirtualFolder), "/")
+	firstPrefix := ""
+
+	for i, entry := range strings.Split(site.HostName, "|") {
+		entry = strings.TrimSpace(entry)
+		entryScheme := scheme
+		if before, after, ok := strings.Cut(entry, "://"); ok {
+			entryScheme, entry = before, after
+		}
+
+		host, prefix := entry, virtualFolder
+		if idx := strings.Index(entry, "/"); idx >= 0 {
+			host, prefix = entry[:idx], strings.Trim(entry[idx:], "/")
+		}
+		if i == 0 {
+			firstPrefix = prefix
+		}
+		if host == "" || strings.Contains(host, "*") {
+			continue
+		}
+
+		return entryScheme + "://" + strings.ToLower(host), normalizedPrefix(prefix)
+	}
+	return "", normalizedPrefix(firstPrefix)
+}
+
+// normalizedPrefix returns a path prefix in "/segment" form, "" for the root
+func normalizedPrefix(prefix string) string {
+	if prefix == "" {
+		return ""
+	}
+	return "/" + prefix
+}
+
+// joinPath prepends prefix to a rooted path, keeping the root as the prefix itself
+func joinPath(prefix, path string) string {
+	if prefix == "" {
+		return path
+	}
+	if path == "/" {
+		return prefix
+	}
+	return prefix + path
+}
+
+type urlContextKey struct{}
+
+type urlContextValue struct {
+	service  URLService
+	site     models.SiteInfo
+	language string
+}
+
+// NewURLContext returns a context carrying a URL service with the current site and language.
+// Internal General Link fields rendered with this context get the site path prefix and locale prefix.
+func NewURLContext(ctx context.Context, service URLService, site models.SiteInfo, language string) context.Context {
+	return context.WithValue(ctx, urlContextKey{}, urlContextValue{service: service, site: site, language: language})
+}
+
+// URLServiceFromContext returns the URL service, site and language stored by NewURLContext
+func URLServiceFromContext(ctx context.Context) (URLService, models.SiteInfo, string, bool) {
+	value, ok := ctx.Value(urlContextKey{}).(urlContextValue)
+	if !ok || value.service == nil {
+		return nil, models.SiteInfo{}, "", false
+	}
+	return value.service, value.site, value.language, true
+}
@@ -0,0 +1,121 @@
+package site
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/guitarrich/content-sdk-go/models"
+)
+
+// itemLanguagesClient answers ItemLanguagesQuery with fixed language versions
+type itemLanguagesClient struct {
+	languages []string
+	query     string
+	variables map[string]any
+}
+
+func (c *itemLanguagesClient) Request(ctx context.Context, query string, variables map[string]any) (map[string]any, error) {
+	c.query, c.variables = query, variables
+	versions := make([]any, len(c.languages))
+	for i, language := range c.languages {
+		versions[i] = map[string]any{"language": map[string]any{"name": language}}
+	}
+	return map[string]any{"item": map[string]any{"languages": versions}}, nil
+}
+
+func TestURLService_URL(t *testing.T) {
+	main := models.SiteInfo{Name: "main", HostName: "www.example.com|example.com", Language: "en"}
+	brand := models.SiteInfo{Name: "brand", HostName: "*.brand.com|http://brand.com:8080", VirtualFolder: "/brand-a/", Language: "de"}
+	mounted := models.SiteInfo{Name: "emea", HostName: "example.com/emea", VirtualFolder: "/ignored", Language: "en"}
+	anyHost := models.SiteInfo{Name: "any", HostName: "*", VirtualFolder: "/any", Language: "en"}
+
+	tests := []struct {
+		name     string
+		strategy LocalePrefix
+		site     models.SiteInfo
+		path     string
+		language string
+		expected string
+	}{
+		{"default language", LocalePrefixExceptDefault, main, "/about", "en", "https://www.example.com/about"},
+		{"other language", LocalePrefixExceptDefault, main, "/about/", "fr-CA", "https://www.example.com/fr-CA/about"},
+		{"home in other language", LocalePrefixExceptDefault, main, "/", "fr", "https://www.example.com/fr"},
+		{"always", LocalePrefixAlways, main, "/about", "en", "https://www.example.com/en/about"},
+		{"never", LocalePrefixNever, main, "about", "fr", "https://www.example.com/about"},
+		{"no language", LocalePrefixAlways, main, "/about", "", "https://www.example.com/about"},
+		{"virtual folder and scheme", LocalePrefixExceptDefault, brand, "/", "en", "http://brand.com:8080/brand-a/en"},
+		{"host path prefix", LocalePrefixExceptDefault, mounted, "/contact", "en", "https://example.com/emea/contact"},
+		{"wildcard host", LocalePrefixExceptDefault, anyHost, "/contact", "fr", "/any/fr/contact"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewURLService(URLServiceConfig{LocalePrefix: tt.strategy})
+			if url := service.URL(tt.site, tt.path, tt.language); url != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, url)
+			}
+		})
+	}
+}
+
+func TestURLService_Path(t *testing.T) {
+	service := NewURLService(URLServiceConfig{})
+	site := models.SiteInfo{Name: "brand", HostName: "example.com", VirtualFolder: "/brand-a", Language: "en"}
+
+	if path := service.Path(site, "/", "en"); path != "/brand-a" {
+		t.Errorf("expected /brand-a, got %s", path)
+	}
+	if path := service.Path(site, "/news/launch", "fr"); path != "/brand-a/fr/news/launch" {
+		t.Errorf("expected /brand-a/fr/news/launch, got %s", path)
+	}
+}
+
+func TestURLService_Alternates(t *testing.T) {
+	client := &itemLanguagesClient{languages: []string{"fr", "en", "ja-JP"}}
+	service := NewURLService(URLServiceConfig{GraphQLClient: client, XDefault: true})
+	site := models.SiteInfo{Name: "main", HostName: "example.com", Language: "en", Languages: []string{"en", "fr"}}
+
+	alternates, err := service.Alternates(context.Background(), site, "{A1B2}", "/about", "fr")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(client.query, `item(path: $path, language: $language)`) {
+		t.Errorf("unexpected query: %s", client.query)
+	}
+	// The item is queried in the page's language
+	if client.variables["path"] != "{A1B2}" || client.variables["language"] != "fr" {
+		t.Errorf("unexpected variables: %v", client.variables)
+	}
+
+	expected := []AlternateURL{
+		{Language: "en", URL: "https://example.com/about"},
+		{Language: "fr", URL: "https://example.com/fr/about"},
+		{Language: XDefault, URL: "https://example.com/about"},
+	}
+	if len(alternates) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, alternates)
+	}
+	for i := range expected {
+		if alternates[i] != expected[i] {
+			t.Errorf("alternate %d: expected %v, got %v", i, expected[i], alternates[i])
+		}
+	}
+
+	// Without a language the site's language is used
+	if _, err := service.Alternates(context.Background(), site, "{A1B2}", "/about", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.variables["language"] != "en" {
+		t.Errorf("expected the site language, got %v", client.variables["language"])
+	}
+}
+
+func TestURLService_AlternatesWithoutClient(t *testing.T) {
+	service := NewURLService(URLServiceConfig{})
+
+	alternates, err := service.Alternates(context.Background(), models.SiteInfo{Name: "main"}, "{A1B2}", "/about", "en")
+	if err != nil || alternates != nil {
+		t.Errorf("expected no alternates, got %v, %v", alternates, err)
+	}
+}
@@ -0,0 +1,290 @@
+package sitecoretest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/guitarrich/content-sdk-go/models"
+)
+
+// Fixture is a recorded GraphQL response.
+//
+// A request matches a fixture when the operation names are equal and every
+// fixture argument equals the request argument of the same name. Arguments
+// are the request variables plus the string arguments written inline in the
+// query, such as site and language. Leaving an argument out of a fixture
+// matches any value; when several fixtures match, the one with the most
+// arguments wins.
+type Fixture struct {
+	// Operation is the GraphQL operation name (e.g. "DictionaryQuery")
+	Operation string `json:"operation"`
+
+	// Arguments select the requests the fixture answers
+	Arguments map[string]any `json:"arguments,omitempty"`
+
+	// Data is the response data
+	Data map[string]any `json:"data,omitempty"`
+
+	// Errors are the GraphQL errors of the response
+	Errors []Error `json:"errors,omitempty"`
+
+	// Status is the HTTP status of the response (default: 200)
+	Status int `json:"status,omitempty"`
+}
+
+// Error is a GraphQL error of a fixture
+type Error struct {
+	Message    string         `json:"message"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// matches reports whether the fixture answers an operation with the given arguments
+func (f *Fixture) matches(operation string, arguments map[string]any) bool {
+	if f.Operation != operation {
+		return false
+	}
+	for name, value := range f.Arguments {
+		if !reflect.DeepEqual(normalize(value), arguments[name]) {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadFixtures reads the fixtures of the JSON files in a directory. A file
+// holds a single fixture or an array of fixtures.
+func LoadFixtures(dir string) ([]Fixture, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var fixtures []Fixture
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		var list []Fixture
+		if strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+			err = json.Unmarshal(data, &list)
+		} else {
+			var fixture Fixture
+			err = json.Unmarshal(data, &fixture)
+			list = []Fixture{fixture}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse fixture %s: %w", file, err)
+		}
+		for _, fixture := range list {
+			if fixture.Operation == "" {
+				return nil, fmt.Errorf("fixture %s has no operation", file)
+			}
+			fixtures = append(fixtures, fixture)
+		}
+	}
+	return fixtures, nil
+}
+
+// operationPattern matches the operation name of a GraphQL document
+var operationPattern = regexp.MustCompile(`^\s*(?:query|mutation|subscription)\s+(\w+)`)
+
+// inlineArgumentPattern matches string arguments written in a query, such as site: "main"
+var inlineArgumentPattern = regexp.MustCompile(`(\w+)\s*:\s*"((?:[^"\\]|\\.)*)"`)
+
+// operationName returns the name of a GraphQL operation, or "query" when it is anonymous
+func operationName(query string) string {
+	if match := operationPattern.FindStringSubmatch(query); match != nil {
+		return match[1]
+	}
+	return "query"
+}
+
+// requestArguments returns the variables and inline string arguments of a
+// request. Variables take precedence over inline arguments of the same name.
+func requestArguments(query string, variables map[string]any) map[string]any {
+	arguments := map[string]any{}
+	for _, match := range inlineArgumentPattern.FindAllStringSubmatch(query, -1) {
+		name := match[1]
+		var value string
+		if err := json.Unmarshal([]byte(`"`+match[2]+`"`), &value); err != nil {
+			value = match[2]
+		}
+		if _, ok := arguments[name]; !ok {
+			arguments[name] = value
+		}
+	}
+	for name, value := range variables {
+		if value != nil {
+			arguments[name] = normalize(value)
+		}
+	}
+	return arguments
+}
+
+// normalize converts a value to its JSON representation, so fixtures and
+// requests compare equal regardless of the Go types they were built from
+func normalize(value any) any {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var normalized any
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return value
+	}
+	return normalized
+}
+
+// toMap converts a value to a JSON object
+func toMap(value any) map[string]any {
+	normalized, _ := normalize(value).(map[string]any)
+	return normalized
+}
+
+// siteInfoData wraps site info fields in the response shape of the site queries
+func siteInfoData(siteInfo map[string]any) map[string]any {
+	return map[string]any{"site": map[string]any{"siteInfo": siteInfo}}
+}
+
+// LayoutFixture answers the layout query of a route. rendered is the layout
+// service data, with the "sitecore" context and route; nil answers not found.
+// An empty language matches any language.
+func LayoutFixture(site, routePath, language string, rendered map[string]any) Fixture {
+	var item any
+	if rendered != nil {
+		item = map[string]any{"rendered": rendered}
+	}
+	arguments := map[string]any{"site": site, "routePath": routePath}
+	if language != "" {
+		arguments["language"] = language
+	}
+	return Fixture{
+		Operation: "ContentSdkLayoutQuery",
+		Arguments: arguments,
+		Data:      map[string]any{"layout": map[string]any{"item": item}},
+	}
+}
+
+// DictionaryFixture answers the dictionary query of a site and language
+func DictionaryFixture(site, language string, phrases map[string]string) Fixture {
+	keys := make([]string, 0, len(phrases))
+	for key := range phrases {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	entries := make([]any, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, map[string]any{"key": key, "value": phrases[key]})
+	}
+	return Fixture{
+		Operation: "DictionaryQuery",
+		Arguments: map[string]any{"site": site, "language": language},
+		Data:      siteInfoData(map[string]any{"dictionary": entries}),
+	}
+}
+
+// SiteInfoFixture answers the site info query of a site
+func SiteInfoFixture(siteInfo models.SiteInfo) Fixture {
+	return Fixture{
+		Operation: "SiteInfoQuery",
+		Arguments: map[string]any{"site": siteInfo.Name},
+		Data:      siteInfoData(toMap(siteInfo)),
+	}
+}
+
+// SitesFixture answers the query listing all sites
+func SitesFixture(sites ...models.SiteInfo) Fixture {
+	collection := make([]any, 0, len(sites))
+	for _, siteInfo := range sites {
+		collection = append(collection, toMap(siteInfo))
+	}
+	return Fixture{
+		Operation: "AllSitesQuery",
+		Data:      map[string]any{"site": map[string]any{"siteInfoCollection": collection}},
+	}
+}
+
+// RedirectsFixture answers the redirects query of a site
+func RedirectsFixture(site string, redirects ...models.RedirectInfo) Fixture {
+	list := make([]any, 0, len(redirects))
+	for _, redirect := range redirects {
+		list = append(list, toMap(redirect))
+	}
+	return Fixture{
+		Operation: "RedirectsQuery",
+		Arguments: map[string]any{"site": site},
+		Data:      siteInfoData(map[string]any{"redirects": list}),
+	}
+}
+
+// SitemapRoute is a route of a sitemap fixture
+type SitemapRoute struct {
+	Path         string `json:"path"`
+	Template     string `json:"template,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// SitemapFixture answers the sitemap query of a site and language
+func SitemapFixture(site, language string, routes ...SitemapRoute) Fixture {
+	list := make([]any, 0, len(routes))
+	for _, route := range routes {
+		list = append(list, toMap(route))
+	}
+	return Fixture{
+		Operation: "SitemapQuery",
+		Arguments: map[string]any{"site": site, "language": language},
+		Data:      siteInfoData(map[string]any{"routes": list}),
+	}
+}
+
+// RobotsFixture answers the robots query of a site
+func RobotsFixture(site string, robots models.RobotsDirective) Fixture {
+	return Fixture{
+		Operation: "RobotsQuery",
+		Arguments: map[string]any{"site": site},
+		Data:      siteInfoData(map[string]any{"robots": toMap(robots)}),
+	}
+}
+
+// ErrorPagesFixture answers the error pages query of a site. The pages are
+// layout service data; nil leaves a page unconfigured.
+func ErrorPagesFixture(site string, notFound, serverError map[string]any) Fixture {
+	page := func(rendered map[string]any) any {
+		if rendered == nil {
+			return nil
+		}
+		return map[string]any{"rendered": rendered}
+	}
+	return Fixture{
+		Operation: "ErrorPagesQuery",
+		Arguments: map[string]any{"site": site},
+		Data: siteInfoData(map[string]any{"errorHandling": map[string]any{
+			"notFoundPage":    page(notFound),
+			"serverErrorPage": page(serverError),
+		}}),
+	}
+}
+
+// ItemLanguagesFixture answers the language versions query of an item
+func ItemLanguagesFixture(itemID string, languages ...string) Fixture {
+	versions := make([]any, len(languages))
+	for i, language := range languages {
+		versions[i] = map[string]any{"language": map[string]any{"name": language}}
+	}
+	return Fixture{
+		Operation: "ItemLanguagesQuery",
+		Arguments: map[string]any{"path": itemID},
+		Data:      map[string]any{"item": map[string]any{"languages": versions}},
+	}
+}
@@ -0,0 +1,326 @@
+// Package sitecoretest provides a mock Experience Edge GraphQL server for tests.
+//
+// The server answers the SDK's layout, dictionary, site info, redirects,
+// sitemap, robots and error page queries from fixtures, so integration tests
+// run against real-looking data without network access. Fixtures are built
+// with helpers such as LayoutFixture, or loaded from JSON files.
+//
+// In Record mode the server forwards requests to a real Edge endpoint and
+// writes each response to the fixtures directory; Replay mode, the default,
+// answers from the recorded files:
+//
+//	mode := sitecoretest.Replay
+//	if os.Getenv("SITECORE_RECORD") != "" {
+//		mode = sitecoretest.Record
+//	}
+//	server := sitecoretest.NewServer(t, sitecoretest.Options{
+//		FixturesDir: "testdata/edge",
+//		Mode:        mode,
+//		Upstream:    os.Getenv("SITECORE_EDGE_URL"),
+//	})
+//	layout := layoutservice.NewLayoutServiceWithClient(layoutservice.LayoutServiceConfig{}, server.GraphQLClient())
+package sitecoretest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/guitarrich/content-sdk-go/graphql"
+)
+
+// EndpointPath is the GraphQL path served by the mock server
+const EndpointPath = "/sitecore/api/graph/edge"
+
+// Mode selects how the server answers requests
+type Mode int
+
+const (
+	// Replay answers requests from fixtures
+	Replay Mode = iota
+
+	// Record forwards requests to the upstream endpoint and saves the responses as fixtures
+	Record
+)
+
+// Options contains configuration for the mock server
+type Options struct {
+	// FixturesDir is the directory fixtures are loaded from and recorded to (optional)
+	FixturesDir string
+
+	// Fixtures are added to the fixtures loaded from FixturesDir
+	Fixtures []Fixture
+
+	// Mode is Replay or Record (default: Replay)
+	Mode Mode
+
+	// Upstream is the GraphQL endpoint recorded from, e.g. an Edge URL with sitecoreContextId
+	Upstream string
+
+	// APIKey is sent as sc_apikey to a non-Edge upstream (optional)
+	APIKey string
+
+	// HTTPClient sends recorded requests upstream (default: client with a 30s timeout)
+	HTTPClient *http.Client
+}
+
+// Request is a GraphQL request received by the server
+type Request struct {
+	// Operation is the GraphQL operation name
+	Operation string
+
+	// Arguments are the variables and inline string arguments of the request
+	Arguments map[string]any
+
+	// Query is the GraphQL document
+	Query string
+
+	// Matched reports whether a fixture answered the request
+	Matched bool
+}
+
+// Server is a mock Experience Edge GraphQL server
+type Server struct {
+	options Options
+	server  *httptest.Server
+
+	mu       sync.Mutex
+	fixtures []Fixture
+	requests []Request
+}
+
+// NewServer starts a mock server that is closed when the test ends. Invalid
+// fixture files and a Record mode without upstream fail the test.
+func NewServer(t testing.TB, options Options) *Server {
+	t.Helper()
+
+	if options.Mode == Record && options.Upstream == "" {
+		t.Fatal("sitecoretest: Record mode requires an upstream endpoint")
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	s := &Server{options: options}
+	if options.FixturesDir != "" && options.Mode == Replay {
+		fixtures, err := LoadFixtures(options.FixturesDir)
+		if err != nil {
+			t.Fatalf("sitecoretest: %v", err)
+		}
+		s.fixtures = fixtures
+	}
+	s.fixtures = append(s.fixtures, options.Fixtures...)
+
+	s.server = httptest.NewServer(s)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Endpoint returns the GraphQL endpoint of the server
+func (s *Server) Endpoint() string {
+	return s.server.URL + EndpointPath
+}
+
+// GraphQLClient returns a GraphQL client for the server. It doesn't retry,
+// so a missing fixture fails fast.
+func (s *Server) GraphQLClient() graphql.Client {
+	return graphql.NewClient(s.Endpoint(), "", nil, &graphql.ClientConfig{Timeout: 10 * time.Second})
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Add adds fixtures to the server
+func (s *Server) Add(fixtures ...Fixture) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fixtures = append(s.fixtures, fixtures...)
+}
+
+// Requests returns the requests received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// ServeHTTP answers a GraphQL request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var request struct {
+		Query     string         `json:"query"`
+		Variables map[string]any `json:"variables"`
+	}
+	if err := json.Unmarshal(body, &request); err != nil {
+		http.Error(w, "invalid GraphQL request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	received := Request{
+		Operation: operationName(request.Query),
+		Arguments: requestArguments(request.Query, request.Variables),
+		Query:     request.Query,
+	}
+
+	var fixture *Fixture
+	if s.options.Mode == Record {
+		fixture = s.record(r, body, received)
+	} else {
+		fixture = s.match(received)
+	}
+	received.Matched = fixture != nil
+
+	s.mu.Lock()
+	s.requests = append(s.requests, received)
+	s.mu.Unlock()
+
+	if fixture == nil {
+		fixture = &Fixture{Errors: []Error{{
+			Message: fmt.Sprintf("sitecoretest: no fixture for %s %s", received.Operation, formatArguments(received.Arguments)),
+		}}}
+	}
+	writeFixture(w, fixture)
+}
+
+// match returns the most specific fixture answering a request
+func (s *Server) match(request Request) *Fixture {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var best *Fixture
+	for i := range s.fixtures {
+		fixture := &s.fixtures[i]
+		if fixture.matches(request.Operation, request.Arguments) &&
+			(best == nil || len(fixture.Arguments) > len(best.Arguments)) {
+			best = fixture
+		}
+	}
+	return best
+}
+
+// record forwards a request upstream and saves the response as a fixture
+func (s *Server) record(r *http.Request, body []byte, request Request) *Fixture {
+	fixture := Fixture{Operation: request.Operation, Arguments: request.Arguments}
+
+	upstream, err := http.NewRequestWithContext(r.Context(), http.MethodPost, s.options.Upstream, bytes.NewReader(body))
+	if err != nil {
+		fixture.Status = http.StatusBadGateway
+		fixture.Errors = []Error{{Message: err.Error()}}
+		return &fixture
+	}
+	upstream.Header.Set("Content-Type", "application/json")
+	if s.options.APIKey != "" && !strings.Contains(s.options.Upstream, "sitecoreContextId=") {
+		upstream.Header.Set("sc_apikey", s.options.APIKey)
+	}
+
+	resp, err := s.options.HTTPClient.Do(upstream)
+	if err != nil {
+		// Transport failures aren't recorded
+		fixture.Status = http.StatusBadGateway
+		fixture.Errors = []Error{{Message: err.Error()}}
+		return &fixture
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		fixture.Status = http.StatusBadGateway
+		fixture.Errors = []Error{{Message: err.Error()}}
+		return &fixture
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		fixture.Status = resp.StatusCode
+	}
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		// Responses that aren't GraphQL results are kept as an error message
+		fixture = Fixture{
+			Operation: request.Operation,
+			Arguments: request.Arguments,
+			Status:    resp.StatusCode,
+			Errors:    []Error{{Message: strings.TrimSpace(string(data))}},
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.options.FixturesDir != "" {
+		if err := saveFixture(s.options.FixturesDir, fixture); err != nil {
+			fixture.Status = http.StatusInternalServerError
+			fixture.Errors = []Error{{Message: "sitecoretest: " + err.Error()}}
+		}
+	}
+	s.fixtures = append(s.fixtures, fixture)
+	return &fixture
+}
+
+// saveFixture writes a fixture to a file named after its operation and arguments
+func saveFixture(dir string, fixture Fixture) error {
+	arguments, err := json.Marshal(fixture.Arguments)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(arguments)
+	file := filepath.Join(dir, fixture.Operation+"-"+hex.EncodeToString(sum[:4])+".json")
+
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(data, '\n'), 0o644)
+}
+
+// writeFixture writes a fixture as a GraphQL response
+func writeFixture(w http.ResponseWriter, fixture *Fixture) {
+	response := map[string]any{"data": fixture.Data}
+	if len(fixture.Errors) > 0 {
+		response["errors"] = fixture.Errors
+	}
+
+	status := fixture.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// formatArguments formats arguments in a stable order for error messages
+func formatArguments(arguments map[string]any) string {
+	names := make([]string, 0, len(arguments))
+	for name := range arguments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s=%v", name, arguments[name])
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
@@ -0,0 +1,197 @@
+package sitecoretest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/guitarrich/content-sdk-go/i18n"
+	layoutservice "github.com/guitarrich/content-sdk-go/layoutService"
+	"github.com/guitarrich/content-sdk-go/models"
+	"github.com/guitarrich/content-sdk-go/seo"
+	"github.com/guitarrich/content-sdk-go/site"
+	"github.com/guitarrich/content-sdk-go/sitecoretest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_ReplaysFixtureFiles(t *testing.T) {
+	server := sitecoretest.NewServer(t, sitecoretest.Options{FixturesDir: "testdata/edge"})
+	client := server.GraphQLClient()
+	ctx := context.Background()
+
+	locale := "en"
+	layout := layoutservice.NewLayoutServiceWithClient(layoutservice.LayoutServiceConfig{}, client)
+	data, err := layout.FetchLayoutDataContext(ctx, "/", layoutservice.RouteOptions{Site: "main", Locale: &locale}, nil)
+	require.NoError(t, err)
+	require.NotNil(t, data.Sitecore.Route)
+	assert.Equal(t, "home", data.Sitecore.Route.Name)
+	assert.Equal(t, "HeroBanner", data.Sitecore.Route.Placeholders["headless-main"][0].ComponentName)
+
+	dictionary := i18n.NewDictionaryService(i18n.DictionaryServiceConfig{GraphQLClient: client})
+	phrases, err := dictionary.FetchDictionaryData(ctx, "en", "main")
+	require.NoError(t, err)
+	assert.Equal(t, "Search", phrases["search.placeholder"])
+
+	redirects, err := site.NewRedirectsService(site.RedirectsServiceConfig{GraphQLClient: client}).FetchRedirects(ctx, "main")
+	require.NoError(t, err)
+	assert.Equal(t, []models.RedirectInfo{{Pattern: "/old", Target: "/new", RedirectType: models.Redirect301}}, redirects)
+}
+
+func TestServer_FixtureHelpers(t *testing.T) {
+	server := sitecoretest.NewServer(t, sitecoretest.Options{Fixtures: []sitecoretest.Fixture{
+		sitecoretest.SiteInfoFixture(models.SiteInfo{Name: "main", HostName: "example.com", Language: "en"}),
+		sitecoretest.SitesFixture(
+			models.SiteInfo{Name: "main", HostName: "example.com"},
+			models.SiteInfo{Name: "brand", HostName: "brand.example.com"},
+		),
+		sitecoretest.SitemapFixture("main", "en",
+			sitecoretest.SitemapRoute{Path: "/", LastModified: "2024-01-15"},
+			sitecoretest.SitemapRoute{Path: "/about", LastModified: "2024-02-01"},
+		),
+		sitecoretest.RobotsFixture("main", models.RobotsDirective{Content: "User-agent: *\nDisallow: /private"}),
+		sitecoretest.ErrorPagesFixture("main", map[string]any{"sitecore": map[string]any{"route": map[string]any{"name": "404"}}}, nil),
+		sitecoretest.LayoutFixture("main", "/missing", "", nil),
+		sitecoretest.ItemLanguagesFixture("{A1B2}", "en", "fr"),
+	}})
+	client := server.GraphQLClient()
+	ctx := context.Background()
+
+	siteInfo := site.NewSiteInfoService(site.SiteInfoServiceConfig{GraphQLClient: client})
+	info, err := siteInfo.FetchSiteInfo(ctx, "main")
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", info.HostName)
+
+	sites, err := siteInfo.FetchSites(ctx)
+	require.NoError(t, err)
+	assert.Len(t, sites, 2)
+
+	sitemap := seo.NewSitemapXmlService(seo.SitemapXmlServiceConfig{GraphQLClient: client, BaseURL: "https://example.com"})
+	entries, err := sitemap.FetchSitemap(ctx, []string{"main"}, []string{"en"})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "https://example.com/about", entries[1].Loc)
+	assert.Equal(t, "2024-02-01", entries[1].LastMod)
+
+	robots, err := seo.NewRobotsService(seo.RobotsServiceConfig{GraphQLClient: client}).FetchRobotsDirectives(ctx, "main")
+	require.NoError(t, err)
+	assert.Contains(t, robots.Content, "Disallow: /private")
+
+	errorPages, err := seo.NewErrorPagesService(seo.ErrorPagesServiceConfig{GraphQLClient: client}).FetchErrorPages(ctx, "main")
+	require.NoError(t, err)
+	assert.NotNil(t, errorPages.NotFoundPage)
+	assert.Nil(t, errorPages.ServerErrorPage)
+
+	urls := site.NewURLService(site.URLServiceConfig{GraphQLClient: client})
+	alternates, err := urls.Alternates(ctx, *info, "{A1B2}", "/about", "en")
+	require.NoError(t, err)
+	assert.Equal(t, []site.AlternateURL{
+		{Language: "en", URL: "https://example.com/about"},
+		{Language: "fr", URL: "https://example.com/fr/about"},
+	}, alternates)
+
+	// A layout fixture without language answers every language
+	locale := "fr"
+	layout := layoutservice.NewLayoutServiceWithClient(layoutservice.LayoutServiceConfig{}, client)
+	data, err := layout.FetchLayoutDataContext(ctx, "/missing", layoutservice.RouteOptions{Site: "main", Locale: &locale}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, data.Sitecore.Route)
+}
+
+func TestServer_MostSpecificFixtureWins(t *testing.T) {
+	server := sitecoretest.NewServer(t, sitecoretest.Options{})
+	server.Add(
+		sitecoretest.Fixture{
+			Operation: "DictionaryQuery",
+			Data:      map[string]any{"site": map[string]any{"siteInfo": map[string]any{"dictionary": []any{}}}},
+		},
+		sitecoretest.DictionaryFixture("main", "de", map[string]string{"greeting": "Hallo"}),
+	)
+	dictionary := i18n.NewDictionaryService(i18n.DictionaryServiceConfig{GraphQLClient: server.GraphQLClient()})
+
+	phrases, err := dictionary.FetchDictionaryData(context.Background(), "de", "main")
+	require.NoError(t, err)
+	assert.Equal(t, "Hallo", phrases["greeting"])
+
+	phrases, err = dictionary.FetchDictionaryData(context.Background(), "en", "main")
+	require.NoError(t, err)
+	assert.Empty(t, phrases)
+}
+
+func TestServer_MissingFixture(t *testing.T) {
+	server := sitecoretest.NewServer(t, sitecoretest.Options{})
+
+	_, err := server.GraphQLClient().Request(context.Background(),
+		`query RedirectsQuery { site { siteInfo(site: "main") { redirects { pattern } } } }`, nil)
+	assert.ErrorContains(t, err, "no fixture for RedirectsQuery {site=main}")
+
+	requests := server.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "RedirectsQuery", requests[0].Operation)
+	assert.False(t, requests[0].Matched)
+}
+
+func TestServer_MatchesVariables(t *testing.T) {
+	server := sitecoretest.NewServer(t, sitecoretest.Options{Fixtures: []sitecoretest.Fixture{{
+		Operation: "ItemQuery",
+		Arguments: map[string]any{"path": "/sitecore/content/home", "first": 10},
+		Data:      map[string]any{"item": map[string]any{"name": "home"}},
+	}}})
+
+	result, err := server.GraphQLClient().Request(context.Background(),
+		`query ItemQuery($path: String!, $first: Int) { item(path: $path) { name } }`,
+		map[string]any{"path": "/sitecore/content/home", "first": 10})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"item": map[string]any{"name": "home"}}, result)
+}
+
+func TestServer_RecordThenReplay(t *testing.T) {
+	var upstreamKey string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamKey = r.Header.Get("sc_apikey")
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"site": map[string]any{
+			"siteInfo": map[string]any{"dictionary": []any{map[string]any{"key": "greeting", "value": "Hello"}}},
+		}}})
+	}))
+	defer upstream.Close()
+	dir := t.TempDir()
+
+	recorder := sitecoretest.NewServer(t, sitecoretest.Options{
+		FixturesDir: dir,
+		Mode:        sitecoretest.Record,
+		Upstream:    upstream.URL,
+		APIKey:      "secret",
+	})
+	dictionary := i18n.NewDictionaryService(i18n.DictionaryServiceConfig{GraphQLClient: recorder.GraphQLClient()})
+	phrases, err := dictionary.FetchDictionaryData(context.Background(), "en", "main")
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", phrases["greeting"])
+	assert.Equal(t, "secret", upstreamKey)
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Regexp(t, `^DictionaryQuery-[0-9a-f]{8}\.json$`, files[0].Name())
+
+	// Replay from the recorded file without the upstream
+	upstream.Close()
+	replay := sitecoretest.NewServer(t, sitecoretest.Options{FixturesDir: dir})
+	dictionary = i18n.NewDictionaryService(i18n.DictionaryServiceConfig{GraphQLClient: replay.GraphQLClient()})
+	phrases, err = dictionary.FetchDictionaryData(context.Background(), "en", "main")
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", phrases["greeting"])
+
+	_, err = dictionary.FetchDictionaryData(context.Background(), "fr", "main")
+	assert.Error(t, err)
+}
+
+func TestLoadFixtures_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(dir+"/bad.json", []byte(`{"data": {}}`), 0o644))
+
+	_, err := sitecoretest.LoadFixtures(dir)
+	assert.ErrorContains(t, err, "has no operation")
+}
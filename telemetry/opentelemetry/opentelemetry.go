@@ -0,0 +1,227 @@
+// Package opentelemetry is the OpenTelemetry provider of the telemetry package.
+//
+//	opentelemetry.Enable(opentelemetry.Config{
+//		TracerProvider: tracerProvider,
+//		MeterProvider:  meterProvider,
+//	})
+//
+// Only applications that import this package build against OpenTelemetry.
+package opentelemetry
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/guitarrich/content-sdk-go/telemetry"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName is the name of the SDK's tracer and meter
+const InstrumentationName = "github.com/guitarrich/content-sdk-go"
+
+// Config contains the OpenTelemetry providers used by the SDK.
+// Nil providers default to the global OpenTelemetry providers.
+type Config struct {
+	// TracerProvider creates the SDK tracer
+	TracerProvider trace.TracerProvider
+
+	// MeterProvider creates the SDK meter
+	MeterProvider metric.MeterProvider
+
+	// Propagator injects the trace context into Edge requests
+	// (default: W3C trace context and baggage)
+	Propagator propagation.TextMapPropagator
+}
+
+// provider implements telemetry.Provider with OpenTelemetry instruments
+type provider struct {
+	tracer       trace.Tracer
+	propagator   propagation.TextMapPropagator
+	edgeLatency  metric.Float64Histogram
+	renderTime   metric.Float64Histogram
+	cacheLookups metric.Int64Counter
+	redirectHits metric.Int64Counter
+	edgeAttempts metric.Int64Histogram
+}
+
+// Enable turns on SDK instrumentation with the given providers
+func Enable(config Config) error {
+	p, err := NewProvider(config)
+	if err != nil {
+		return err
+	}
+	telemetry.SetProvider(p)
+	return nil
+}
+
+// NewProvider creates a telemetry.Provider from OpenTelemetry providers
+func NewProvider(config Config) (telemetry.Provider, error) {
+	if config.TracerProvider == nil {
+		config.TracerProvider = otel.GetTracerProvider()
+	}
+	if config.MeterProvider == nil {
+		config.MeterProvider = otel.GetMeterProvider()
+	}
+	if config.Propagator == nil {
+		config.Propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+	}
+
+	meter := config.MeterProvider.Meter(InstrumentationName)
+	p := &provider{
+		tracer:     config.TracerProvider.Tracer(InstrumentationName),
+		propagator: config.Propagator,
+	}
+
+	var err error
+	if p.edgeLatency, err = meter.Float64Histogram("content_sdk.edge.duration",
+		metric.WithDescription("Duration of Experience Edge GraphQL requests"),
+		metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	if p.edgeAttempts, err = meter.Int64Histogram("content_sdk.edge.attempts",
+		metric.WithDescription("Attempts per Experience Edge GraphQL request, including retries"),
+		metric.WithUnit("{attempt}")); err != nil {
+		return nil, err
+	}
+	if p.renderTime, err = meter.Float64Histogram("content_sdk.render.duration",
+		metric.WithDescription("Duration of page and component renders"),
+		metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	if p.cacheLookups, err = meter.Int64Counter("content_sdk.cache.lookups",
+		metric.WithDescription("Cache lookups by cache and result (hit or miss); the hit ratio is hits / lookups"),
+		metric.WithUnit("{lookup}")); err != nil {
+		return nil, err
+	}
+	if p.redirectHits, err = meter.Int64Counter("content_sdk.redirect.hits",
+		metric.WithDescription("Requests redirected by the redirects middleware"),
+		metric.WithUnit("{redirect}")); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// span adapts an OpenTelemetry span to telemetry.Span
+type span struct {
+	trace.Span
+}
+
+// SetAttributes adds attributes to the span
+func (s span) SetAttributes(attrs ...slog.Attr) {
+	s.Span.SetAttributes(attributes(attrs)...)
+}
+
+// RecordError records err and sets the error status
+func (s span) RecordError(err error) {
+	s.Span.RecordError(err)
+	s.Span.SetStatus(codes.Error, err.Error())
+}
+
+// End ends the span
+func (s span) End() {
+	s.Span.End()
+}
+
+// StartSpan starts a span
+func (p *provider) StartSpan(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, telemetry.Span) {
+	ctx, s := p.tracer.Start(ctx, name, trace.WithAttributes(attributes(attrs)...))
+	return ctx, span{s}
+}
+
+// SpanFromContext returns the current span of ctx
+func (p *provider) SpanFromContext(ctx context.Context) telemetry.Span {
+	return span{trace.SpanFromContext(ctx)}
+}
+
+// ContextWithSpan returns a copy of ctx with span as the current span
+func (p *provider) ContextWithSpan(ctx context.Context, s telemetry.Span) context.Context {
+	if s, ok := s.(span); ok {
+		return trace.ContextWithSpan(ctx, s.Span)
+	}
+	return ctx
+}
+
+// InjectHeaders adds the trace context of ctx to outgoing request headers
+func (p *provider) InjectHeaders(ctx context.Context, header http.Header) {
+	p.propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// RecordEdgeRequest records the duration and attempts of an Edge GraphQL request
+func (p *provider) RecordEdgeRequest(ctx context.Context, operation string, duration time.Duration, attempts int, err error) {
+	attrs := metric.WithAttributes(
+		attribute.String("graphql.operation.name", operation),
+		attribute.String("status", status(err)),
+	)
+	p.edgeLatency.Record(ctx, duration.Seconds(), attrs)
+	p.edgeAttempts.Record(ctx, int64(attempts), attrs)
+}
+
+// RecordCacheLookup records a cache hit or miss
+func (p *provider) RecordCacheLookup(ctx context.Context, cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	p.cacheLookups.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("cache", cache),
+		attribute.String("result", result),
+	))
+}
+
+// RecordRedirectHit records a redirect
+func (p *provider) RecordRedirectHit(ctx context.Context, site, redirectType string) {
+	p.redirectHits.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("site", site),
+		attribute.String("redirect.type", redirectType),
+	))
+}
+
+// RecordRender records the duration of a render
+func (p *provider) RecordRender(ctx context.Context, name string, duration time.Duration, err error) {
+	p.renderTime.Record(ctx, duration.Seconds(), metric.WithAttributes(
+		attribute.String("render.name", name),
+		attribute.String("status", status(err)),
+	))
+}
+
+// status returns the status attribute value of an operation
+func status(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+// attributes converts slog attributes to OpenTelemetry attributes
+func attributes(attrs []slog.Attr) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		value := attr.Value.Resolve()
+		switch value.Kind() {
+		case slog.KindString:
+			kvs = append(kvs, attribute.String(attr.Key, value.String()))
+		case slog.KindInt64:
+			kvs = append(kvs, attribute.Int64(attr.Key, value.Int64()))
+		case slog.KindUint64:
+			kvs = append(kvs, attribute.Int64(attr.Key, int64(value.Uint64())))
+		case slog.KindFloat64:
+			kvs = append(kvs, attribute.Float64(attr.Key, value.Float64()))
+		case slog.KindBool:
+			kvs = append(kvs, attribute.Bool(attr.Key, value.Bool()))
+		case slog.KindDuration:
+			kvs = append(kvs, attribute.Float64(attr.Key, value.Duration().Seconds()))
+		default:
+			kvs = append(kvs, attribute.String(attr.Key, fmt.Sprint(value.Any())))
+		}
+	}
+	return kvs
+}
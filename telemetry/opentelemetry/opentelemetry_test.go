@@ -0,0 +1,198 @@
+package opentelemetry_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/guitarrich/content-sdk-go/graphql"
+	"github.com/guitarrich/content-sdk-go/middleware"
+	"github.com/guitarrich/content-sdk-go/telemetry"
+	"github.com/guitarrich/content-sdk-go/telemetry/opentelemetry"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// setup enables telemetry with an in-memory span exporter and a manual metric reader
+func setup(t *testing.T) (*tracetest.InMemoryExporter, *sdkmetric.ManualReader) {
+	exporter := tracetest.NewInMemoryExporter()
+	reader := sdkmetric.NewManualReader()
+
+	err := opentelemetry.Enable(opentelemetry.Config{
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)),
+		MeterProvider:  sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	})
+	require.NoError(t, err)
+	t.Cleanup(telemetry.Disable)
+
+	return exporter, reader
+}
+
+// findMetric returns the metric with the given name
+func findMetric(t *testing.T, reader *sdkmetric.ManualReader, name string) metricdata.Metrics {
+	var data metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &data))
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name == name {
+				return m
+			}
+		}
+	}
+	t.Fatalf("metric %s not recorded", name)
+	return metricdata.Metrics{}
+}
+
+// spanAttribute returns an attribute of a span
+func spanAttribute(span tracetest.SpanStub, key string) attribute.Value {
+	for _, attr := range span.Attributes {
+		if string(attr.Key) == key {
+			return attr.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestGraphQLRequest_SpanAndTraceContext(t *testing.T) {
+	exporter, reader := setup(t)
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.Write([]byte(`{"data":{"item":{"name":"home"}}}`))
+	}))
+	defer server.Close()
+
+	client := graphql.NewClient(server.URL, "", nil, nil)
+	_, err := client.Request(context.Background(), `query ItemQuery($path: String!) { item(path: $path) { name } }`, nil)
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	span := spans[0]
+
+	assert.Equal(t, "graphql ItemQuery", span.Name)
+	assert.Equal(t, "ItemQuery", spanAttribute(span, "graphql.operation.name").AsString())
+	assert.Equal(t, int64(1), spanAttribute(span, "graphql.attempts").AsInt64())
+	assert.Equal(t, int64(200), spanAttribute(span, "http.response.status_code").AsInt64())
+
+	// The W3C trace context of the span is forwarded to Edge
+	assert.Contains(t, traceparent, span.SpanContext.TraceID().String())
+
+	latency := findMetric(t, reader, "content_sdk.edge.duration").Data.(metricdata.Histogram[float64])
+	require.Len(t, latency.DataPoints, 1)
+	assert.Equal(t, uint64(1), latency.DataPoints[0].Count)
+}
+
+func TestGraphQLRequest_RetriesRecorded(t *testing.T) {
+	exporter, _ := setup(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := graphql.NewClient(server.URL, "", nil, &graphql.ClientConfig{Retries: 1})
+	_, err := client.Request(context.Background(), `{ item { name } }`, nil)
+	require.Error(t, err)
+
+	span := exporter.GetSpans()[0]
+	assert.Equal(t, "graphql query", span.Name)
+	assert.Equal(t, int64(2), spanAttribute(span, "graphql.attempts").AsInt64())
+	assert.Equal(t, int64(502), spanAttribute(span, "http.response.status_code").AsInt64())
+	assert.Equal(t, "Error", span.Status.Code.String())
+}
+
+func TestChain_MiddlewareSpans(t *testing.T) {
+	exporter, _ := setup(t)
+
+	outer := middleware.NewRequestLogger(middleware.RequestLoggerConfig{})
+	inner := middleware.MiddlewareFunc(func(ctx middleware.Context, next middleware.HandlerFunc) error {
+		return next(ctx)
+	})
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/about", nil)
+	ctx := middleware.NewEchoContext(e.NewContext(req, httptest.NewRecorder()))
+
+	err := middleware.Chain(outer, inner).Handle(ctx, func(ctx middleware.Context) error {
+		_, span := telemetry.StartSpan(ctx.Request().Context(), "handler")
+		span.End()
+		return nil
+	})
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+	handler, innerSpan, outerSpan := spans[0], spans[1], spans[2]
+
+	assert.Equal(t, "middleware *middleware.RequestLogger", outerSpan.Name)
+	assert.Equal(t, "middleware middleware.MiddlewareFunc", innerSpan.Name)
+	assert.Equal(t, outerSpan.SpanContext.SpanID(), innerSpan.Parent.SpanID())
+	assert.Equal(t, innerSpan.SpanContext.SpanID(), handler.Parent.SpanID())
+}
+
+func TestInstrumentComponent(t *testing.T) {
+	exporter, reader := setup(t)
+
+	component := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, "<p>hello</p>")
+		return err
+	})
+
+	err := telemetry.InstrumentComponent("page", component).Render(context.Background(), io.Discard)
+	require.NoError(t, err)
+
+	require.Len(t, exporter.GetSpans(), 1)
+	assert.Equal(t, "render page", exporter.GetSpans()[0].Name)
+
+	renders := findMetric(t, reader, "content_sdk.render.duration").Data.(metricdata.Histogram[float64])
+	require.Len(t, renders.DataPoints, 1)
+	name, _ := renders.DataPoints[0].Attributes.Value("render.name")
+	assert.Equal(t, "page", name.AsString())
+}
+
+func TestCacheAndRedirectMetrics(t *testing.T) {
+	_, reader := setup(t)
+	ctx := context.Background()
+
+	telemetry.RecordCacheLookup(ctx, "media", true)
+	telemetry.RecordCacheLookup(ctx, "media", true)
+	telemetry.RecordCacheLookup(ctx, "media", false)
+	telemetry.RecordRedirectHit(ctx, "main", "REDIRECT_301")
+
+	lookups := findMetric(t, reader, "content_sdk.cache.lookups").Data.(metricdata.Sum[int64])
+	counts := map[string]int64{}
+	for _, point := range lookups.DataPoints {
+		result, _ := point.Attributes.Value("result")
+		counts[result.AsString()] = point.Value
+	}
+	assert.Equal(t, map[string]int64{"hit": 2, "miss": 1}, counts)
+
+	hits := findMetric(t, reader, "content_sdk.redirect.hits").Data.(metricdata.Sum[int64])
+	require.Len(t, hits.DataPoints, 1)
+	assert.Equal(t, int64(1), hits.DataPoints[0].Value)
+}
+
+func TestDisabled(t *testing.T) {
+	exporter, _ := setup(t)
+	telemetry.Disable()
+
+	ctx, span := telemetry.StartSpan(context.Background(), "ignored")
+	telemetry.EndSpan(span, nil)
+	assert.Equal(t, context.Background(), ctx)
+	assert.Empty(t, exporter.GetSpans())
+
+	header := http.Header{}
+	telemetry.InjectHeaders(ctx, header)
+	assert.Empty(t, header.Get("traceparent"))
+}
@@ -0,0 +1,27 @@
+package telemetry
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/a-h/templ"
+)
+
+// InstrumentComponent wraps a templ component so its renders are traced and
+// recorded in the render time histogram under the given name
+func InstrumentComponent(name string, component templ.Component) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if !Enabled() {
+			return component.Render(ctx, w)
+		}
+
+		ctx, span := StartSpan(ctx, "render "+name, slog.String("render.name", name))
+		start := time.Now()
+		err := component.Render(ctx, w)
+		RecordRender(ctx, name, time.Since(start), err)
+		EndSpan(span, err)
+		return err
+	})
+}
@@ -0,0 +1,167 @@
+// Package telemetry provides optional tracing and metrics hooks for the SDK.
+//
+// Instrumentation is disabled until a Provider is set. The SDK then creates
+// spans for middleware, GraphQL requests, layout fetches and renders, forwards
+// the trace context to Experience Edge and records Edge latency, cache lookups,
+// redirect hits and render time through the provider.
+//
+// This package has no OpenTelemetry dependency; the OpenTelemetry provider is
+// in the telemetry/opentelemetry package, so only applications that import it
+// build against OpenTelemetry.
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Span is a span started by a Provider
+type Span interface {
+	// SetAttributes adds attributes to the span
+	SetAttributes(attrs ...slog.Attr)
+
+	// RecordError marks the span as failed with err
+	RecordError(err error)
+
+	// End ends the span
+	End()
+}
+
+// Provider implements the SDK's instrumentation on a tracing and metrics backend
+type Provider interface {
+	// StartSpan starts a span that is the current span of the returned context
+	StartSpan(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, Span)
+
+	// SpanFromContext returns the current span of ctx
+	SpanFromContext(ctx context.Context) Span
+
+	// ContextWithSpan returns a copy of ctx with span as the current span
+	ContextWithSpan(ctx context.Context, span Span) context.Context
+
+	// InjectHeaders adds the trace context of ctx to outgoing request headers
+	InjectHeaders(ctx context.Context, header http.Header)
+
+	// RecordEdgeRequest records the duration and attempts of an Edge GraphQL request
+	RecordEdgeRequest(ctx context.Context, operation string, duration time.Duration, attempts int, err error)
+
+	// RecordCacheLookup records a cache hit or miss
+	RecordCacheLookup(ctx context.Context, cache string, hit bool)
+
+	// RecordRedirectHit records a redirect
+	RecordRedirectHit(ctx context.Context, site, redirectType string)
+
+	// RecordRender records the duration of a render
+	RecordRender(ctx context.Context, name string, duration time.Duration, err error)
+}
+
+// current is nil while instrumentation is disabled
+var current atomic.Pointer[Provider]
+
+// SetProvider turns on instrumentation with the given provider; nil disables it
+func SetProvider(provider Provider) {
+	if provider == nil {
+		current.Store(nil)
+		return
+	}
+	current.Store(&provider)
+}
+
+// Disable turns off instrumentation
+func Disable() {
+	current.Store(nil)
+}
+
+// Enabled reports whether instrumentation is enabled
+func Enabled() bool {
+	return current.Load() != nil
+}
+
+// provider returns the current provider, or nil
+func provider() Provider {
+	if p := current.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// noopSpan is the span returned while instrumentation is disabled
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(attrs ...slog.Attr) {}
+func (noopSpan) RecordError(err error)            {}
+func (noopSpan) End()                             {}
+
+// StartSpan starts a span when instrumentation is enabled. The returned span
+// is a no-op otherwise, so callers can always End it.
+func StartSpan(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, Span) {
+	p := provider()
+	if p == nil {
+		return ctx, noopSpan{}
+	}
+	return p.StartSpan(ctx, name, attrs...)
+}
+
+// SpanFromContext returns the current span of ctx, a no-op span while
+// instrumentation is disabled
+func SpanFromContext(ctx context.Context) Span {
+	p := provider()
+	if p == nil {
+		return noopSpan{}
+	}
+	return p.SpanFromContext(ctx)
+}
+
+// ContextWithSpan returns a copy of ctx with span as the current span
+func ContextWithSpan(ctx context.Context, span Span) context.Context {
+	p := provider()
+	if p == nil {
+		return ctx
+	}
+	return p.ContextWithSpan(ctx, span)
+}
+
+// EndSpan records err on the span, if any, and ends it
+func EndSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// InjectHeaders adds the trace context of ctx to outgoing request headers
+func InjectHeaders(ctx context.Context, header http.Header) {
+	if p := provider(); p != nil {
+		p.InjectHeaders(ctx, header)
+	}
+}
+
+// RecordEdgeRequest records the duration and attempts of an Edge GraphQL request
+func RecordEdgeRequest(ctx context.Context, operation string, duration time.Duration, attempts int, err error) {
+	if p := provider(); p != nil {
+		p.RecordEdgeRequest(ctx, operation, duration, attempts, err)
+	}
+}
+
+// RecordCacheLookup records a cache hit or miss
+func RecordCacheLookup(ctx context.Context, cache string, hit bool) {
+	if p := provider(); p != nil {
+		p.RecordCacheLookup(ctx, cache, hit)
+	}
+}
+
+// RecordRedirectHit records a redirect
+func RecordRedirectHit(ctx context.Context, site, redirectType string) {
+	if p := provider(); p != nil {
+		p.RecordRedirectHit(ctx, site, redirectType)
+	}
+}
+
+// RecordRender records the duration of a render
+func RecordRender(ctx context.Context, name string, duration time.Duration, err error) {
+	if p := provider(); p != nil {
+		p.RecordRender(ctx, name, duration, err)
+	}
+}
@@ -0,0 +1,96 @@
+package telemetry_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/guitarrich/content-sdk-go/telemetry"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingSpan records the calls of a span
+type recordingSpan struct {
+	name  string
+	attrs []slog.Attr
+	err   error
+	ended bool
+}
+
+func (s *recordingSpan) SetAttributes(attrs ...slog.Attr) { s.attrs = append(s.attrs, attrs...) }
+func (s *recordingSpan) RecordError(err error)            { s.err = err }
+func (s *recordingSpan) End()                             { s.ended = true }
+
+// spanKey is the context key of the current recordingSpan
+type spanKey struct{}
+
+// recordingProvider is a telemetry.Provider without a tracing backend
+type recordingProvider struct {
+	spans   []*recordingSpan
+	lookups []string
+}
+
+func (p *recordingProvider) StartSpan(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, telemetry.Span) {
+	span := &recordingSpan{name: name, attrs: attrs}
+	p.spans = append(p.spans, span)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (p *recordingProvider) SpanFromContext(ctx context.Context) telemetry.Span {
+	span, _ := ctx.Value(spanKey{}).(*recordingSpan)
+	return span
+}
+
+func (p *recordingProvider) ContextWithSpan(ctx context.Context, span telemetry.Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+func (p *recordingProvider) InjectHeaders(ctx context.Context, header http.Header) {
+	header.Set("traceparent", p.SpanFromContext(ctx).(*recordingSpan).name)
+}
+
+func (p *recordingProvider) RecordEdgeRequest(ctx context.Context, operation string, duration time.Duration, attempts int, err error) {
+}
+
+func (p *recordingProvider) RecordCacheLookup(ctx context.Context, cache string, hit bool) {
+	p.lookups = append(p.lookups, cache)
+}
+
+func (p *recordingProvider) RecordRedirectHit(ctx context.Context, site, redirectType string) {}
+
+func (p *recordingProvider) RecordRender(ctx context.Context, name string, duration time.Duration, err error) {
+}
+
+func TestSetProvider(t *testing.T) {
+	provider := &recordingProvider{}
+	telemetry.SetProvider(provider)
+	t.Cleanup(telemetry.Disable)
+	assert.True(t, telemetry.Enabled())
+
+	ctx, span := telemetry.StartSpan(context.Background(), "fetch", slog.String("site", "main"))
+	telemetry.SpanFromContext(ctx).SetAttributes(slog.Int("status", 200))
+	header := http.Header{}
+	telemetry.InjectHeaders(ctx, header)
+	telemetry.RecordCacheLookup(ctx, "layout", true)
+	telemetry.EndSpan(span, errors.New("failed"))
+
+	recorded := provider.spans[0]
+	assert.Equal(t, "fetch", recorded.name)
+	assert.Equal(t, []slog.Attr{slog.String("site", "main"), slog.Int("status", 200)}, recorded.attrs)
+	assert.EqualError(t, recorded.err, "failed")
+	assert.True(t, recorded.ended)
+	assert.Equal(t, "fetch", header.Get("traceparent"))
+	assert.Equal(t, []string{"layout"}, provider.lookups)
+
+	// A nil provider disables instrumentation
+	telemetry.SetProvider(nil)
+	assert.False(t, telemetry.Enabled())
+	ctx, span = telemetry.StartSpan(context.Background(), "ignored")
+	telemetry.EndSpan(span, nil)
+	assert.Equal(t, context.Background(), ctx)
+	assert.Equal(t, ctx, telemetry.ContextWithSpan(ctx, span))
+	assert.Len(t, provider.spans, 1)
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// TTLCache is a concurrency-safe map whose entries expire after a fixed duration
+type TTLCache[V any] struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]ttlEntry[V]
+}
+
+// ttlEntry is a cached value and its expiry
+type ttlEntry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+// NewTTLCache creates a cache whose entries expire after ttl
+func NewTTLCache[V any](ttl time.Duration) *TTLCache[V] {
+	return &TTLCache[V]{ttl: ttl, entries: make(map[string]ttlEntry[V])}
+}
+
+// Get returns the unexpired value of a key
+func (c *TTLCache[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+// Set stores a value, dropping expired entries
+func (c *TTLCache[V]) Set(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for k, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = ttlEntry[V]{value: value, expires: now.Add(c.ttl)}
+}
+
+// Clear removes all entries
+func (c *TTLCache[V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]ttlEntry[V])
+}